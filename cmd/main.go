@@ -1,20 +1,43 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/module"
-	"github.com/joho/godotenv"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 )
 
 func main() {
-	err := godotenv.Load("./.env")
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 	if err != nil {
-		log.Fatalf("Error loading env: %v", err)
+		// Flag errors were already printed along with the usage.
+		var invalid *config.Error
+		if errors.As(err, &invalid) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(2)
 	}
 
-	app := fx.New(module.Module())
+	app := fx.New(
+		fx.Supply(cfg),
+		module.Module(),
+		// Leaves room for the HTTP and gRPC servers to drain one after the
+		// other and for the database and tracer to close afterwards.
+		fx.StopTimeout(cfg.GRPC.ShutdownTimeout+cfg.HTTP.ShutdownTimeout+10*time.Second),
+		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
+			return &fxevent.SlogLogger{Logger: logger}
+		}),
+	)
 
 	app.Run()
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package dtos
+
+import "time"
+
+type SetAudienceKeyDTO struct {
+	Audience  string `json:"audience"`
+	PublicKey string `json:"public_key"`
+}
+
+type AudienceKeyDTO struct {
+	Audience  string    `json:"audience"`
+	KeyID     string    `json:"key_id"`
+	Algorithm string    `json:"algorithm"`
+	CreatedAt time.Time `json:"created_at"`
+}
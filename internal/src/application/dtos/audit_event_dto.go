@@ -0,0 +1,65 @@
+package dtos
+
+import "time"
+
+type QueryAuditEventsDTO struct {
+	UserID   string     `json:"user_id,omitempty"`
+	Types    []string   `json:"types,omitempty"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+	PageSize int        `json:"page_size,omitempty"`
+	Cursor   string     `json:"cursor,omitempty"`
+}
+
+type AuditEventDTO struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	ActorID    string                 `json:"actor_id,omitempty"`
+	SubjectID  string                 `json:"subject_id,omitempty"`
+	ClientID   string                 `json:"client_id,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Reason     string                 `json:"reason,omitempty"`
+	PeerIP     string                 `json:"peer_ip,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+type QueryAuditEventsResponseDTO struct {
+	Events     []AuditEventDTO `json:"events"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type VerifyAuditChainDTO struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+type AuditChainProblemDTO struct {
+	Sequence int64  `json:"sequence"`
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+}
+
+type AuditChainReportDTO struct {
+	Valid               bool                   `json:"valid"`
+	FirstSequence       int64                  `json:"first_sequence"`
+	LastSequence        int64                  `json:"last_sequence"`
+	EventsChecked       int64                  `json:"events_checked"`
+	CheckpointsVerified int64                  `json:"checkpoints_verified"`
+	Problems            []AuditChainProblemDTO `json:"problems"`
+}
+
+type WatchAuthEventsDTO struct {
+	Types    []string `json:"types,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+	Cursor   string   `json:"cursor,omitempty"`
+}
+
+// AuthEventDTO is an audit event pushed to a watcher. Cursor resumes the
+// stream right after it.
+type AuthEventDTO struct {
+	Event    AuditEventDTO `json:"event"`
+	TenantID string        `json:"tenant_id,omitempty"`
+	Cursor   string        `json:"cursor"`
+}
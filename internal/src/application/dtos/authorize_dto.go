@@ -0,0 +1,25 @@
+package dtos
+
+type AuthorizeRequestDTO struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	Nonce               string `json:"nonce"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+type AuthorizeDTO struct {
+	Request     AuthorizeRequestDTO `json:"request"`
+	Credentials LoginDTO            `json:"credentials"`
+}
+
+// AuthorizeResponseDTO tells the HTTP layer where to send the user agent.
+// RedirectURI is empty when the request is valid and the login form should
+// be shown instead.
+type AuthorizeResponseDTO struct {
+	ClientName  string `json:"client_name"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
@@ -0,0 +1,7 @@
+package dtos
+
+type ChangePasswordDTO struct {
+	UserID          string `json:"user_id"`
+	CurrentPassword string `json:"-"`
+	NewPassword     string `json:"-"`
+}
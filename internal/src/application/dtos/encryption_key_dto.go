@@ -0,0 +1,32 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type RotateEncryptionKeyDTO struct {
+	Algorithm         string `json:"algorithm"`
+	ActivateInSeconds int    `json:"activate_in_seconds"`
+}
+
+type ListEncryptionKeysDTO struct {
+	InUseOnly bool `json:"in_use_only,omitempty"`
+}
+
+type EncryptionKeyDTO struct {
+	KeyID       string                    `json:"key_id"`
+	Algorithm   string                    `json:"algorithm"`
+	State       models.EncryptionKeyState `json:"state"`
+	InUse       bool                      `json:"in_use"`
+	ActivatesAt time.Time                 `json:"activates_at"`
+	ExpiresAt   *time.Time                `json:"expires_at,omitempty"`
+	RemoveAt    *time.Time                `json:"remove_at,omitempty"`
+	LastUsedAt  *time.Time                `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+}
+
+type ListEncryptionKeysResponseDTO struct {
+	Keys []EncryptionKeyDTO `json:"keys"`
+}
@@ -6,6 +6,7 @@ type LoginDTO struct {
 	IdentifierType  models.IdentifierType `json:"identifier_type"`
 	IdentifierValue string              `json:"identifier_value"`
 	Password        string              `json:"password"`
+	Audience        []string            `json:"audience,omitempty"`
 }
 
 type LoginResponseDTO struct {
@@ -0,0 +1,14 @@
+package dtos
+
+type RegisterClientDTO struct {
+	Name                string   `json:"name"`
+	RedirectURIs        []string `json:"redirect_uris"`
+	Public              bool     `json:"public"`
+	EncryptTokens       bool     `json:"encrypt_tokens"`
+	EncryptionPublicKey string   `json:"encryption_public_key,omitempty"`
+}
+
+type RegisterClientResponseDTO struct {
+	ClientID     string  `json:"client_id"`
+	ClientSecret *string `json:"client_secret,omitempty"`
+}
@@ -1,7 +1,8 @@
 package dtos
 
 type RefreshTokenDTO struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string   `json:"refresh_token"`
+	Audience     []string `json:"audience,omitempty"`
 }
 
 type RefreshTokenResponseDTO struct {
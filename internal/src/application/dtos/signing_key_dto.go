@@ -0,0 +1,32 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type RotateSigningKeyDTO struct {
+	Purpose           models.SigningKeyPurpose `json:"purpose"`
+	Algorithm         string                   `json:"algorithm"`
+	ActivateInSeconds int                      `json:"activate_in_seconds"`
+}
+
+type ListSigningKeysDTO struct {
+	Purpose models.SigningKeyPurpose `json:"purpose,omitempty"`
+}
+
+type SigningKeyDTO struct {
+	KeyID       string                   `json:"key_id"`
+	Purpose     models.SigningKeyPurpose `json:"purpose"`
+	Algorithm   string                   `json:"algorithm"`
+	State       models.SigningKeyState   `json:"state"`
+	ActivatesAt time.Time                `json:"activates_at"`
+	ExpiresAt   *time.Time               `json:"expires_at,omitempty"`
+	RemoveAt    *time.Time               `json:"remove_at,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
+
+type ListSigningKeysResponseDTO struct {
+	Keys []SigningKeyDTO `json:"keys"`
+}
@@ -0,0 +1,34 @@
+package dtos
+
+type TokenDTO struct {
+	GrantType          string   `json:"grant_type"`
+	Code               string   `json:"code"`
+	RedirectURI        string   `json:"redirect_uri"`
+	ClientID           string   `json:"client_id"`
+	ClientSecret       string   `json:"client_secret"`
+	CodeVerifier       string   `json:"code_verifier"`
+	Scope              string   `json:"scope"`
+	Audience           []string `json:"audience"`
+	SubjectToken       string   `json:"subject_token"`
+	SubjectTokenType   string   `json:"subject_token_type"`
+	ActorToken         string   `json:"actor_token"`
+	ActorTokenType     string   `json:"actor_token_type"`
+	RequestedTokenType string   `json:"requested_token_type"`
+}
+
+type TokenResponseDTO struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+	IDToken         string `json:"id_token,omitempty"`
+}
+
+type RevokeTokenDTO struct {
+	Token         string `json:"token"`
+	TokenTypeHint string `json:"token_type_hint"`
+	ClientID      string `json:"client_id"`
+	ClientSecret  string `json:"client_secret"`
+}
@@ -1,7 +1,17 @@
 package dtos
 
 type UserInfoDTO struct {
-	UserID string   `json:"user_id"`
-	Name   string   `json:"name"`
-	Roles  []string `json:"roles"`
-}
\ No newline at end of file
+	UserID   string            `json:"user_id"`
+	Name     string            `json:"name"`
+	Roles    []string          `json:"roles"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type UpdateUserInfoDTO struct {
+	UserID             string            `json:"user_id"`
+	Name               *string           `json:"name,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	RemoveMetadataKeys []string          `json:"remove_metadata_keys,omitempty"`
+	AddRoles           []string          `json:"add_roles,omitempty"`
+	RemoveRoles        []string          `json:"remove_roles,omitempty"`
+}
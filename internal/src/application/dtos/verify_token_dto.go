@@ -1,5 +1,7 @@
 package dtos
 
 type VerifyTokenDTO struct {
-	AccessToken string `json:"access_token"`
+	AccessToken      string `json:"access_token"`
+	ExpectedAudience string `json:"expected_audience,omitempty"`
+	ExpectedIssuer   string `json:"expected_issuer,omitempty"`
 }
\ No newline at end of file
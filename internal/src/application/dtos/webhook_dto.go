@@ -0,0 +1,64 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type CreateWebhookSubscriptionDTO struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Secret      string   `json:"-"`
+	MaxAttempts int      `json:"max_attempts,omitempty"`
+}
+
+type WebhookSubscriptionDTO struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"event_types"`
+	MaxAttempts int       `json:"max_attempts"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"-"`
+}
+
+type ListWebhookSubscriptionsDTO struct{}
+
+type ListWebhookSubscriptionsResponseDTO struct {
+	Subscriptions []WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+type DeleteWebhookSubscriptionDTO struct {
+	ID string `json:"id"`
+}
+
+type ListWebhookDeliveriesDTO struct {
+	SubscriptionID string                       `json:"subscription_id"`
+	Status         models.WebhookDeliveryStatus `json:"status,omitempty"`
+	PageSize       int                          `json:"page_size,omitempty"`
+	Cursor         string                       `json:"cursor,omitempty"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID             string                       `json:"id"`
+	SubscriptionID string                       `json:"subscription_id"`
+	EventID        string                       `json:"event_id"`
+	EventType      string                       `json:"event_type"`
+	Status         models.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	NextAttemptAt  time.Time                    `json:"next_attempt_at"`
+	LastStatusCode int                          `json:"last_status_code,omitempty"`
+	LastError      string                       `json:"last_error,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	DeliveredAt    *time.Time                   `json:"delivered_at,omitempty"`
+}
+
+type ListWebhookDeliveriesResponseDTO struct {
+	Deliveries []WebhookDeliveryDTO `json:"deliveries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+type RetryWebhookDeliveryDTO struct {
+	DeliveryID string `json:"delivery_id"`
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/logging"
+)
+
+const (
+	auditEventLogin          = "login"
+	auditEventRegister       = "register"
+	auditEventRefresh        = "token_refresh"
+	auditEventDeleteAuth     = "delete_auth"
+	auditEventPasswordChange = "password_change"
+	auditEventLockout        = "account_lockout"
+	auditEventLogout         = "logout"
+	auditEventRoleChange     = "role_change"
+)
+
+// tenantMetadataKey is the user metadata key naming the tenant a user
+// belongs to.
+const tenantMetadataKey = "org_id"
+
+// recordAudit completes event with the result of the action it describes
+// and records it. The error message becomes the reason of a failure.
+func recordAudit(ctx context.Context, auditService services.IAuditService, event services.AuditEvent, err error) {
+	if err != nil {
+		event.Outcome = services.AuditOutcomeFailure
+		if event.Reason == "" {
+			event.Reason = err.Error()
+		}
+	} else {
+		event.Outcome = services.AuditOutcomeSuccess
+	}
+
+	logging.SetUser(ctx, event.SubjectID, event.TenantID)
+	auditService.Record(ctx, event)
+}
+
+// auditTenant returns the tenant recorded with audit events about the user.
+func auditTenant(userInfo models.UserInfo) string {
+	return userInfo.GetMetadata()[tenantMetadataKey]
+}
@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	authEventBatchSize    = 100
+	authEventPollInterval = time.Second
+)
+
+// AuthEventWatcher follows the audit log and pushes its events to streaming
+// consumers as they are stored. Events are read back from the log by chain
+// position, so events stored by other instances are picked up too, and a
+// consumer that reconnects with the cursor of the last event it received
+// misses nothing in between. Callers are authorized by their client
+// certificate before they get here.
+type AuthEventWatcher struct {
+	auditEventRepo repositories.IAuditEventRepository
+	auditService   services.IAuditService
+}
+
+func NewAuthEventWatcher(
+	auditEventRepo repositories.IAuditEventRepository,
+	auditService services.IAuditService,
+) *AuthEventWatcher {
+	return &AuthEventWatcher{
+		auditEventRepo: auditEventRepo,
+		auditService:   auditService,
+	}
+}
+
+// Watch sends the events after props.Cursor that match the filters, then
+// keeps sending new ones until ctx is done or send fails. Without a cursor
+// only events stored from now on are sent; cursor "0" starts at the oldest
+// event kept.
+func (w *AuthEventWatcher) Watch(ctx context.Context, props dtos.WatchAuthEventsDTO, send func(dtos.AuthEventDTO) error) error {
+	after, err := w.resolveCursor(ctx, props.Cursor)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(authEventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Taken before reading so an event stored meanwhile still wakes the
+		// loop.
+		recorded := w.auditService.Recorded()
+
+		events, err := w.auditEventRepo.ListBySequence(ctx, after+1, math.MaxInt64, authEventBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			after = event.GetSequence()
+			if !authEventMatches(event, props) {
+				continue
+			}
+
+			if err := send(authEventToDTO(event)); err != nil {
+				return err
+			}
+		}
+
+		if len(events) == authEventBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-recorded:
+		case <-ticker.C:
+		}
+	}
+}
+
+// resolveCursor returns the chain position to continue after. A cursor that
+// points before events purged by retention is rejected, since the events in
+// between are gone.
+func (w *AuthEventWatcher) resolveCursor(ctx context.Context, cursor string) (int64, error) {
+	first, last, err := w.auditEventRepo.SequenceRange(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if cursor == "" {
+		return last, nil
+	}
+
+	after, er := strconv.ParseInt(cursor, 10, 64)
+	if er != nil || after < 0 {
+		return 0, exceptions.NewBusinessException("invalid cursor")
+	}
+	if after > 0 && first > 0 && after < first-1 {
+		return 0, exceptions.NewBusinessException("cursor has expired: the events after it were purged")
+	}
+
+	return after, nil
+}
+
+func authEventMatches(event models.AuditEvent, props dtos.WatchAuthEventsDTO) bool {
+	if len(props.Types) > 0 && !slices.Contains(props.Types, event.GetType()) {
+		return false
+	}
+	if props.TenantID != "" && authEventTenant(event) != props.TenantID {
+		return false
+	}
+	return true
+}
+
+func authEventTenant(event models.AuditEvent) string {
+	tenantID, _ := event.GetDetails()["tenant_id"].(string)
+	return tenantID
+}
+
+func authEventToDTO(event models.AuditEvent) dtos.AuthEventDTO {
+	return dtos.AuthEventDTO{
+		Event:    auditEventToDTO(event),
+		TenantID: authEventTenant(event),
+		Cursor:   strconv.FormatInt(event.GetSequence(), 10),
+	}
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type validateAuthorizeUsecase struct {
+	clientRepo repositories.IOAuthClientRepository
+}
+
+func NewValidateAuthorizeUsecase(clientRepo repositories.IOAuthClientRepository) usecase.UseCaseWithProps[dtos.AuthorizeRequestDTO, *dtos.AuthorizeResponseDTO] {
+	return &validateAuthorizeUsecase{
+		clientRepo: clientRepo,
+	}
+}
+
+func (uc validateAuthorizeUsecase) Execute(ctx context.Context, props dtos.AuthorizeRequestDTO) (*dtos.AuthorizeResponseDTO, error) {
+	client, redirectErr, err := validateAuthorizeRequest(ctx, uc.clientRepo, props)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.AuthorizeResponseDTO{ClientName: client.GetName()}
+	if redirectErr != nil {
+		response.RedirectURI = errorRedirect(props, redirectErr)
+	}
+
+	return response, nil
+}
+
+type authorizeUsecase struct {
+	cfg          config.AuthConfig
+	clientRepo   repositories.IOAuthClientRepository
+	codeRepo     repositories.IAuthorizationCodeRepository
+	authRepo     repositories.IAuthRepository
+	auditService services.IAuditService
+	metrics      services.IMetrics
+}
+
+func NewAuthorizeUsecase(
+	cfg config.AuthConfig,
+	clientRepo repositories.IOAuthClientRepository,
+	codeRepo repositories.IAuthorizationCodeRepository,
+	authRepo repositories.IAuthRepository,
+	auditService services.IAuditService,
+	metrics services.IMetrics,
+) usecase.UseCaseWithProps[dtos.AuthorizeDTO, *dtos.AuthorizeResponseDTO] {
+	return &authorizeUsecase{
+		cfg:          cfg,
+		clientRepo:   clientRepo,
+		codeRepo:     codeRepo,
+		authRepo:     authRepo,
+		auditService: auditService,
+		metrics:      metrics,
+	}
+}
+
+func (uc authorizeUsecase) Execute(ctx context.Context, props dtos.AuthorizeDTO) (*dtos.AuthorizeResponseDTO, error) {
+	client, redirectErr, err := validateAuthorizeRequest(ctx, uc.clientRepo, props.Request)
+	if err != nil {
+		return nil, err
+	}
+	if redirectErr != nil {
+		return &dtos.AuthorizeResponseDTO{
+			ClientName:  client.GetName(),
+			RedirectURI: errorRedirect(props.Request, redirectErr),
+		}, nil
+	}
+
+	auth, err := authenticate(ctx, uc.authRepo, uc.auditService, uc.metrics, props.Credentials, client.GetClientID())
+	if err != nil {
+		var notFound *exceptions.RepositoryNoDataFoundException
+		if errors.As(err, &notFound) {
+			return nil, exceptions.NewBusinessException("invalid credentials")
+		}
+		return nil, err
+	}
+
+	authTime := time.Now()
+
+	code, er := utils.GenerateRandomToken(32)
+	if er != nil {
+		return nil, exceptions.NewBusinessException("failed to generate authorization code")
+	}
+
+	authorizationCode, er := models.NewAuthorizationCode(models.AuthorizationCodeProps{
+		CodeHash:            utils.HashToken(code),
+		ClientID:            client.GetClientID(),
+		UserID:              auth.GetUserInfo().GetUserID(),
+		RedirectURI:         props.Request.RedirectURI,
+		CodeChallenge:       props.Request.CodeChallenge,
+		CodeChallengeMethod: props.Request.CodeChallengeMethod,
+		Scope:               props.Request.Scope,
+		Nonce:               props.Request.Nonce,
+		AuthTime:            authTime,
+		ExpiresAt:           authTime.Add(uc.cfg.AuthorizationCodeTTL),
+	})
+	if er != nil {
+		return nil, er
+	}
+
+	if err := uc.codeRepo.Save(ctx, authorizationCode); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	if props.Request.State != "" {
+		params.Set("state", props.Request.State)
+	}
+
+	return &dtos.AuthorizeResponseDTO{
+		ClientName:  client.GetName(),
+		RedirectURI: appendQuery(props.Request.RedirectURI, params),
+	}, nil
+}
+
+// validateAuthorizeRequest checks an authorization request in the order
+// mandated by RFC 6749 section 4.1.2.1. Problems with the client or the
+// redirect URI are returned as err and must never cause a redirect; every
+// other problem is returned as redirectErr and is reported back to the client
+// through its redirect URI.
+func validateAuthorizeRequest(ctx context.Context, clientRepo repositories.IOAuthClientRepository, props dtos.AuthorizeRequestDTO) (models.OAuthClient, *models.OAuthError, error) {
+	if props.ClientID == "" {
+		return nil, nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "client_id is required")
+	}
+
+	client, err := clientRepo.GetByClientID(ctx, props.ClientID)
+	if err != nil {
+		var notFound *exceptions.RepositoryNoDataFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "unknown client")
+		}
+		return nil, nil, err
+	}
+
+	if !client.HasRedirectURI(props.RedirectURI) {
+		return nil, nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	if props.ResponseType != "code" {
+		return client, models.NewOAuthError(models.OAuthErrorUnsupportedResponseType, "only the code response type is supported"), nil
+	}
+	if props.CodeChallenge == "" {
+		return client, models.NewOAuthError(models.OAuthErrorInvalidRequest, "code_challenge is required"), nil
+	}
+	if props.CodeChallengeMethod != models.CodeChallengeMethodS256 {
+		return client, models.NewOAuthError(models.OAuthErrorInvalidRequest, "code_challenge_method must be S256"), nil
+	}
+	if !isValidPKCEValue(props.CodeChallenge) {
+		return client, models.NewOAuthError(models.OAuthErrorInvalidRequest, "code_challenge is malformed"), nil
+	}
+
+	return client, nil, nil
+}
+
+func errorRedirect(props dtos.AuthorizeRequestDTO, oauthErr *models.OAuthError) string {
+	params := url.Values{}
+	params.Set("error", oauthErr.Code)
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	if props.State != "" {
+		params.Set("state", props.State)
+	}
+
+	return appendQuery(props.RedirectURI, params)
+}
+
+func appendQuery(rawURL string, params url.Values) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := parsed.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
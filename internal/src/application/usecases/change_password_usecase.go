@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type changePasswordUsecase struct {
+	cfg          config.AuthConfig
+	authRepo     repositories.IAuthRepository
+	auditService services.IAuditService
+	metrics      services.IMetrics
+}
+
+func NewChangePasswordUsecase(cfg config.AuthConfig, authRepo repositories.IAuthRepository, auditService services.IAuditService, metrics services.IMetrics) usecase.UseCaseWithProps[dtos.ChangePasswordDTO, *struct{}] {
+	return &changePasswordUsecase{
+		cfg:          cfg,
+		authRepo:     authRepo,
+		auditService: auditService,
+		metrics:      metrics,
+	}
+}
+
+func (uc changePasswordUsecase) Execute(ctx context.Context, props dtos.ChangePasswordDTO) (*struct{}, error) {
+	err := uc.changePassword(ctx, props)
+
+	recordAudit(ctx, uc.auditService, services.AuditEvent{
+		Type:      auditEventPasswordChange,
+		ActorID:   props.UserID,
+		SubjectID: props.UserID,
+	}, err)
+
+	if err != nil {
+		return nil, err
+	}
+	return &struct{}{}, nil
+}
+
+func (uc changePasswordUsecase) changePassword(ctx context.Context, props dtos.ChangePasswordDTO) error {
+	if props.UserID == "" || props.CurrentPassword == "" || props.NewPassword == "" {
+		return exceptions.NewBusinessException("user ID, current password and new password are required")
+	}
+
+	auth, err := uc.authRepo.GetByUserID(ctx, props.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !checkPassword(uc.metrics, props.CurrentPassword, auth.GetPassword()) {
+		return exceptions.NewBusinessException("invalid credentials")
+	}
+
+	hashedPassword, err := hashPassword(uc.metrics, uc.cfg, props.NewPassword)
+	if err != nil {
+		return exceptions.NewBusinessException("failed to hash password")
+	}
+
+	return uc.authRepo.UpdatePassword(ctx, props.UserID, hashedPassword)
+}
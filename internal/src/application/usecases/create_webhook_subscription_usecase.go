@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type createWebhookSubscriptionUsecase struct {
+	subscriptionRepo repositories.IWebhookSubscriptionRepository
+}
+
+func NewCreateWebhookSubscriptionUsecase(subscriptionRepo repositories.IWebhookSubscriptionRepository) usecase.UseCaseWithProps[dtos.CreateWebhookSubscriptionDTO, *dtos.WebhookSubscriptionDTO] {
+	return &createWebhookSubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+// Execute generates the signing secret unless the caller brings one. Either
+// way it is returned only here.
+func (uc createWebhookSubscriptionUsecase) Execute(ctx context.Context, props dtos.CreateWebhookSubscriptionDTO) (*dtos.WebhookSubscriptionDTO, error) {
+	secret := props.Secret
+	if secret == "" {
+		generated, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, exceptions.NewBusinessException("failed to generate webhook secret")
+		}
+		secret = generated
+	}
+
+	subscription, err := models.NewWebhookSubscription(models.WebhookSubscriptionProps{
+		URL:         props.URL,
+		EventTypes:  props.EventTypes,
+		Secret:      secret,
+		MaxAttempts: props.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepo.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	response := webhookSubscriptionToDTO(subscription)
+	response.Secret = secret
+	return &response, nil
+}
+
+func webhookSubscriptionToDTO(subscription models.WebhookSubscription) dtos.WebhookSubscriptionDTO {
+	return dtos.WebhookSubscriptionDTO{
+		ID:          subscription.GetID(),
+		URL:         subscription.GetURL(),
+		EventTypes:  subscription.GetEventTypes(),
+		MaxAttempts: subscription.GetMaxAttempts(),
+		CreatedAt:   subscription.GetCreatedAt(),
+	}
+}
@@ -12,12 +12,14 @@ import (
 type deleteAuthUsecase struct {
 	authRepo repositories.IAuthRepository
 	jwtService services.IJWTService
+	auditService services.IAuditService
 }
 
-func NewDeleteAuthUsecase(authRepo repositories.IAuthRepository, jwtService services.IJWTService) usecase.UseCaseWithProps[string, *struct{}] {
+func NewDeleteAuthUsecase(authRepo repositories.IAuthRepository, jwtService services.IJWTService, auditService services.IAuditService) usecase.UseCaseWithProps[string, *struct{}] {
 	return &deleteAuthUsecase{
 		authRepo: authRepo,
 		jwtService: jwtService,
+		auditService: auditService,
 	}
 }
 
@@ -26,7 +28,18 @@ func (luc deleteAuthUsecase) Execute(ctx context.Context, userID string) (*struc
 		return nil, exceptions.NewBusinessException("access token is required")
 	}
 
+    event := services.AuditEvent{
+        Type:      auditEventDeleteAuth,
+        SubjectID: userID,
+    }
+
+    // The tenant has to be read before the user is gone.
+    if auth, err := luc.authRepo.GetByUserID(ctx, userID); err == nil {
+        event.TenantID = auditTenant(auth.GetUserInfo())
+    }
+
     err := luc.authRepo.Delete(ctx, userID)
+    recordAudit(ctx, luc.auditService, event, err)
     if err != nil {
         return nil, err
     }
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type deleteWebhookSubscriptionUsecase struct {
+	subscriptionRepo repositories.IWebhookSubscriptionRepository
+}
+
+func NewDeleteWebhookSubscriptionUsecase(subscriptionRepo repositories.IWebhookSubscriptionRepository) usecase.UseCaseWithProps[dtos.DeleteWebhookSubscriptionDTO, *struct{}] {
+	return &deleteWebhookSubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (uc deleteWebhookSubscriptionUsecase) Execute(ctx context.Context, props dtos.DeleteWebhookSubscriptionDTO) (*struct{}, error) {
+	if props.ID == "" {
+		return nil, exceptions.NewBusinessException("subscription ID is required")
+	}
+
+	if err := uc.subscriptionRepo.Delete(ctx, props.ID); err != nil {
+		return nil, err
+	}
+
+	return &struct{}{}, nil
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type getJWKSUsecase struct {
+	jwtService services.IJWTService
+}
+
+func NewGetJWKSUsecase(jwtService services.IJWTService) usecase.UseCase[*dtos.JWKSDTO] {
+	return &getJWKSUsecase{
+		jwtService: jwtService,
+	}
+}
+
+func (uc getJWKSUsecase) Execute(ctx context.Context) (*dtos.JWKSDTO, error) {
+	keys, err := uc.jwtService.GetPublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.JWKSDTO{Keys: make([]dtos.JSONWebKeyDTO, 0, len(keys))}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, dtos.JSONWebKeyDTO{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+			Y:   key.Y,
+		})
+	}
+
+	return response, nil
+}
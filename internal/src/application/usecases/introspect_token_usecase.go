@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type introspectTokenUsecase struct {
+	clientRepo    repositories.IOAuthClientRepository
+	tokenVerifier *TokenVerifier
+	metrics       services.IMetrics
+}
+
+func NewIntrospectTokenUsecase(clientRepo repositories.IOAuthClientRepository, tokenVerifier *TokenVerifier, metrics services.IMetrics) usecase.UseCaseWithProps[dtos.IntrospectDTO, *dtos.IntrospectionResponseDTO] {
+	return &introspectTokenUsecase{
+		clientRepo:    clientRepo,
+		tokenVerifier: tokenVerifier,
+		metrics:       metrics,
+	}
+}
+
+func (uc introspectTokenUsecase) Execute(ctx context.Context, props dtos.IntrospectDTO) (*dtos.IntrospectionResponseDTO, error) {
+	client, err := authenticateClient(ctx, uc.clientRepo, uc.metrics, props.ClientID, props.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	// Public clients cannot keep a secret, so anyone could introspect
+	// tokens in their name.
+	if client.IsPublic() {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "introspection requires a confidential client")
+	}
+
+	if props.Token == "" {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "token is required")
+	}
+
+	// As at revocation, the hint only decides which type is tried first. A
+	// refresh token is checked against the revocation of its session too.
+	verifiers := []func(context.Context, string) (map[string]interface{}, models.Auth, error){
+		uc.tokenVerifier.VerifyAccessToken,
+		uc.tokenVerifier.VerifyRefreshToken,
+	}
+	if props.TokenTypeHint == tokenTypeHintRefreshToken {
+		verifiers[0], verifiers[1] = verifiers[1], verifiers[0]
+	}
+
+	for _, verify := range verifiers {
+		claims, _, err := verify(ctx, props.Token)
+		if err != nil {
+			if isInvalidTokenError(err) {
+				continue
+			}
+			return nil, err
+		}
+		return introspectionResponse(claims), nil
+	}
+
+	return &dtos.IntrospectionResponseDTO{Active: false}, nil
+}
+
+// introspectionResponse describes an active token. Access tokens are bearer
+// tokens; refresh tokens are told apart by a token_type of refresh_token.
+func introspectionResponse(claims map[string]interface{}) *dtos.IntrospectionResponseDTO {
+	response := &dtos.IntrospectionResponseDTO{
+		Active:    true,
+		TokenType: "Bearer",
+		Roles:     claimRoles(claims),
+		Aud:       claimStrings(claims, "aud"),
+	}
+	if claims["type"] == "refresh" {
+		response.TokenType = tokenTypeHintRefreshToken
+	}
+	response.Iss, _ = claims["iss"].(string)
+	response.Sub, _ = claims["sub"].(string)
+	response.Scope, _ = claims["scope"].(string)
+	response.ClientID, _ = claims["client_id"].(string)
+	response.Jti, _ = claims["jti"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		response.Exp = int64(exp)
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		response.Iat = int64(iat)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok {
+		response.Nbf = int64(nbf)
+	}
+
+	return response
+}
@@ -0,0 +1,292 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const testClientSecret = "client-secret"
+
+// fakeJWTService knows the claims of a fixed set of tokens and records the
+// order in which token types were tried and the access tokens it issued.
+// Only the methods used by the TokenVerifier and the TokenIssuer are
+// implemented.
+type fakeJWTService struct {
+	services.IJWTService
+	tokens map[string]map[string]interface{}
+	tried  []string
+	issued []services.AccessTokenClaims
+}
+
+func (s *fakeJWTService) GenerateToken(ctx context.Context, claims services.AccessTokenClaims) (*string, error) {
+	s.issued = append(s.issued, claims)
+	token := fmt.Sprintf("issued-%d", len(s.issued))
+	return &token, nil
+}
+
+func (s *fakeJWTService) ExtractClaims(ctx context.Context, token string, expectations services.TokenExpectations) (map[string]interface{}, error) {
+	s.tried = append(s.tried, "access")
+	return s.extract(token, "access")
+}
+
+func (s *fakeJWTService) ExtractRefreshClaims(ctx context.Context, token string) (map[string]interface{}, error) {
+	s.tried = append(s.tried, "refresh")
+	return s.extract(token, "refresh")
+}
+
+func (s *fakeJWTService) extract(token, tokenType string) (map[string]interface{}, error) {
+	claims, ok := s.tokens[token]
+	if !ok || claims["type"] != tokenType {
+		return nil, exceptions.NewBusinessException("invalid token")
+	}
+	return claims, nil
+}
+
+// fakeEncryptService treats every token as a plain JWT.
+type fakeEncryptService struct {
+	services.IEncryptService
+}
+
+func (s fakeEncryptService) Envelope(token string) services.TokenEnvelope {
+	return services.TokenEnvelopeNone
+}
+
+type fakeAuthRepo struct {
+	repositories.IAuthRepository
+	users map[string]models.Auth
+}
+
+func (r *fakeAuthRepo) GetByUserID(ctx context.Context, userID string) (models.Auth, error) {
+	auth, ok := r.users[userID]
+	if !ok {
+		return nil, exceptions.NewRepositoryNoDataFoundException("user not found")
+	}
+	return auth, nil
+}
+
+type fakeRevokedTokenRepo struct {
+	revoked map[string]models.RevokedToken
+}
+
+func (r *fakeRevokedTokenRepo) Save(ctx context.Context, token models.RevokedToken) error {
+	r.revoked[token.GetTokenID()] = token
+	return nil
+}
+
+func (r *fakeRevokedTokenRepo) IsRevoked(ctx context.Context, tokenIDs ...string) (bool, error) {
+	for _, id := range tokenIDs {
+		if _, ok := r.revoked[id]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// tokenFixture holds what a TokenVerifier needs to accept the tokens of its
+// users, starting with user-1.
+type tokenFixture struct {
+	clients  *fakeClientRepo
+	users    *fakeAuthRepo
+	jwt      *fakeJWTService
+	revoked  *fakeRevokedTokenRepo
+	verifier *TokenVerifier
+	metrics  services.IMetrics
+}
+
+func newTokenFixture(t *testing.T) *tokenFixture {
+	t.Helper()
+
+	f := &tokenFixture{
+		clients: &fakeClientRepo{clients: map[string]models.OAuthClient{}},
+		users:   &fakeAuthRepo{users: map[string]models.Auth{}},
+		jwt:     &fakeJWTService{tokens: map[string]map[string]interface{}{}},
+		revoked: &fakeRevokedTokenRepo{revoked: map[string]models.RevokedToken{}},
+		metrics: adapters.NewMetrics(adapters.NewMetricsRegistry()),
+	}
+	f.verifier = NewTokenVerifier(f.users, f.revoked, f.jwt, fakeEncryptService{}, f.metrics)
+	f.addUser(t, "user-1")
+
+	return f
+}
+
+func (f *tokenFixture) addUser(t *testing.T, userID string, roles ...string) {
+	t.Helper()
+
+	userInfo, er := models.NewUserInfo(models.UserInfoProps{UserID: userID, Roles: roles})
+	if er != nil {
+		t.Fatalf("NewUserInfo() error = %v", er)
+	}
+	auth, er := models.NewAuth(models.AuthProps{
+		IdentifierType:  1,
+		IdentifierValue: userID + "@example.com",
+		Password:        "hash",
+		UserInfo:        userInfo,
+	})
+	if er != nil {
+		t.Fatalf("NewAuth() error = %v", er)
+	}
+	f.users.users[userID] = auth
+}
+
+// addClient registers a client; confidential clients get testClientSecret.
+func (f *tokenFixture) addClient(t *testing.T, clientID string, public bool) {
+	t.Helper()
+
+	props := models.OAuthClientProps{ClientID: clientID, Public: public}
+	if !public {
+		hash, err := utils.HashPassword(testClientSecret, 4)
+		if err != nil {
+			t.Fatalf("HashPassword() error = %v", err)
+		}
+		props.SecretHash = &hash
+	}
+	f.clients.clients[clientID] = newTestClient(t, props)
+}
+
+// addToken makes token known with the given claims on top of those every
+// token carries. Tokens belong to user-1 unless extra sets another sub, and
+// a client_id of "" leaves the claim out.
+func (f *tokenFixture) addToken(token, tokenType, clientID string, extra map[string]interface{}) {
+	claims := map[string]interface{}{
+		"sub":  "user-1",
+		"type": tokenType,
+		"jti":  "jti-" + token,
+		"exp":  float64(time.Now().Add(time.Hour).Unix()),
+	}
+	if clientID != "" {
+		claims["client_id"] = clientID
+	}
+	for name, value := range extra {
+		claims[name] = value
+	}
+	f.jwt.tokens[token] = claims
+}
+
+func TestIntrospectionRequiresConfidentialClient(t *testing.T) {
+	f := newTokenFixture(t)
+	f.addClient(t, "public-client", true)
+	f.addToken("access-1", "access", "public-client", nil)
+
+	uc := NewIntrospectTokenUsecase(f.clients, f.verifier, f.metrics)
+	_, err := uc.Execute(context.Background(), dtos.IntrospectDTO{Token: "access-1", ClientID: "public-client"})
+	wantOAuthError(t, err, models.OAuthErrorInvalidClient)
+
+	f.addClient(t, "confidential-client", false)
+	_, err = uc.Execute(context.Background(), dtos.IntrospectDTO{Token: "access-1", ClientID: "confidential-client", ClientSecret: "wrong"})
+	wantOAuthError(t, err, models.OAuthErrorInvalidClient)
+}
+
+func TestIntrospectToken(t *testing.T) {
+	f := newTokenFixture(t)
+	f.addClient(t, "resource-server", false)
+	f.addToken("access-1", "access", "app", map[string]interface{}{
+		"iss":   "https://auth.example",
+		"scope": "openid profile",
+		"aud":   []interface{}{"api"},
+		"roles": []interface{}{"admin"},
+		"iat":   float64(1700000000),
+	})
+	f.addToken("refresh-1", "refresh", "app", map[string]interface{}{"sid": "session-1"})
+	f.addToken("revoked-1", "access", "app", nil)
+	f.revoked.revoked["jti-revoked-1"] = nil
+
+	accessExp := int64(f.jwt.tokens["access-1"]["exp"].(float64))
+
+	tests := []struct {
+		name      string
+		token     string
+		hint      string
+		want      *dtos.IntrospectionResponseDTO
+		wantType  string
+		wantTried []string
+	}{
+		{
+			name:  "access token",
+			token: "access-1",
+			want: &dtos.IntrospectionResponseDTO{
+				Active:    true,
+				TokenType: "Bearer",
+				Iss:       "https://auth.example",
+				Sub:       "user-1",
+				Scope:     "openid profile",
+				ClientID:  "app",
+				Jti:       "jti-access-1",
+				Aud:       []string{"api"},
+				Roles:     []string{"admin"},
+				Exp:       accessExp,
+				Iat:       1700000000,
+			},
+			wantTried: []string{"access"},
+		},
+		{
+			name:      "refresh token",
+			token:     "refresh-1",
+			wantType:  tokenTypeHintRefreshToken,
+			wantTried: []string{"access", "refresh"},
+		},
+		{
+			name:      "refresh token with hint",
+			token:     "refresh-1",
+			hint:      tokenTypeHintRefreshToken,
+			wantType:  tokenTypeHintRefreshToken,
+			wantTried: []string{"refresh"},
+		},
+		{
+			name:      "access token with wrong hint",
+			token:     "access-1",
+			hint:      tokenTypeHintRefreshToken,
+			wantType:  "Bearer",
+			wantTried: []string{"refresh", "access"},
+		},
+		{
+			name:      "revoked token",
+			token:     "revoked-1",
+			want:      &dtos.IntrospectionResponseDTO{Active: false},
+			wantTried: []string{"access", "refresh"},
+		},
+		{
+			name:      "unknown token",
+			token:     "garbage",
+			want:      &dtos.IntrospectionResponseDTO{Active: false},
+			wantTried: []string{"access", "refresh"},
+		},
+	}
+
+	uc := NewIntrospectTokenUsecase(f.clients, f.verifier, f.metrics)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.jwt.tried = nil
+
+			got, err := uc.Execute(context.Background(), dtos.IntrospectDTO{
+				Token:         tt.token,
+				TokenTypeHint: tt.hint,
+				ClientID:      "resource-server",
+				ClientSecret:  testClientSecret,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Execute() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantType != "" && (!got.Active || got.TokenType != tt.wantType) {
+				t.Errorf("Execute() = %+v, want an active %s", got, tt.wantType)
+			}
+			if !reflect.DeepEqual(f.jwt.tried, tt.wantTried) {
+				t.Errorf("tried %v, want %v", f.jwt.tried, tt.wantTried)
+			}
+		})
+	}
+}
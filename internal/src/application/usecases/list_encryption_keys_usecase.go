@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type listEncryptionKeysUsecase struct {
+	encryptService services.IEncryptService
+}
+
+func NewListEncryptionKeysUsecase(encryptService services.IEncryptService) usecase.UseCaseWithProps[dtos.ListEncryptionKeysDTO, *dtos.ListEncryptionKeysResponseDTO] {
+	return &listEncryptionKeysUsecase{
+		encryptService: encryptService,
+	}
+}
+
+func (uc listEncryptionKeysUsecase) Execute(ctx context.Context, props dtos.ListEncryptionKeysDTO) (*dtos.ListEncryptionKeysResponseDTO, error) {
+	keys, err := uc.encryptService.ListEncryptionKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := &dtos.ListEncryptionKeysResponseDTO{Keys: []dtos.EncryptionKeyDTO{}}
+	for _, key := range keys {
+		dto := encryptionKeyToDTO(key, now)
+		if props.InUseOnly && !dto.InUse {
+			continue
+		}
+		response.Keys = append(response.Keys, dto)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type listSigningKeysUsecase struct {
+	jwtService services.IJWTService
+}
+
+func NewListSigningKeysUsecase(jwtService services.IJWTService) usecase.UseCaseWithProps[dtos.ListSigningKeysDTO, *dtos.ListSigningKeysResponseDTO] {
+	return &listSigningKeysUsecase{
+		jwtService: jwtService,
+	}
+}
+
+func (uc listSigningKeysUsecase) Execute(ctx context.Context, props dtos.ListSigningKeysDTO) (*dtos.ListSigningKeysResponseDTO, error) {
+	keys, err := uc.jwtService.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.ListSigningKeysResponseDTO{Keys: []dtos.SigningKeyDTO{}}
+	for _, key := range keys {
+		if props.Purpose != "" && key.GetPurpose() != props.Purpose {
+			continue
+		}
+		response.Keys = append(response.Keys, signingKeyToDTO(key))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	defaultWebhookDeliveryPageSize = 50
+	maxWebhookDeliveryPageSize     = 500
+)
+
+type listWebhookDeliveriesUsecase struct {
+	subscriptionRepo repositories.IWebhookSubscriptionRepository
+	deliveryRepo     repositories.IWebhookDeliveryRepository
+}
+
+func NewListWebhookDeliveriesUsecase(
+	subscriptionRepo repositories.IWebhookSubscriptionRepository,
+	deliveryRepo repositories.IWebhookDeliveryRepository,
+) usecase.UseCaseWithProps[dtos.ListWebhookDeliveriesDTO, *dtos.ListWebhookDeliveriesResponseDTO] {
+	return &listWebhookDeliveriesUsecase{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+	}
+}
+
+func (uc listWebhookDeliveriesUsecase) Execute(ctx context.Context, props dtos.ListWebhookDeliveriesDTO) (*dtos.ListWebhookDeliveriesResponseDTO, error) {
+	if props.SubscriptionID == "" {
+		return nil, exceptions.NewBusinessException("subscription ID is required")
+	}
+	switch props.Status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryDead:
+	default:
+		return nil, exceptions.NewBusinessException("status must be pending, succeeded or dead")
+	}
+
+	if _, err := uc.subscriptionRepo.GetByID(ctx, props.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	pageSize := props.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultWebhookDeliveryPageSize
+	}
+	if pageSize > maxWebhookDeliveryPageSize {
+		pageSize = maxWebhookDeliveryPageSize
+	}
+
+	query := repositories.WebhookDeliveryQuery{
+		SubscriptionID: props.SubscriptionID,
+		Status:         props.Status,
+		Limit:          pageSize + 1,
+	}
+	if props.Cursor != "" {
+		createdAt, id, err := decodePageCursor(props.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = &repositories.WebhookDeliveryCursor{CreatedAt: createdAt, ID: id}
+	}
+
+	deliveries, err := uc.deliveryRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.ListWebhookDeliveriesResponseDTO{Deliveries: []dtos.WebhookDeliveryDTO{}}
+	if len(deliveries) > pageSize {
+		deliveries = deliveries[:pageSize]
+		last := deliveries[len(deliveries)-1]
+		response.NextCursor = encodePageCursor(last.GetCreatedAt(), last.GetID())
+	}
+
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, webhookDeliveryToDTO(delivery))
+	}
+
+	return response, nil
+}
+
+func webhookDeliveryToDTO(delivery models.WebhookDelivery) dtos.WebhookDeliveryDTO {
+	return dtos.WebhookDeliveryDTO{
+		ID:             delivery.GetID(),
+		SubscriptionID: delivery.GetSubscriptionID(),
+		EventID:        delivery.GetEventID(),
+		EventType:      delivery.GetEventType(),
+		Status:         delivery.GetStatus(),
+		Attempts:       delivery.GetAttempts(),
+		NextAttemptAt:  delivery.GetNextAttemptAt(),
+		LastStatusCode: delivery.GetLastStatusCode(),
+		LastError:      delivery.GetLastError(),
+		CreatedAt:      delivery.GetCreatedAt(),
+		DeliveredAt:    delivery.GetDeliveredAt(),
+	}
+}
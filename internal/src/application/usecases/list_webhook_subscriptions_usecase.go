@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type listWebhookSubscriptionsUsecase struct {
+	subscriptionRepo repositories.IWebhookSubscriptionRepository
+}
+
+func NewListWebhookSubscriptionsUsecase(subscriptionRepo repositories.IWebhookSubscriptionRepository) usecase.UseCaseWithProps[dtos.ListWebhookSubscriptionsDTO, *dtos.ListWebhookSubscriptionsResponseDTO] {
+	return &listWebhookSubscriptionsUsecase{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (uc listWebhookSubscriptionsUsecase) Execute(ctx context.Context, props dtos.ListWebhookSubscriptionsDTO) (*dtos.ListWebhookSubscriptionsResponseDTO, error) {
+	subscriptions, err := uc.subscriptionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.ListWebhookSubscriptionsResponseDTO{Subscriptions: []dtos.WebhookSubscriptionDTO{}}
+	for _, subscription := range subscriptions {
+		response.Subscriptions = append(response.Subscriptions, webhookSubscriptionToDTO(subscription))
+	}
+
+	return response, nil
+}
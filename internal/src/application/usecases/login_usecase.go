@@ -2,73 +2,53 @@ package usecases
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
-	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
 )
 
 type loginUsecase struct {
 	authRepo repositories.IAuthRepository
-	jwtService services.IJWTService
-    encryptService services.IEncryptService
+    tokenIssuer *TokenIssuer
+	auditService services.IAuditService
+	metrics services.IMetrics
 }
 
-func NewLoginUsecase(authRepo repositories.IAuthRepository, jwtService services.IJWTService, encryptService services.IEncryptService) usecase.UseCaseWithProps[dtos.LoginDTO, *dtos.LoginResponseDTO] {
+func NewLoginUsecase(authRepo repositories.IAuthRepository, tokenIssuer *TokenIssuer, auditService services.IAuditService, metrics services.IMetrics) usecase.UseCaseWithProps[dtos.LoginDTO, *dtos.LoginResponseDTO] {
 	return &loginUsecase{
 		authRepo: authRepo,
-		jwtService: jwtService,
-        encryptService: encryptService,
+        tokenIssuer: tokenIssuer,
+		auditService: auditService,
+		metrics: metrics,
 	}
 }
 
 func (luc loginUsecase) Execute(ctx context.Context, props dtos.LoginDTO) (*dtos.LoginResponseDTO, error) {
-    auth, err := luc.authRepo.GetByIdentifier(ctx, int(props.IdentifierType), props.IdentifierValue)
+    auth, err := authenticate(ctx, luc.authRepo, luc.auditService, luc.metrics, props, "")
     if err != nil {
         return nil, err
     }
 
-    if ok := utils.CheckPasswordHash(props.Password, auth.GetPassword()); !ok {
-        return nil, exceptions.NewBusinessException("invalid credentials")
+    options := TokenOptions{
+        SessionID: newSessionID(),
+        Audience:  props.Audience,
     }
 
-    accessToken, err := luc.jwtService.GenerateToken(
-        ctx,
-        auth.GetUserInfo().GetUserID(), 
-        auth.GetUserInfo().GetRoles(), 
-        *auth.GetMaxTokenAgeSeconds(),
-    )
+    accessToken, err := luc.tokenIssuer.IssueAccessToken(ctx, auth, options)
     if err != nil {
         return nil, err
     }
 
-    refreshTokenExpiry := 7 * 24 * 60 * 60
-    refreshToken, err := luc.jwtService.GenerateRefreshToken(
-        ctx,
-        auth.GetUserInfo().GetUserID(),
-        refreshTokenExpiry,
-    )
+    refreshToken, err := luc.tokenIssuer.IssueRefreshToken(ctx, auth, options)
     if err != nil {
         return nil, err
     }
 
-    if auth.GetEncryptToken() {
-        accessToken, err = luc.encryptService.Encrypt(ctx, *accessToken)
-        if err != nil {
-            fmt.Println("Failed to encrypt access token:", err)
-            return nil, exceptions.NewBusinessException("failed to encrypt access token")
-        }
-
-        refreshToken, err = luc.encryptService.Encrypt(ctx, *refreshToken)
-        if err != nil {
-            return nil, exceptions.NewBusinessException("failed to encrypt refresh token")
-        }
-    }
-
     return &dtos.LoginResponseDTO{
         AccessToken:  *accessToken,
         RefreshToken: *refreshToken,
@@ -78,4 +58,81 @@ func (luc loginUsecase) Execute(ctx context.Context, props dtos.LoginDTO) (*dtos
             Roles:  auth.GetUserInfo().GetRoles(),
         },
     }, nil
-}
\ No newline at end of file
+}
+
+// authenticate checks a set of credentials and returns the matching auth.
+// It is shared by every flow where the user presents a password, so every
+// attempt is audited here; clientID is set when the user signs in to an
+// OAuth client. Every attempt is also counted by outcome.
+func authenticate(ctx context.Context, authRepo repositories.IAuthRepository, auditService services.IAuditService, metrics services.IMetrics, props dtos.LoginDTO, clientID string) (models.Auth, error) {
+    event := services.AuditEvent{
+        Type:     auditEventLogin,
+        ClientID: clientID,
+        Details: map[string]interface{}{
+            "identifier_type": props.IdentifierType,
+        },
+    }
+
+    auth, err := authRepo.GetByIdentifier(ctx, int(props.IdentifierType), props.IdentifierValue)
+    if err != nil {
+        var notFound *exceptions.RepositoryNoDataFoundException
+        if errors.As(err, &notFound) {
+            // The repository message names the identifier, which must not
+            // end up in the audit log.
+            event.Reason = "unknown identifier"
+            metrics.LoginAttempt(services.LoginOutcomeUnknownUser)
+        } else {
+            metrics.LoginAttempt(services.LoginOutcomeError)
+        }
+        recordAudit(ctx, auditService, event, err)
+        return nil, err
+    }
+
+    userID := auth.GetUserInfo().GetUserID()
+    event.ActorID = userID
+    event.SubjectID = userID
+    event.TenantID = auditTenant(auth.GetUserInfo())
+
+    if auth.IsLocked() {
+        err := exceptions.NewBusinessException("account is locked")
+        metrics.LoginAttempt(services.LoginOutcomeLocked)
+        recordAudit(ctx, auditService, event, err)
+        return nil, err
+    }
+
+    if ok := checkPassword(metrics, props.Password, auth.GetPassword()); !ok {
+        attempts, err := authRepo.RecordLoginAttempt(ctx, userID, false)
+        if err != nil {
+            metrics.LoginAttempt(services.LoginOutcomeError)
+            return nil, err
+        }
+        metrics.LoginAttempt(services.LoginOutcomeBadPassword)
+
+        err = exceptions.NewBusinessException("invalid credentials")
+        recordAudit(ctx, auditService, event, err)
+
+        // Only the attempt that reaches the limit locks the account, so the
+        // lockout is recorded once.
+        if attempts == *auth.GetMaxWrongAttempts() {
+            recordAudit(ctx, auditService, services.AuditEvent{
+                Type:      auditEventLockout,
+                SubjectID: userID,
+                ClientID:  clientID,
+                TenantID:  event.TenantID,
+                Details: map[string]interface{}{
+                    "wrong_attempts": attempts,
+                },
+            }, nil)
+        }
+        return nil, err
+    }
+
+    if _, err := authRepo.RecordLoginAttempt(ctx, userID, true); err != nil {
+        metrics.LoginAttempt(services.LoginOutcomeError)
+        return nil, err
+    }
+    metrics.LoginAttempt(services.LoginOutcomeSuccess)
+
+    recordAudit(ctx, auditService, event, nil)
+    return auth, nil
+}
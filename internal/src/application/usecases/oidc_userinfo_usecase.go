@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type oidcUserInfoUsecase struct {
+	tokenVerifier *TokenVerifier
+}
+
+func NewOIDCUserInfoUsecase(tokenVerifier *TokenVerifier) usecase.UseCaseWithProps[dtos.OIDCUserInfoRequestDTO, *dtos.OIDCUserInfoDTO] {
+	return &oidcUserInfoUsecase{
+		tokenVerifier: tokenVerifier,
+	}
+}
+
+func (uc oidcUserInfoUsecase) Execute(ctx context.Context, props dtos.OIDCUserInfoRequestDTO) (*dtos.OIDCUserInfoDTO, error) {
+	claims, auth, err := uc.tokenVerifier.VerifyAccessToken(ctx, props.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, _ := claims["scope"].(string)
+	if !hasScope(scope, models.ScopeOpenID) {
+		return nil, models.NewOAuthError(models.OAuthErrorInsufficientScope, "the access token was not issued for the openid scope")
+	}
+
+	released := standardClaims(auth, scope)
+	response := &dtos.OIDCUserInfoDTO{Sub: auth.GetUserInfo().GetUserID()}
+	response.Name, _ = released["name"].(string)
+	response.Email, _ = released["email"].(string)
+	response.PhoneNumber, _ = released["phone_number"].(string)
+
+	return response, nil
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+)
+
+type openIDConfigurationUsecase struct {
+	jwtService services.IJWTService
+}
+
+func NewOpenIDConfigurationUsecase(jwtService services.IJWTService) usecase.UseCase[*dtos.OpenIDConfigurationDTO] {
+	return &openIDConfigurationUsecase{
+		jwtService: jwtService,
+	}
+}
+
+func (uc openIDConfigurationUsecase) Execute(ctx context.Context) (*dtos.OpenIDConfigurationDTO, error) {
+	issuer := strings.TrimSuffix(uc.jwtService.GetIssuer(), "/")
+
+	return &dtos.OpenIDConfigurationDTO{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserInfoEndpoint:                  issuer + "/userinfo",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		RevocationEndpoint:                issuer + "/oauth/revoke",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{grantTypeAuthorizationCode, grantTypeTokenExchange},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{uc.jwtService.GetSigningAlgorithm()},
+		ScopesSupported:                   models.SupportedScopes,
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "name", "email", "phone_number"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{models.CodeChallengeMethodS256},
+	}, nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const (
+	outboxBatchSize          = 100
+	outboxMaxRounds          = 10
+	outboxLease              = time.Minute
+	outboxPublishTimeout     = 15 * time.Second
+	outboxMaxRetryDelay      = 10 * time.Minute
+	outboxPublishedRetention = 7 * 24 * time.Hour
+)
+
+// OutboxRelay moves domain events from the outbox to the event publisher.
+// Delivery is at least once: an event is marked published only after the
+// publisher accepted it, and a relay that dies in between leaves the lease
+// to expire so the event is sent again. Events of one user go out one at a
+// time in the order they were written; a failing event holds back the
+// user's later events until it gets through. Besides the publisher, every
+// event is handed to the webhook dispatcher, which delivers it to webhook
+// subscriptions on its own schedule; that does not wait for the publisher,
+// and handing an event over again when it is retried is harmless.
+type OutboxRelay struct {
+	outboxRepo        repositories.IOutboxRepository
+	publisher         services.IEventPublisher
+	webhookDispatcher *WebhookDispatcher
+	logger            *slog.Logger
+}
+
+func NewOutboxRelay(outboxRepo repositories.IOutboxRepository, publisher services.IEventPublisher, webhookDispatcher *WebhookDispatcher, logger *slog.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo:        outboxRepo,
+		publisher:         publisher,
+		webhookDispatcher: webhookDispatcher,
+		logger:            logger,
+	}
+}
+
+// Relay delivers what is due. Each round takes the next event of every
+// user, so a user with a backlog needs one round per event.
+func (r *OutboxRelay) Relay(ctx context.Context) error {
+	for round := 0; round < outboxMaxRounds; round++ {
+		messages, err := r.outboxRepo.Claim(ctx, outboxBatchSize, time.Now().Add(outboxLease))
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		for _, message := range messages {
+			event := message.GetEvent()
+
+			err := r.deliver(ctx, event)
+
+			if err != nil {
+				retryAt := time.Now().Add(outboxRetryDelay(message.GetAttempts()))
+				r.logger.WarnContext(ctx, "Failed to publish event", "event_type", event.GetType(), "event_id", event.GetID(), "retry_at", retryAt, "error", err)
+				if err := r.outboxRepo.MarkFailed(ctx, event.GetID(), err.Error(), retryAt); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := r.outboxRepo.MarkPublished(ctx, event.GetID()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, event models.DomainEvent) error {
+	// Enqueue ignores deliveries it already has, so a retry after a
+	// publisher failure does not deliver twice.
+	enqueueErr := r.webhookDispatcher.Enqueue(ctx, event)
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	defer cancel()
+
+	return errors.Join(r.publisher.Publish(publishCtx, event), enqueueErr)
+}
+
+// PurgePublished drops delivered events after a week; they are kept that
+// long only to help trace deliveries.
+func (r *OutboxRelay) PurgePublished(ctx context.Context) error {
+	_, err := r.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-outboxPublishedRetention))
+	return err
+}
+
+// outboxRetryDelay doubles from one second per failed attempt, capped at
+// ten minutes.
+func outboxRetryDelay(attempts int) time.Duration {
+	if attempts >= 10 {
+		return outboxMaxRetryDelay
+	}
+
+	delay := time.Second << attempts
+	if delay > outboxMaxRetryDelay {
+		return outboxMaxRetryDelay
+	}
+	return delay
+}
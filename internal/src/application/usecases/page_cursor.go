@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// Page cursors are opaque to callers. They hold the sort key of the last
+// item of a page, a timestamp and an ID, so the next page starts right
+// after it.
+func encodePageCursor(at time.Time, id string) string {
+	raw := fmt.Sprintf("%d:%s", at.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageCursor(cursor string) (time.Time, string, *exceptions.BusinessException) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", exceptions.NewBusinessException("invalid cursor")
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return time.Time{}, "", exceptions.NewBusinessException("invalid cursor")
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", exceptions.NewBusinessException("invalid cursor")
+	}
+
+	return time.Unix(0, unixNano).UTC(), id, nil
+}
@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+)
+
+// hashPassword and checkPassword are the bcrypt helpers, timed. Passwords
+// and client secrets both go through them, hashed at the configured cost.
+func hashPassword(metrics services.IMetrics, cfg config.AuthConfig, password string) (string, error) {
+	start := time.Now()
+	defer func() { metrics.ObservePasswordHash("hash", time.Since(start)) }()
+
+	return utils.HashPassword(password, cfg.BcryptCost)
+}
+
+func checkPassword(metrics services.IMetrics, password, hash string) bool {
+	start := time.Now()
+	defer func() { metrics.ObservePasswordHash("compare", time.Since(start)) }()
+
+	return utils.CheckPasswordHash(password, hash)
+}
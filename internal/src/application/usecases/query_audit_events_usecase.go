@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+type queryAuditEventsUsecase struct {
+	auditEventRepo repositories.IAuditEventRepository
+}
+
+func NewQueryAuditEventsUsecase(auditEventRepo repositories.IAuditEventRepository) usecase.UseCaseWithProps[dtos.QueryAuditEventsDTO, *dtos.QueryAuditEventsResponseDTO] {
+	return &queryAuditEventsUsecase{
+		auditEventRepo: auditEventRepo,
+	}
+}
+
+func (uc queryAuditEventsUsecase) Execute(ctx context.Context, props dtos.QueryAuditEventsDTO) (*dtos.QueryAuditEventsResponseDTO, error) {
+	pageSize := props.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+	if pageSize > maxAuditPageSize {
+		pageSize = maxAuditPageSize
+	}
+
+	if props.From != nil && props.To != nil && props.From.After(*props.To) {
+		return nil, exceptions.NewBusinessException("from must not be after to")
+	}
+
+	query := repositories.AuditEventQuery{
+		UserID: props.UserID,
+		Types:  props.Types,
+		From:   props.From,
+		To:     props.To,
+		// One extra event tells whether there is a next page.
+		Limit: pageSize + 1,
+	}
+	if props.Cursor != "" {
+		occurredAt, id, err := decodePageCursor(props.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = &repositories.AuditEventCursor{OccurredAt: occurredAt, ID: id}
+	}
+
+	events, err := uc.auditEventRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.QueryAuditEventsResponseDTO{Events: []dtos.AuditEventDTO{}}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		last := events[len(events)-1]
+		response.NextCursor = encodePageCursor(last.GetOccurredAt(), last.GetID())
+	}
+
+	for _, event := range events {
+		response.Events = append(response.Events, auditEventToDTO(event))
+	}
+
+	return response, nil
+}
+
+func auditEventToDTO(event models.AuditEvent) dtos.AuditEventDTO {
+	return dtos.AuditEventDTO{
+		ID:         event.GetID(),
+		Type:       event.GetType(),
+		ActorID:    event.GetActorID(),
+		SubjectID:  event.GetSubjectID(),
+		ClientID:   event.GetClientID(),
+		Outcome:    event.GetOutcome(),
+		Reason:     event.GetReason(),
+		PeerIP:     event.GetPeerIP(),
+		UserAgent:  event.GetUserAgent(),
+		Details:    event.GetDetails(),
+		OccurredAt: event.GetOccurredAt(),
+	}
+}
@@ -2,63 +2,78 @@ package usecases
 
 import (
 	"context"
+	"slices"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
-	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
 )
 
 type refreshTokenUsecase struct {
-	authRepo repositories.IAuthRepository
-	jwtService services.IJWTService
-	encryptService services.IEncryptService
+	tokenVerifier *TokenVerifier
+	tokenIssuer *TokenIssuer
+	auditService services.IAuditService
+	metrics services.IMetrics
 }
 
-func NewRefreshTokenUsecase(authRepo repositories.IAuthRepository, jwtService services.IJWTService, encryptService services.IEncryptService) usecase.UseCaseWithProps[dtos.RefreshTokenDTO, *dtos.RefreshTokenResponseDTO] {
+func NewRefreshTokenUsecase(tokenVerifier *TokenVerifier, tokenIssuer *TokenIssuer, auditService services.IAuditService, metrics services.IMetrics) usecase.UseCaseWithProps[dtos.RefreshTokenDTO, *dtos.RefreshTokenResponseDTO] {
 	return &refreshTokenUsecase{
-		authRepo: authRepo,
-		jwtService: jwtService,
-		encryptService: encryptService,
+		tokenVerifier: tokenVerifier,
+		tokenIssuer: tokenIssuer,
+		auditService: auditService,
+		metrics: metrics,
 	}
 }
 
 func (luc refreshTokenUsecase) Execute(ctx context.Context, props dtos.RefreshTokenDTO) (*dtos.RefreshTokenResponseDTO, error) {
-	if props.RefreshToken == "" {
-		return nil, exceptions.NewBusinessException("refresh token is required")
-	}
-
-	token, _ := luc.encryptService.Decrypt(ctx, props.RefreshToken)
-	if token != "" {
-		props.RefreshToken = token
-	}
+	event := services.AuditEvent{Type: auditEventRefresh}
 
-	claims, err := luc.jwtService.ExtractRefreshClaims(ctx, props.RefreshToken)
+	claims, auth, err := luc.tokenVerifier.VerifyRefreshToken(ctx, props.RefreshToken)
 	if err != nil {
-		return nil, exceptions.NewBusinessException("invalid refresh token")
+		recordAudit(ctx, luc.auditService, event, err)
+		luc.metrics.TokenRefreshed(false)
+		return nil, err
 	}
 
-    auth, err := luc.authRepo.GetByUserID(ctx, claims["sub"].(string))
-    if err != nil {
-        return nil, err
-    }
+	event.ActorID = auth.GetUserInfo().GetUserID()
+	event.SubjectID = auth.GetUserInfo().GetUserID()
+	event.TenantID = auditTenant(auth.GetUserInfo())
+	event.ClientID, _ = claims["client_id"].(string)
 
-	newAccessToken, err := luc.jwtService.GenerateToken(
-		ctx,
-		auth.GetUserInfo().GetUserID(),
-		auth.GetUserInfo().GetRoles(),
-		*auth.GetMaxTokenAgeSeconds(),
-	)
-	if err != nil {
-		return nil, err
-	}
+	response, err := luc.refresh(ctx, props, claims, auth)
+	recordAudit(ctx, luc.auditService, event, err)
+	luc.metrics.TokenRefreshed(err == nil)
+
+	return response, err
+}
+
+func (luc refreshTokenUsecase) refresh(ctx context.Context, props dtos.RefreshTokenDTO, claims map[string]interface{}, auth models.Auth) (*dtos.RefreshTokenResponseDTO, error) {
 
-	if auth.GetEncryptToken() {
-		newAccessToken, err = luc.encryptService.Encrypt(ctx, *newAccessToken)
-		if err != nil {
-			return nil, exceptions.NewBusinessException("failed to encrypt access token")
+	// The new access token stays in the refresh token's session so revoking
+	// the session also revokes it.
+	options := TokenOptions{}
+	options.Scope, _ = claims["scope"].(string)
+	options.ClientID, _ = claims["client_id"].(string)
+	options.SessionID, _ = claims["sid"].(string)
+
+	// A refresh token can only mint access tokens for the audiences it was
+	// granted, so one product cannot obtain tokens for another. One granted
+	// none only mints tokens without an aud, as Login issued.
+	options.Audience = claimStrings(claims, "aud")
+	if len(props.Audience) > 0 {
+		for _, audience := range props.Audience {
+			if !slices.Contains(options.Audience, audience) {
+				return nil, exceptions.NewBusinessException("requested audience was not granted to this refresh token")
+			}
 		}
+		options.Audience = props.Audience
+	}
+
+	newAccessToken, err := luc.tokenIssuer.IssueAccessToken(ctx, auth, options)
+	if err != nil {
+		return nil, err
 	}
 
 	return &dtos.RefreshTokenResponseDTO{
@@ -69,4 +84,4 @@ func (luc refreshTokenUsecase) Execute(ctx context.Context, props dtos.RefreshTo
 			Roles:  auth.GetUserInfo().GetRoles(),
 		},
 	}, nil
-}
\ No newline at end of file
+}
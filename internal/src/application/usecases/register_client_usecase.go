@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type registerClientUsecase struct {
+	cfg             config.AuthConfig
+	clientRepo      repositories.IOAuthClientRepository
+	audienceKeyRepo repositories.IAudienceKeyRepository
+	encryptService  services.IEncryptService
+	metrics         services.IMetrics
+}
+
+func NewRegisterClientUsecase(
+	cfg config.AuthConfig,
+	clientRepo repositories.IOAuthClientRepository,
+	audienceKeyRepo repositories.IAudienceKeyRepository,
+	encryptService services.IEncryptService,
+	metrics services.IMetrics,
+) usecase.UseCaseWithProps[dtos.RegisterClientDTO, *dtos.RegisterClientResponseDTO] {
+	return &registerClientUsecase{
+		cfg:             cfg,
+		clientRepo:      clientRepo,
+		audienceKeyRepo: audienceKeyRepo,
+		encryptService:  encryptService,
+		metrics:         metrics,
+	}
+}
+
+func (uc registerClientUsecase) Execute(ctx context.Context, props dtos.RegisterClientDTO) (*dtos.RegisterClientResponseDTO, error) {
+	for _, redirectURI := range props.RedirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return nil, exceptions.NewBusinessException("redirect URIs must be absolute and must not contain a fragment")
+		}
+	}
+
+	// The client's encryption key is registered under its client ID, which
+	// is the audience of tokens issued to it without an explicit one.
+	var encryptionKey *services.PublicEncryptionKey
+	if props.EncryptionPublicKey != "" {
+		parsed, err := uc.encryptService.ParsePublicKey(props.EncryptionPublicKey)
+		if err != nil {
+			return nil, exceptions.NewBusinessException("invalid encryption public key: " + err.Error())
+		}
+		encryptionKey = parsed
+	}
+
+	var secret, secretHash *string
+	if !props.Public {
+		plainSecret, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, exceptions.NewBusinessException("failed to generate client secret")
+		}
+
+		hashed, err := hashPassword(uc.metrics, uc.cfg, plainSecret)
+		if err != nil {
+			return nil, exceptions.NewBusinessException("failed to hash client secret")
+		}
+
+		secret = &plainSecret
+		secretHash = &hashed
+	}
+
+	client, err := models.NewOAuthClient(models.OAuthClientProps{
+		Name:          props.Name,
+		SecretHash:    secretHash,
+		RedirectURIs:  props.RedirectURIs,
+		Public:        props.Public,
+		EncryptTokens: props.EncryptTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, er := uc.clientRepo.Save(ctx, client)
+	if er != nil {
+		return nil, er
+	}
+
+	if encryptionKey != nil {
+		key, err := models.NewAudienceKey(models.AudienceKeyProps{
+			Audience:  saved.GetClientID(),
+			KeyID:     encryptionKey.KeyID,
+			Algorithm: encryptionKey.Algorithm,
+			PublicJWK: encryptionKey.JWK,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.audienceKeyRepo.Save(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dtos.RegisterClientResponseDTO{
+		ClientID:     saved.GetClientID(),
+		ClientSecret: secret,
+	}, nil
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
-	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
 )
 
 type registerUsecase struct {
+	cfg config.AuthConfig
 	authRepo repositories.IAuthRepository
+	auditService services.IAuditService
+	metrics services.IMetrics
 }
 
 type checkResult struct {
@@ -20,13 +24,32 @@ type checkResult struct {
 	err    error
 }
 
-func NewRegisterUsecase(authRepo repositories.IAuthRepository) usecase.UseCaseWithProps[dtos.RegisterDTO, *dtos.RegisterResponseDTO] {
+func NewRegisterUsecase(cfg config.AuthConfig, authRepo repositories.IAuthRepository, auditService services.IAuditService, metrics services.IMetrics) usecase.UseCaseWithProps[dtos.RegisterDTO, *dtos.RegisterResponseDTO] {
 	return &registerUsecase{
+		cfg: cfg,
 		authRepo: authRepo,
+		auditService: auditService,
+		metrics: metrics,
 	}
 }
 
 func (luc registerUsecase) Execute(ctx context.Context, props dtos.RegisterDTO) (*dtos.RegisterResponseDTO, error) {
+	response, err := luc.register(ctx, props)
+
+	recordAudit(ctx, luc.auditService, services.AuditEvent{
+		Type:      auditEventRegister,
+		ActorID:   props.UserInfo.UserID,
+		SubjectID: props.UserInfo.UserID,
+		TenantID:  props.UserInfo.Metadata[tenantMetadataKey],
+		Details: map[string]interface{}{
+			"identifier_type": props.IdentifierType,
+		},
+	}, err)
+
+	return response, err
+}
+
+func (luc registerUsecase) register(ctx context.Context, props dtos.RegisterDTO) (*dtos.RegisterResponseDTO, error) {
     if  props.IdentifierValue == "" || props.Password == "" {
 		return nil, exceptions.NewBusinessException("identifier type, identifier value, and password are required")
 	}
@@ -64,25 +87,36 @@ func (luc registerUsecase) Execute(ctx context.Context, props dtos.RegisterDTO)
 	userInfo, err := models.NewUserInfo(models.UserInfoProps{
 		UserID: props.UserInfo.UserID,
 		Name:   props.UserInfo.Name,
-		Roles:  props.UserInfo.Roles,
+		Metadata: props.UserInfo.Metadata,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	hashedPassword, er := utils.HashPassword(props.Password)
+	hashedPassword, er := hashPassword(luc.metrics, luc.cfg, props.Password)
 	if er != nil {
 		return nil, exceptions.NewBusinessException("failed to hash password")
 	}
 
+	maxTokenAgeSeconds := props.MaxTokenAgeSeconds
+	if maxTokenAgeSeconds == nil {
+		age := int(luc.cfg.DefaultMaxTokenAge.Seconds())
+		maxTokenAgeSeconds = &age
+	}
+	maxWrongAttempts := props.MaxWrongAttempts
+	if maxWrongAttempts == nil {
+		limit := luc.cfg.DefaultMaxWrongAttempts
+		maxWrongAttempts = &limit
+	}
+
 	auth, err := models.NewAuth(models.AuthProps{
 		IdentifierType:     models.IdentifierType(props.IdentifierType),
 		IdentifierValue:    props.IdentifierValue,
 		Password:           hashedPassword,
 		UserInfo:           userInfo,
 		EncryptToken:       props.EncryptToken,
-		MaxTokenAgeSeconds: props.MaxTokenAgeSeconds,
-		MaxWrongAttempts:   props.MaxWrongAttempts,
+		MaxTokenAgeSeconds: maxTokenAgeSeconds,
+		MaxWrongAttempts:   maxWrongAttempts,
 	})
 	if err != nil {
 		return nil, err
@@ -99,6 +133,7 @@ func (luc registerUsecase) Execute(ctx context.Context, props dtos.RegisterDTO)
 			UserID: auth.GetUserInfo().GetUserID(),
 			Name:   auth.GetUserInfo().GetName(),
 			Roles:  auth.GetUserInfo().GetRoles(),
+			Metadata: auth.GetUserInfo().GetMetadata(),
 		},
 	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type retryWebhookDeliveryUsecase struct {
+	deliveryRepo repositories.IWebhookDeliveryRepository
+}
+
+func NewRetryWebhookDeliveryUsecase(deliveryRepo repositories.IWebhookDeliveryRepository) usecase.UseCaseWithProps[dtos.RetryWebhookDeliveryDTO, *dtos.WebhookDeliveryDTO] {
+	return &retryWebhookDeliveryUsecase{
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+// Execute takes a dead-lettered delivery out of the dead letter queue and
+// schedules it right away with a fresh set of attempts.
+func (uc retryWebhookDeliveryUsecase) Execute(ctx context.Context, props dtos.RetryWebhookDeliveryDTO) (*dtos.WebhookDeliveryDTO, error) {
+	if props.DeliveryID == "" {
+		return nil, exceptions.NewBusinessException("delivery ID is required")
+	}
+
+	delivery, err := uc.deliveryRepo.GetByID(ctx, props.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.GetStatus() != models.WebhookDeliveryDead {
+		return nil, exceptions.NewBusinessException("only dead-lettered deliveries can be retried")
+	}
+
+	delivery.Requeue(time.Now())
+	if err := uc.deliveryRepo.Save(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	response := webhookDeliveryToDTO(delivery)
+	return &response, nil
+}
@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	tokenTypeHintAccessToken  = "access_token"
+	tokenTypeHintRefreshToken = "refresh_token"
+)
+
+type revokeTokenUsecase struct {
+	clientRepo       repositories.IOAuthClientRepository
+	revokedTokenRepo repositories.IRevokedTokenRepository
+	tokenVerifier    *TokenVerifier
+	auditService     services.IAuditService
+	metrics          services.IMetrics
+}
+
+func NewRevokeTokenUsecase(
+	clientRepo repositories.IOAuthClientRepository,
+	revokedTokenRepo repositories.IRevokedTokenRepository,
+	tokenVerifier *TokenVerifier,
+	auditService services.IAuditService,
+	metrics services.IMetrics,
+) usecase.UseCaseWithProps[dtos.RevokeTokenDTO, *struct{}] {
+	return &revokeTokenUsecase{
+		clientRepo:       clientRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		tokenVerifier:    tokenVerifier,
+		auditService:     auditService,
+		metrics:          metrics,
+	}
+}
+
+// Execute implements RFC 7009. Revoking a refresh token revokes its whole
+// session, including every access token minted from it; revoking an access
+// token revokes only that token. Tokens that are already invalid or that the
+// client may not revoke are silently accepted, as section 2.2 requires.
+func (uc revokeTokenUsecase) Execute(ctx context.Context, props dtos.RevokeTokenDTO) (*struct{}, error) {
+	client, err := authenticateClient(ctx, uc.clientRepo, uc.metrics, props.ClientID, props.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if props.Token == "" {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "token is required")
+	}
+
+	// The hint only decides which type is tried first; an unknown hint is
+	// ignored and both types are tried.
+	verifiers := []func(context.Context, string) (map[string]interface{}, models.Auth, error){
+		uc.tokenVerifier.VerifyRefreshToken,
+		uc.tokenVerifier.VerifyAccessToken,
+	}
+	if props.TokenTypeHint == tokenTypeHintAccessToken {
+		verifiers[0], verifiers[1] = verifiers[1], verifiers[0]
+	}
+
+	for _, verify := range verifiers {
+		claims, auth, err := verify(ctx, props.Token)
+		if err != nil {
+			if isInvalidTokenError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		// Tokens from Login carry no client_id. Only confidential clients,
+		// which proved who they are, may revoke them; a public client is
+		// known by its client_id alone, which anyone can send. A token the
+		// client may not revoke is left alone, and the answer is the same
+		// as for an invalid one so it reveals nothing about it.
+		issuedTo, _ := claims["client_id"].(string)
+		if issuedTo != client.GetClientID() && (issuedTo != "" || client.IsPublic()) {
+			return &struct{}{}, nil
+		}
+
+		err = uc.revoke(ctx, props.Token, claims, auth)
+		if claims["type"] == "refresh" {
+			// Revoking a refresh token ends the session: the user logged out.
+			recordAudit(ctx, uc.auditService, services.AuditEvent{
+				Type:      auditEventLogout,
+				ActorID:   auth.GetUserInfo().GetUserID(),
+				SubjectID: auth.GetUserInfo().GetUserID(),
+				ClientID:  client.GetClientID(),
+				TenantID:  auditTenant(auth.GetUserInfo()),
+			}, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &struct{}{}, nil
+	}
+
+	return &struct{}{}, nil
+}
+
+func (uc revokeTokenUsecase) revoke(ctx context.Context, token string, claims map[string]interface{}, auth models.Auth) error {
+	expiresAt := time.Now()
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = time.Unix(int64(exp), 0)
+	}
+
+	revocationID := tokenID(token, claims)
+	if claims["type"] == "refresh" {
+		if sessionID, _ := claims["sid"].(string); sessionID != "" {
+			// Access tokens refreshed right before the refresh token expires
+			// outlive it by at most their own lifetime.
+			revocationID = sessionID
+			expiresAt = expiresAt.Add(time.Duration(*auth.GetMaxTokenAgeSeconds()) * time.Second)
+		}
+	}
+
+	revoked, er := models.NewRevokedToken(models.RevokedTokenProps{
+		TokenID:   revocationID,
+		UserID:    auth.GetUserInfo().GetUserID(),
+		ExpiresAt: expiresAt,
+	})
+	if er != nil {
+		return er
+	}
+
+	return uc.revokedTokenRepo.Save(ctx, revoked)
+}
+
+// isInvalidTokenError reports whether err means the token itself is not
+// usable, as opposed to an infrastructure failure.
+func isInvalidTokenError(err error) bool {
+	var businessErr *exceptions.BusinessException
+	var notFound *exceptions.RepositoryNoDataFoundException
+	return errors.As(err, &businessErr) || errors.As(err, &notFound)
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+type fakeAuditService struct {
+	services.IAuditService
+	events []services.AuditEvent
+}
+
+func (s *fakeAuditService) Record(ctx context.Context, event services.AuditEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientID    string
+		public      bool
+		token       string
+		tokenClient string
+		tokenType   string
+		wantRevoked string
+	}{
+		{name: "own access token", clientID: "app", token: "access-1", tokenClient: "app", tokenType: "access", wantRevoked: "jti-access-1"},
+		{name: "own refresh token ends the session", clientID: "app", token: "refresh-1", tokenClient: "app", tokenType: "refresh", wantRevoked: "session-1"},
+		{name: "token of another client", clientID: "app", token: "access-1", tokenClient: "other", tokenType: "access"},
+		{name: "public client, own token", clientID: "spa", public: true, token: "access-1", tokenClient: "spa", tokenType: "access", wantRevoked: "jti-access-1"},
+		{name: "public client, Login token", clientID: "spa", public: true, token: "access-1", tokenType: "access"},
+		{name: "confidential client, Login token", clientID: "app", token: "access-1", tokenType: "access", wantRevoked: "jti-access-1"},
+		{name: "unknown token", clientID: "app", token: "garbage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTokenFixture(t)
+			f.addClient(t, tt.clientID, tt.public)
+			if tt.tokenType != "" {
+				f.addToken(tt.token, tt.tokenType, tt.tokenClient, map[string]interface{}{"sid": "session-1"})
+			}
+			audit := &fakeAuditService{}
+
+			uc := NewRevokeTokenUsecase(f.clients, f.revoked, f.verifier, audit, f.metrics)
+			props := dtos.RevokeTokenDTO{Token: tt.token, ClientID: tt.clientID}
+			if !tt.public {
+				props.ClientSecret = testClientSecret
+			}
+			if _, err := uc.Execute(context.Background(), props); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if tt.wantRevoked == "" {
+				if len(f.revoked.revoked) != 0 {
+					t.Errorf("revoked %v, want nothing revoked", f.revoked.revoked)
+				}
+				return
+			}
+			if _, ok := f.revoked.revoked[tt.wantRevoked]; !ok || len(f.revoked.revoked) != 1 {
+				t.Errorf("revoked %v, want only %s", f.revoked.revoked, tt.wantRevoked)
+			}
+
+			wantLogout := tt.tokenType == "refresh"
+			if loggedOut := len(audit.events) == 1 && audit.events[0].Type == auditEventLogout; loggedOut != wantLogout {
+				t.Errorf("audit events = %+v, want a logout %v", audit.events, wantLogout)
+			}
+		})
+	}
+}
+
+func TestRevokeTokenRequiresClientAuthentication(t *testing.T) {
+	f := newTokenFixture(t)
+	f.addClient(t, "app", false)
+	f.addToken("access-1", "access", "app", nil)
+
+	uc := NewRevokeTokenUsecase(f.clients, f.revoked, f.verifier, &fakeAuditService{}, f.metrics)
+	_, err := uc.Execute(context.Background(), dtos.RevokeTokenDTO{Token: "access-1", ClientID: "app", ClientSecret: "wrong"})
+	wantOAuthError(t, err, models.OAuthErrorInvalidClient)
+	if len(f.revoked.revoked) != 0 {
+		t.Errorf("revoked %v, want nothing revoked", f.revoked.revoked)
+	}
+}
@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type rotateEncryptionKeyUsecase struct {
+	encryptService services.IEncryptService
+}
+
+func NewRotateEncryptionKeyUsecase(encryptService services.IEncryptService) usecase.UseCaseWithProps[dtos.RotateEncryptionKeyDTO, *dtos.EncryptionKeyDTO] {
+	return &rotateEncryptionKeyUsecase{
+		encryptService: encryptService,
+	}
+}
+
+func (uc rotateEncryptionKeyUsecase) Execute(ctx context.Context, props dtos.RotateEncryptionKeyDTO) (*dtos.EncryptionKeyDTO, error) {
+	if props.ActivateInSeconds < 0 {
+		return nil, exceptions.NewBusinessException("activation delay cannot be negative")
+	}
+
+	activateAt := time.Now().Add(time.Duration(props.ActivateInSeconds) * time.Second)
+
+	key, err := uc.encryptService.RotateEncryptionKey(ctx, props.Algorithm, activateAt)
+	if err != nil {
+		return nil, err
+	}
+
+	response := encryptionKeyToDTO(key, time.Now())
+	return &response, nil
+}
+
+// encryptionKeyToDTO reports a key as in use while tokens encrypted with it
+// are still accepted.
+func encryptionKeyToDTO(key models.EncryptionKey, now time.Time) dtos.EncryptionKeyDTO {
+	return dtos.EncryptionKeyDTO{
+		KeyID:       key.GetKeyID(),
+		Algorithm:   key.GetAlgorithm(),
+		State:       key.GetState(),
+		InUse:       key.CanDecrypt(now),
+		ActivatesAt: key.GetActivatesAt(),
+		ExpiresAt:   key.GetExpiresAt(),
+		RemoveAt:    key.GetRemoveAt(),
+		LastUsedAt:  key.GetLastUsedAt(),
+		CreatedAt:   key.GetCreatedAt(),
+	}
+}
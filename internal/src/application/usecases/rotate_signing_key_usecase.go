@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type rotateSigningKeyUsecase struct {
+	jwtService services.IJWTService
+}
+
+func NewRotateSigningKeyUsecase(jwtService services.IJWTService) usecase.UseCaseWithProps[dtos.RotateSigningKeyDTO, *dtos.SigningKeyDTO] {
+	return &rotateSigningKeyUsecase{
+		jwtService: jwtService,
+	}
+}
+
+func (uc rotateSigningKeyUsecase) Execute(ctx context.Context, props dtos.RotateSigningKeyDTO) (*dtos.SigningKeyDTO, error) {
+	if props.Purpose != models.SigningKeyPurposeAccess && props.Purpose != models.SigningKeyPurposeRefresh {
+		return nil, exceptions.NewBusinessException("signing key purpose must be access or refresh")
+	}
+	if props.ActivateInSeconds < 0 {
+		return nil, exceptions.NewBusinessException("activation delay cannot be negative")
+	}
+
+	activateAt := time.Now().Add(time.Duration(props.ActivateInSeconds) * time.Second)
+
+	key, err := uc.jwtService.RotateSigningKey(ctx, props.Purpose, props.Algorithm, activateAt)
+	if err != nil {
+		return nil, err
+	}
+
+	response := signingKeyToDTO(key)
+	return &response, nil
+}
+
+func signingKeyToDTO(key models.SigningKey) dtos.SigningKeyDTO {
+	return dtos.SigningKeyDTO{
+		KeyID:       key.GetKeyID(),
+		Purpose:     key.GetPurpose(),
+		Algorithm:   key.GetAlgorithm(),
+		State:       key.GetState(),
+		ActivatesAt: key.GetActivatesAt(),
+		ExpiresAt:   key.GetExpiresAt(),
+		RemoveAt:    key.GetRemoveAt(),
+		CreatedAt:   key.GetCreatedAt(),
+	}
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type setAudienceKeyUsecase struct {
+	audienceKeyRepo repositories.IAudienceKeyRepository
+	encryptService  services.IEncryptService
+}
+
+func NewSetAudienceKeyUsecase(audienceKeyRepo repositories.IAudienceKeyRepository, encryptService services.IEncryptService) usecase.UseCaseWithProps[dtos.SetAudienceKeyDTO, *dtos.AudienceKeyDTO] {
+	return &setAudienceKeyUsecase{
+		audienceKeyRepo: audienceKeyRepo,
+		encryptService:  encryptService,
+	}
+}
+
+// Execute registers the public key access tokens for props.Audience are
+// encrypted to, replacing any previous one. An empty key removes it, after
+// which tokens for the audience are encrypted to authgate's own key again.
+func (uc setAudienceKeyUsecase) Execute(ctx context.Context, props dtos.SetAudienceKeyDTO) (*dtos.AudienceKeyDTO, error) {
+	if props.Audience == "" {
+		return nil, exceptions.NewBusinessException("audience is required")
+	}
+
+	if props.PublicKey == "" {
+		if err := uc.audienceKeyRepo.Delete(ctx, props.Audience); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	key, err := newAudienceKey(uc.encryptService, props.Audience, props.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.audienceKeyRepo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dtos.AudienceKeyDTO{
+		Audience:  key.GetAudience(),
+		KeyID:     key.GetKeyID(),
+		Algorithm: key.GetAlgorithm(),
+		CreatedAt: key.GetCreatedAt(),
+	}, nil
+}
+
+func newAudienceKey(encryptService services.IEncryptService, audience, publicKey string) (models.AudienceKey, error) {
+	parsed, err := encryptService.ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, exceptions.NewBusinessException("invalid public key: " + err.Error())
+	}
+
+	key, er := models.NewAudienceKey(models.AudienceKeyProps{
+		Audience:  audience,
+		KeyID:     parsed.KeyID,
+		Algorithm: parsed.Algorithm,
+		PublicJWK: parsed.JWK,
+	})
+	if er != nil {
+		return nil, er
+	}
+
+	return key, nil
+}
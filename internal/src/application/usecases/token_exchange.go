@@ -0,0 +1,252 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const (
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+	// tokenTypeUserID names the subject by user id instead of by token. It
+	// is how support staff impersonate a user whose token they do not hold,
+	// and policy only allows it together with an actor token.
+	tokenTypeUserID = "urn:authgate:params:oauth:token-type:user_id"
+
+	auditEventTokenExchange   = "token_exchange"
+	defaultExchangeTTLSeconds = 300
+)
+
+// exchangeParty is one side of a token exchange: the authenticated user and
+// the claims of the token that identified them, if any.
+type exchangeParty struct {
+	auth   models.Auth
+	claims map[string]interface{}
+}
+
+// exchangeToken implements the RFC 8693 token exchange grant. The issued
+// access token belongs to the subject, names the actor in its act claim and
+// can only be narrower than the subject token: a subset of its scopes and
+// audiences and a shorter lifetime. Every attempt is audited.
+func (uc tokenUsecase) exchangeToken(ctx context.Context, props dtos.TokenDTO) (*dtos.TokenResponseDTO, error) {
+	client, err := authenticateClient(ctx, uc.clientRepo, uc.metrics, props.ClientID, props.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.IsPublic() {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "token exchange requires a confidential client")
+	}
+
+	event := services.AuditEvent{
+		Type:     auditEventTokenExchange,
+		ClientID: client.GetClientID(),
+		Details: map[string]interface{}{
+			"subject_token_type": props.SubjectTokenType,
+			"audience":           props.Audience,
+			"scope":              props.Scope,
+		},
+	}
+
+	response, err := uc.performTokenExchange(ctx, client, props, &event)
+	if err != nil {
+		event.Outcome = services.AuditOutcomeDenied
+		event.Reason = err.Error()
+		uc.auditService.Record(ctx, event)
+		return nil, err
+	}
+
+	event.Outcome = services.AuditOutcomeSuccess
+	event.Details["scope"] = response.Scope
+	event.Details["expires_in"] = response.ExpiresIn
+	uc.auditService.Record(ctx, event)
+
+	return response, nil
+}
+
+func (uc tokenUsecase) performTokenExchange(ctx context.Context, client models.OAuthClient, props dtos.TokenDTO, event *services.AuditEvent) (*dtos.TokenResponseDTO, error) {
+	if props.RequestedTokenType != "" && props.RequestedTokenType != tokenTypeAccessToken {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "only access tokens can be requested")
+	}
+	if props.SubjectToken == "" || props.SubjectTokenType == "" {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "subject_token and subject_token_type are required")
+	}
+	if len(props.Audience) == 0 {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidTarget, "audience is required")
+	}
+
+	var actor *exchangeParty
+	if props.ActorToken != "" {
+		if props.ActorTokenType != tokenTypeAccessToken {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "actor_token_type must be an access token")
+		}
+
+		claims, auth, err := uc.tokenVerifier.VerifyAccessToken(ctx, props.ActorToken)
+		if err != nil {
+			return nil, exchangeTokenError(err, "actor token is invalid")
+		}
+		actor = &exchangeParty{auth: auth, claims: claims}
+		event.ActorID = auth.GetUserInfo().GetUserID()
+	}
+
+	subject, err := uc.exchangeSubject(ctx, props, actor)
+	if err != nil {
+		return nil, err
+	}
+	event.SubjectID = subject.auth.GetUserInfo().GetUserID()
+
+	scopes := strings.Fields(props.Scope)
+	if props.SubjectTokenType == tokenTypeAccessToken {
+		scopes, err = narrowToSubjectToken(subject.claims, props.Audience, scopes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request := models.TokenExchangeRequest{
+		ClientID:      client.GetClientID(),
+		HasActor:      actor != nil,
+		SubjectRoles:  subject.auth.GetUserInfo().GetRoles(),
+		Impersonation: props.SubjectTokenType == tokenTypeUserID,
+		Audiences:     props.Audience,
+		Scopes:        scopes,
+	}
+	if actor != nil {
+		request.ActorRoles = actor.auth.GetUserInfo().GetRoles()
+	}
+
+	rule := uc.exchangePolicy.Evaluate(ctx, request)
+	if rule == nil {
+		return nil, models.NewOAuthError(models.OAuthErrorUnauthorizedClient, "token exchange is not allowed by policy")
+	}
+	if len(scopes) == 0 {
+		scopes = slices.Clone(rule.Scopes)
+	}
+
+	expiresIn := exchangeTTL(subject, rule)
+	if expiresIn <= 0 {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "subject token is about to expire")
+	}
+
+	// The exchanged token lives in the session of whoever presented a token
+	// for the subject, so signing that session out also ends the exchange.
+	sessionID, _ := subject.claims["sid"].(string)
+	if request.Impersonation {
+		sessionID, _ = actor.claims["sid"].(string)
+	}
+
+	scope := strings.Join(scopes, " ")
+	accessToken, err := uc.tokenIssuer.IssueAccessToken(ctx, subject.auth, TokenOptions{
+		Scope:     scope,
+		ClientID:  client.GetClientID(),
+		SessionID: sessionID,
+		Audience:  props.Audience,
+		Actor:     actClaim(client, actor, subject),
+		ExpiresIn: expiresIn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.TokenResponseDTO{
+		AccessToken:     *accessToken,
+		IssuedTokenType: tokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       expiresIn,
+		Scope:           scope,
+	}, nil
+}
+
+// exchangeSubject resolves the user the new token is issued for, either
+// from their access token or, for impersonation, from their user id.
+func (uc tokenUsecase) exchangeSubject(ctx context.Context, props dtos.TokenDTO, actor *exchangeParty) (*exchangeParty, error) {
+	switch props.SubjectTokenType {
+	case tokenTypeAccessToken:
+		claims, auth, err := uc.tokenVerifier.VerifyAccessToken(ctx, props.SubjectToken)
+		if err != nil {
+			return nil, exchangeTokenError(err, "subject token is invalid")
+		}
+		return &exchangeParty{auth: auth, claims: claims}, nil
+	case tokenTypeUserID:
+		if actor == nil {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "impersonation requires an actor token")
+		}
+		auth, err := uc.authRepo.GetByUserID(ctx, props.SubjectToken)
+		if err != nil {
+			return nil, exchangeTokenError(err, "subject does not exist")
+		}
+		return &exchangeParty{auth: auth, claims: map[string]interface{}{}}, nil
+	default:
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "unsupported subject_token_type")
+	}
+}
+
+// narrowToSubjectToken keeps a delegated token within the subject token:
+// every requested audience must be in its aud and every requested scope in
+// its scope, which is also what is granted when no scope is requested. A
+// subject token without an aud or a scope, such as one from Login, is not
+// restricted there, so that part is bounded by the policy rule alone, as
+// impersonation is.
+func narrowToSubjectToken(claims map[string]interface{}, audiences []string, scopes []string) ([]string, error) {
+	if subjectAudience := claimStrings(claims, "aud"); len(subjectAudience) > 0 {
+		for _, audience := range audiences {
+			if !slices.Contains(subjectAudience, audience) {
+				return nil, models.NewOAuthError(models.OAuthErrorInvalidTarget, "requested audience exceeds the subject token")
+			}
+		}
+	}
+
+	subjectScope, _ := claims["scope"].(string)
+	if len(strings.Fields(subjectScope)) == 0 {
+		return scopes, nil
+	}
+	if len(scopes) == 0 {
+		return strings.Fields(subjectScope), nil
+	}
+	for _, scope := range scopes {
+		if !hasScope(subjectScope, scope) {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidScope, "requested scope exceeds the subject token")
+		}
+	}
+
+	return scopes, nil
+}
+
+// exchangeTTL caps the lifetime of an exchanged token by the policy, by
+// defaultExchangeTTLSeconds and by what is left of the subject token.
+func exchangeTTL(subject *exchangeParty, rule *models.TokenExchangeRule) int {
+	ttl := defaultExchangeTTLSeconds
+	if rule.MaxTTLSeconds > 0 {
+		ttl = min(ttl, rule.MaxTTLSeconds)
+	}
+	if exp, ok := subject.claims["exp"].(float64); ok {
+		ttl = min(ttl, int(time.Until(time.Unix(int64(exp), 0)).Seconds()))
+	}
+	return ttl
+}
+
+// actClaim builds the RFC 8693 act claim. The actor is the user behind the
+// actor token, or the client itself when none was presented; an act claim
+// already on the subject token is nested to keep the delegation chain.
+func actClaim(client models.OAuthClient, actor *exchangeParty, subject *exchangeParty) map[string]interface{} {
+	act := map[string]interface{}{"client_id": client.GetClientID()}
+	if actor != nil {
+		act["sub"] = actor.auth.GetUserInfo().GetUserID()
+	}
+	if previous, ok := subject.claims["act"].(map[string]interface{}); ok {
+		act["act"] = previous
+	}
+	return act
+}
+
+func exchangeTokenError(err error, description string) error {
+	if isInvalidTokenError(err) {
+		return models.NewOAuthError(models.OAuthErrorInvalidGrant, description)
+	}
+	return err
+}
@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+)
+
+const testExchangePolicy = `{
+  "rules": [
+    {
+      "client_id": "gateway",
+      "audiences": ["orders"],
+      "scopes": ["orders:read"],
+      "max_ttl_seconds": 60
+    },
+    {
+      "client_id": "support-console",
+      "actor_roles": ["support"],
+      "impersonate": true,
+      "excluded_subject_roles": ["admin"],
+      "audiences": ["orders"],
+      "scopes": ["orders:read"]
+    }
+  ]
+}`
+
+func newTestExchange(t *testing.T) (*tokenFixture, *fakeAuditService, func(dtos.TokenDTO) (*dtos.TokenResponseDTO, error)) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(testExchangePolicy), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	f := newTokenFixture(t)
+	f.addClient(t, "gateway", false)
+	f.addClient(t, "support-console", false)
+	f.addClient(t, "other", false)
+	f.addUser(t, "agent-1", "support")
+	f.addUser(t, "admin-1", "admin")
+
+	// A delegated token, a token from Login that names neither scope nor
+	// audience, and the token of a support agent.
+	f.addToken("delegated", "access", "app", map[string]interface{}{
+		"scope": "orders:read orders:write",
+		"aud":   []interface{}{"orders", "billing"},
+		"sid":   "session-1",
+	})
+	f.addToken("login", "access", "", map[string]interface{}{"sid": "session-2"})
+	f.addToken("agent", "access", "", map[string]interface{}{"sub": "agent-1", "sid": "session-3"})
+
+	audit := &fakeAuditService{}
+	issuer := NewTokenIssuer(config.AuthConfig{}, f.jwt, fakeEncryptService{}, f.clients, nil, f.metrics, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	uc := NewTokenUsecase(f.clients, nil, f.users, issuer, f.verifier, adapters.NewTokenExchangePolicy(config.TokenExchangeConfig{PolicyFile: path}), audit, f.metrics)
+
+	exchange := func(props dtos.TokenDTO) (*dtos.TokenResponseDTO, error) {
+		props.GrantType = grantTypeTokenExchange
+		props.ClientSecret = testClientSecret
+		if props.ActorToken != "" {
+			props.ActorTokenType = tokenTypeAccessToken
+		}
+		if props.SubjectTokenType == "" {
+			props.SubjectTokenType = tokenTypeAccessToken
+		}
+		return uc.Execute(context.Background(), props)
+	}
+
+	return f, audit, exchange
+}
+
+func TestTokenExchangePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		props     dtos.TokenDTO
+		wantScope string
+		wantErr   string
+	}{
+		{
+			name:      "narrower than the subject token",
+			props:     dtos.TokenDTO{ClientID: "gateway", SubjectToken: "delegated", Audience: []string{"orders"}, Scope: "orders:read"},
+			wantScope: "orders:read",
+		},
+		{
+			name:    "scope beyond the subject token",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "delegated", Audience: []string{"orders"}, Scope: "orders:admin"},
+			wantErr: models.OAuthErrorInvalidScope,
+		},
+		{
+			name:    "audience beyond the subject token",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "delegated", Audience: []string{"shipping"}},
+			wantErr: models.OAuthErrorInvalidTarget,
+		},
+		{
+			name:    "subject scope beyond the policy",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "delegated", Audience: []string{"orders"}},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:    "subject audience beyond the policy",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "delegated", Audience: []string{"billing"}, Scope: "orders:read"},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:      "Login token gets the policy scopes",
+			props:     dtos.TokenDTO{ClientID: "gateway", SubjectToken: "login", Audience: []string{"orders"}},
+			wantScope: "orders:read",
+		},
+		{
+			name:    "Login token, scope beyond the policy",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "login", Audience: []string{"orders"}, Scope: "orders:write"},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:    "Login token, audience beyond the policy",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "login", Audience: []string{"billing"}},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:    "client without a rule",
+			props:   dtos.TokenDTO{ClientID: "other", SubjectToken: "login", Audience: []string{"orders"}},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:    "no audience",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "login"},
+			wantErr: models.OAuthErrorInvalidTarget,
+		},
+		{
+			name:      "impersonation by a support agent",
+			props:     dtos.TokenDTO{ClientID: "support-console", SubjectToken: "user-1", SubjectTokenType: tokenTypeUserID, ActorToken: "agent", Audience: []string{"orders"}},
+			wantScope: "orders:read",
+		},
+		{
+			name:    "impersonation without an actor",
+			props:   dtos.TokenDTO{ClientID: "support-console", SubjectToken: "user-1", SubjectTokenType: tokenTypeUserID, Audience: []string{"orders"}},
+			wantErr: models.OAuthErrorInvalidRequest,
+		},
+		{
+			name:    "impersonation of an excluded subject",
+			props:   dtos.TokenDTO{ClientID: "support-console", SubjectToken: "admin-1", SubjectTokenType: tokenTypeUserID, ActorToken: "agent", Audience: []string{"orders"}},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+		{
+			name:    "impersonation under a rule without it",
+			props:   dtos.TokenDTO{ClientID: "gateway", SubjectToken: "user-1", SubjectTokenType: tokenTypeUserID, ActorToken: "agent", Audience: []string{"orders"}},
+			wantErr: models.OAuthErrorUnauthorizedClient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, audit, exchange := newTestExchange(t)
+
+			got, err := exchange(tt.props)
+			if len(audit.events) != 1 || audit.events[0].Type != auditEventTokenExchange {
+				t.Errorf("audit events = %+v, want one token exchange", audit.events)
+			}
+			if tt.wantErr != "" {
+				wantOAuthError(t, err, tt.wantErr)
+				if len(f.jwt.issued) != 0 {
+					t.Errorf("issued %+v, want no token", f.jwt.issued)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got.Scope != tt.wantScope || got.IssuedTokenType != tokenTypeAccessToken {
+				t.Errorf("Execute() = %+v, want scope %q", got, tt.wantScope)
+			}
+			issued := f.jwt.issued[0]
+			if issued.Scope != tt.wantScope || !reflect.DeepEqual(issued.Audience, tt.props.Audience) {
+				t.Errorf("issued %+v, want scope %q and audience %v", issued, tt.wantScope, tt.props.Audience)
+			}
+		})
+	}
+}
+
+func TestTokenExchangeNamesTheActor(t *testing.T) {
+	f, _, exchange := newTestExchange(t)
+
+	got, err := exchange(dtos.TokenDTO{
+		ClientID:         "support-console",
+		SubjectToken:     "user-1",
+		SubjectTokenType: tokenTypeUserID,
+		ActorToken:       "agent",
+		Audience:         []string{"orders"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	issued := f.jwt.issued[0]
+	wantAct := map[string]interface{}{"client_id": "support-console", "sub": "agent-1"}
+	if issued.UserID != "user-1" || !reflect.DeepEqual(issued.Actor, wantAct) {
+		t.Errorf("issued to %s with act %v, want user-1 with act %v", issued.UserID, issued.Actor, wantAct)
+	}
+	// An impersonation token lives in the agent's session.
+	if issued.SessionID != "session-3" {
+		t.Errorf("session = %s, want the actor's session-3", issued.SessionID)
+	}
+	if got.ExpiresIn <= 0 || got.ExpiresIn > defaultExchangeTTLSeconds {
+		t.Errorf("expires_in = %d, want at most %d", got.ExpiresIn, defaultExchangeTTLSeconds)
+	}
+}
+
+func TestTokenExchangeTTLIsCappedByPolicy(t *testing.T) {
+	_, _, exchange := newTestExchange(t)
+
+	got, err := exchange(dtos.TokenDTO{ClientID: "gateway", SubjectToken: "login", Audience: []string{"orders"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got.ExpiresIn != 60 {
+		t.Errorf("expires_in = %d, want the policy's 60", got.ExpiresIn)
+	}
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+// TokenOptions carries the grant specific inputs of an issued token. The
+// session id is shared by a refresh token and every access token minted from
+// it, which is what lets a sign-out revoke them as one family. ExpiresIn
+// shortens the user's MaxTokenAgeSeconds when set.
+type TokenOptions struct {
+	Scope     string
+	ClientID  string
+	SessionID string
+	Audience  []string
+	Actor     map[string]interface{}
+	ExpiresIn int
+}
+
+// TokenIssuer is the single pipeline through which every grant mints tokens,
+// so per-user settings such as MaxTokenAgeSeconds and EncryptToken are
+// honoured no matter how the user authenticated.
+type TokenIssuer struct {
+	cfg             config.AuthConfig
+	jwtService      services.IJWTService
+	encryptService  services.IEncryptService
+	clientRepo      repositories.IOAuthClientRepository
+	audienceKeyRepo repositories.IAudienceKeyRepository
+	metrics         services.IMetrics
+	logger          *slog.Logger
+}
+
+func NewTokenIssuer(
+	cfg config.AuthConfig,
+	jwtService services.IJWTService,
+	encryptService services.IEncryptService,
+	clientRepo repositories.IOAuthClientRepository,
+	audienceKeyRepo repositories.IAudienceKeyRepository,
+	metrics services.IMetrics,
+	logger *slog.Logger,
+) *TokenIssuer {
+	return &TokenIssuer{
+		cfg:             cfg,
+		jwtService:      jwtService,
+		encryptService:  encryptService,
+		clientRepo:      clientRepo,
+		audienceKeyRepo: audienceKeyRepo,
+		metrics:         metrics,
+		logger:          logger,
+	}
+}
+
+func (t *TokenIssuer) IssueAccessToken(ctx context.Context, auth models.Auth, options TokenOptions) (*string, error) {
+	expiresIn := *auth.GetMaxTokenAgeSeconds()
+	if options.ExpiresIn > 0 && options.ExpiresIn < expiresIn {
+		expiresIn = options.ExpiresIn
+	}
+
+	accessToken, err := t.jwtService.GenerateToken(ctx, services.AccessTokenClaims{
+		UserID:    auth.GetUserInfo().GetUserID(),
+		Roles:     auth.GetUserInfo().GetRoles(),
+		ExpiresIn: expiresIn,
+		Scope:     options.Scope,
+		ClientID:  options.ClientID,
+		SessionID: options.SessionID,
+		Audience:  options.Audience,
+		Actor:     options.Actor,
+		Metadata:  auth.GetUserInfo().GetMetadata(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encrypt, err := t.requiresEncryption(ctx, auth, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if encrypt {
+		accessToken, err = t.encryptAccessToken(ctx, *accessToken, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.metrics.TokenIssued("access")
+	return accessToken, nil
+}
+
+// requiresEncryption reports whether the access token must be encrypted,
+// either because the user asked for it or because the client it is issued
+// to does.
+func (t *TokenIssuer) requiresEncryption(ctx context.Context, auth models.Auth, options TokenOptions) (bool, error) {
+	if auth.GetEncryptToken() {
+		return true, nil
+	}
+	if options.ClientID == "" {
+		return false, nil
+	}
+
+	client, err := t.clientRepo.GetByClientID(ctx, options.ClientID)
+	if err != nil {
+		return false, err
+	}
+	return client.GetEncryptTokens(), nil
+}
+
+// encryptAccessToken encrypts for the token's target audience, its single
+// aud or else the client it was issued to, when that audience registered a
+// key. Tokens for audiences without a key are encrypted to authgate's own
+// key as before. A JWE has a single recipient, so an encrypted token cannot
+// be addressed to several audiences that registered keys.
+func (t *TokenIssuer) encryptAccessToken(ctx context.Context, accessToken string, options TokenOptions) (*string, error) {
+	audiences := options.Audience
+	if len(audiences) == 0 && options.ClientID != "" {
+		audiences = []string{options.ClientID}
+	}
+
+	var recipient models.AudienceKey
+	for _, audience := range audiences {
+		key, err := t.audienceKeyRepo.GetByAudience(ctx, audience)
+		if err != nil {
+			var notFound *exceptions.RepositoryNoDataFoundException
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, err
+		}
+		if recipient != nil {
+			return nil, exceptions.NewBusinessException("encrypted tokens can only be issued for one audience with a registered key")
+		}
+		recipient = key
+	}
+
+	var encrypted *string
+	var err error
+	if recipient != nil {
+		encrypted, err = t.encryptService.EncryptFor(ctx, accessToken, recipient)
+	} else {
+		encrypted, err = t.encryptService.Encrypt(ctx, accessToken)
+	}
+	if err != nil {
+		t.logger.ErrorContext(ctx, "Failed to encrypt access token", "error", err)
+		return nil, exceptions.NewBusinessException("failed to encrypt access token")
+	}
+
+	return encrypted, nil
+}
+
+func (t *TokenIssuer) IssueRefreshToken(ctx context.Context, auth models.Auth, options TokenOptions) (*string, error) {
+	refreshToken, err := t.jwtService.GenerateRefreshToken(ctx, services.RefreshTokenClaims{
+		UserID:    auth.GetUserInfo().GetUserID(),
+		ExpiresIn: int(t.cfg.RefreshTokenTTL.Seconds()),
+		Scope:     options.Scope,
+		ClientID:  options.ClientID,
+		SessionID: options.SessionID,
+		Audience:  options.Audience,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.GetEncryptToken() {
+		refreshToken, err = t.encryptService.Encrypt(ctx, *refreshToken)
+		if err != nil {
+			return nil, exceptions.NewBusinessException("failed to encrypt refresh token")
+		}
+	}
+
+	t.metrics.TokenIssued("refresh")
+	return refreshToken, nil
+}
+
+// IssueIDToken mints an OpenID Connect ID token for clientID. ID tokens are
+// consumed by the relying party itself and are never encrypted.
+func (t *TokenIssuer) IssueIDToken(ctx context.Context, auth models.Auth, clientID, scope, nonce string, authTime time.Time) (*string, error) {
+	claims := standardClaims(auth, scope)
+	claims["aud"] = clientID
+	claims["auth_time"] = authTime.Unix()
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	idToken, err := t.jwtService.GenerateIDToken(ctx, claims, *auth.GetMaxTokenAgeSeconds())
+	if err != nil {
+		return nil, err
+	}
+
+	t.metrics.TokenIssued("id")
+	return idToken, nil
+}
+
+// standardClaims maps a user onto the OpenID Connect standard claims
+// released for the given scopes. The email and phone_number claims come from
+// the identifier the user registered with.
+func standardClaims(auth models.Auth, scope string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": auth.GetUserInfo().GetUserID(),
+	}
+
+	if hasScope(scope, models.ScopeProfile) && auth.GetUserInfo().GetName() != "" {
+		claims["name"] = auth.GetUserInfo().GetName()
+	}
+	if hasScope(scope, models.ScopeEmail) && auth.GetIdentifierType() == models.IdentifierEmail {
+		claims["email"] = auth.GetIdentifierValue()
+	}
+	if hasScope(scope, models.ScopePhone) && auth.GetIdentifierType() == models.IdentifierPhone {
+		claims["phone_number"] = auth.GetIdentifierValue()
+	}
+
+	return claims
+}
+
+// newSessionID starts a token family. It is called once per successful
+// authentication; refreshes carry the id over.
+func newSessionID() string {
+	return utils.GenerateUUID()
+}
+
+func hasScope(scope, wanted string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == wanted {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const grantTypeAuthorizationCode = "authorization_code"
+
+type tokenUsecase struct {
+	clientRepo     repositories.IOAuthClientRepository
+	codeRepo       repositories.IAuthorizationCodeRepository
+	authRepo       repositories.IAuthRepository
+	tokenIssuer    *TokenIssuer
+	tokenVerifier  *TokenVerifier
+	exchangePolicy services.ITokenExchangePolicy
+	auditService   services.IAuditService
+	metrics        services.IMetrics
+}
+
+func NewTokenUsecase(
+	clientRepo repositories.IOAuthClientRepository,
+	codeRepo repositories.IAuthorizationCodeRepository,
+	authRepo repositories.IAuthRepository,
+	tokenIssuer *TokenIssuer,
+	tokenVerifier *TokenVerifier,
+	exchangePolicy services.ITokenExchangePolicy,
+	auditService services.IAuditService,
+	metrics services.IMetrics,
+) usecase.UseCaseWithProps[dtos.TokenDTO, *dtos.TokenResponseDTO] {
+	return &tokenUsecase{
+		clientRepo:     clientRepo,
+		codeRepo:       codeRepo,
+		authRepo:       authRepo,
+		tokenIssuer:    tokenIssuer,
+		tokenVerifier:  tokenVerifier,
+		exchangePolicy: exchangePolicy,
+		auditService:   auditService,
+		metrics:        metrics,
+	}
+}
+
+func (uc tokenUsecase) Execute(ctx context.Context, props dtos.TokenDTO) (*dtos.TokenResponseDTO, error) {
+	switch props.GrantType {
+	case grantTypeAuthorizationCode:
+		return uc.exchangeAuthorizationCode(ctx, props)
+	case grantTypeTokenExchange:
+		return uc.exchangeToken(ctx, props)
+	case "":
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "grant_type is required")
+	default:
+		return nil, models.NewOAuthError(models.OAuthErrorUnsupportedGrantType, "")
+	}
+}
+
+func (uc tokenUsecase) exchangeAuthorizationCode(ctx context.Context, props dtos.TokenDTO) (*dtos.TokenResponseDTO, error) {
+	if props.Code == "" || props.CodeVerifier == "" {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidRequest, "code and code_verifier are required")
+	}
+
+	client, err := authenticateClient(ctx, uc.clientRepo, uc.metrics, props.ClientID, props.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// The code is consumed before any further check so that a failed
+	// redemption also burns it, as RFC 6749 section 4.1.2 recommends.
+	code, err := uc.codeRepo.Consume(ctx, utils.HashToken(props.Code))
+	if err != nil {
+		var notFound *exceptions.RepositoryNoDataFoundException
+		if errors.As(err, &notFound) {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "authorization code is invalid or has already been used")
+		}
+		return nil, err
+	}
+
+	if code.IsExpired(time.Now()) {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "authorization code has expired")
+	}
+	if code.GetClientID() != client.GetClientID() {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "authorization code was issued to another client")
+	}
+	if code.GetRedirectURI() != props.RedirectURI {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "redirect_uri does not match the authorization request")
+	}
+	if !verifyCodeChallenge(props.CodeVerifier, code.GetCodeChallenge()) {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidGrant, "code_verifier does not match the code challenge")
+	}
+
+	auth, err := uc.authRepo.GetByUserID(ctx, code.GetUserID())
+	if err != nil {
+		return nil, err
+	}
+
+	options := TokenOptions{
+		Scope:     code.GetScope(),
+		ClientID:  client.GetClientID(),
+		SessionID: newSessionID(),
+	}
+
+	accessToken, err := uc.tokenIssuer.IssueAccessToken(ctx, auth, options)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := uc.tokenIssuer.IssueRefreshToken(ctx, auth, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.TokenResponseDTO{
+		AccessToken:  *accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    *auth.GetMaxTokenAgeSeconds(),
+		RefreshToken: *refreshToken,
+		Scope:        code.GetScope(),
+	}
+
+	if hasScope(code.GetScope(), models.ScopeOpenID) {
+		idToken, err := uc.tokenIssuer.IssueIDToken(ctx, auth, client.GetClientID(), code.GetScope(), code.GetNonce(), code.GetAuthTime())
+		if err != nil {
+			return nil, err
+		}
+		response.IDToken = *idToken
+	}
+
+	return response, nil
+}
+
+// authenticateClient resolves the calling client. Confidential clients must
+// present their secret; public clients are identified by client_id alone and
+// rely on PKCE instead.
+func authenticateClient(ctx context.Context, clientRepo repositories.IOAuthClientRepository, metrics services.IMetrics, clientID, clientSecret string) (models.OAuthClient, error) {
+	if clientID == "" {
+		return nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "client authentication failed")
+	}
+
+	client, err := clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		var notFound *exceptions.RepositoryNoDataFoundException
+		if errors.As(err, &notFound) {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "client authentication failed")
+		}
+		return nil, err
+	}
+
+	if !client.IsPublic() {
+		if clientSecret == "" || !checkPassword(metrics, clientSecret, *client.GetSecretHash()) {
+			return nil, models.NewOAuthError(models.OAuthErrorInvalidClient, "client authentication failed")
+		}
+	}
+
+	return client, nil
+}
+
+// isValidPKCEValue checks the RFC 7636 syntax shared by code verifiers and
+// S256 code challenges: 43 to 128 characters from the unreserved set.
+func isValidPKCEValue(value string) bool {
+	if len(value) < 43 || len(value) > 128 {
+		return false
+	}
+
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-', r == '.', r == '_', r == '~':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if !isValidPKCEValue(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// testCodeChallenge is BASE64URL(SHA256(testCodeVerifier)), worked out
+// with openssl.
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gXk-kXk24"
+	testCodeChallenge = "-WDrWIR1t4n9_BmFZP3i3z3ut7rovByRcY4Q7bb8O5U"
+)
+
+type fakeClientRepo struct {
+	clients map[string]models.OAuthClient
+}
+
+func (r *fakeClientRepo) Save(ctx context.Context, client models.OAuthClient) (models.OAuthClient, error) {
+	r.clients[client.GetClientID()] = client
+	return client, nil
+}
+
+func (r *fakeClientRepo) GetByClientID(ctx context.Context, clientID string) (models.OAuthClient, error) {
+	client, ok := r.clients[clientID]
+	if !ok {
+		return nil, exceptions.NewRepositoryNoDataFoundException("client not found")
+	}
+	return client, nil
+}
+
+type fakeCodeRepo struct {
+	codes map[string]models.AuthorizationCode
+}
+
+func (r *fakeCodeRepo) Save(ctx context.Context, code models.AuthorizationCode) error {
+	r.codes[code.GetCodeHash()] = code
+	return nil
+}
+
+func (r *fakeCodeRepo) Consume(ctx context.Context, codeHash string) (models.AuthorizationCode, error) {
+	code, ok := r.codes[codeHash]
+	if !ok {
+		return nil, exceptions.NewRepositoryNoDataFoundException("authorization code not found")
+	}
+	delete(r.codes, codeHash)
+	return code, nil
+}
+
+func newTestClient(t *testing.T, props models.OAuthClientProps) models.OAuthClient {
+	t.Helper()
+	if props.Name == "" {
+		props.Name = "test client"
+	}
+	if len(props.RedirectURIs) == 0 {
+		props.RedirectURIs = []string{"https://app.example/callback"}
+	}
+	client, err := models.NewOAuthClient(props)
+	if err != nil {
+		t.Fatalf("NewOAuthClient() error = %v", err)
+	}
+	return client
+}
+
+func wantOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *models.OAuthError
+	if !errors.As(err, &oauthErr) {
+		t.Fatalf("error = %v, want OAuth error %s", err, code)
+	}
+	if oauthErr.Code != code {
+		t.Fatalf("error = %v, want OAuth error %s", err, code)
+	}
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		want     bool
+	}{
+		{name: "matching verifier", verifier: testCodeVerifier, want: true},
+		{name: "other verifier", verifier: strings.Repeat("a", 43), want: false},
+		{name: "plain method", verifier: testCodeChallenge, want: false},
+		{name: "too short", verifier: testCodeVerifier[:42], want: false},
+		{name: "invalid characters", verifier: testCodeVerifier[:42] + "+", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.verifier, testCodeChallenge); got != tt.want {
+				t.Errorf("verifyCodeChallenge(%q) = %v, want %v", tt.verifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizationCodeExchangeRequiresVerifier(t *testing.T) {
+	client := newTestClient(t, models.OAuthClientProps{Public: true})
+	code, er := models.NewAuthorizationCode(models.AuthorizationCodeProps{
+		CodeHash:            utils.HashToken("code-1"),
+		ClientID:            client.GetClientID(),
+		UserID:              "user-1",
+		RedirectURI:         "https://app.example/callback",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: models.CodeChallengeMethodS256,
+		ExpiresAt:           time.Now().Add(time.Minute),
+	})
+	if er != nil {
+		t.Fatalf("NewAuthorizationCode() error = %v", er)
+	}
+
+	codes := &fakeCodeRepo{codes: map[string]models.AuthorizationCode{code.GetCodeHash(): code}}
+	uc := NewTokenUsecase(
+		&fakeClientRepo{clients: map[string]models.OAuthClient{client.GetClientID(): client}},
+		codes, nil, nil, nil, nil, nil, nil,
+	)
+
+	props := dtos.TokenDTO{
+		GrantType:    grantTypeAuthorizationCode,
+		Code:         "code-1",
+		RedirectURI:  "https://app.example/callback",
+		ClientID:     client.GetClientID(),
+		CodeVerifier: strings.Repeat("a", 43),
+	}
+	_, err := uc.Execute(context.Background(), props)
+	wantOAuthError(t, err, models.OAuthErrorInvalidGrant)
+
+	// A failed redemption burns the code, so the right verifier is too
+	// late.
+	props.CodeVerifier = testCodeVerifier
+	_, err = uc.Execute(context.Background(), props)
+	wantOAuthError(t, err, models.OAuthErrorInvalidGrant)
+	if len(codes.codes) != 0 {
+		t.Errorf("%d codes left, want the code consumed", len(codes.codes))
+	}
+}
+
+func TestValidateAuthorizeRequestRequiresS256Challenge(t *testing.T) {
+	client := newTestClient(t, models.OAuthClientProps{Public: true})
+	clients := &fakeClientRepo{clients: map[string]models.OAuthClient{client.GetClientID(): client}}
+	valid := dtos.AuthorizeRequestDTO{
+		ResponseType:        "code",
+		ClientID:            client.GetClientID(),
+		RedirectURI:         "https://app.example/callback",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: models.CodeChallengeMethodS256,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*dtos.AuthorizeRequestDTO)
+	}{
+		{name: "no challenge", modify: func(r *dtos.AuthorizeRequestDTO) { r.CodeChallenge = "" }},
+		{name: "plain method", modify: func(r *dtos.AuthorizeRequestDTO) { r.CodeChallengeMethod = "plain" }},
+		{name: "malformed challenge", modify: func(r *dtos.AuthorizeRequestDTO) { r.CodeChallenge = "short" }},
+	}
+
+	if _, redirectErr, err := validateAuthorizeRequest(context.Background(), clients, valid); err != nil || redirectErr != nil {
+		t.Fatalf("valid request: redirect error = %v, error = %v", redirectErr, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid
+			tt.modify(&request)
+
+			_, redirectErr, err := validateAuthorizeRequest(context.Background(), clients, request)
+			if err != nil {
+				t.Fatalf("error = %v, want the problem reported through the redirect", err)
+			}
+			if redirectErr == nil {
+				t.Fatal("request accepted")
+			}
+			wantOAuthError(t, redirectErr, models.OAuthErrorInvalidRequest)
+		})
+	}
+}
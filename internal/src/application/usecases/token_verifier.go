@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// TokenVerifier validates access and refresh tokens for every endpoint that
+// accepts them, transparently decrypting tokens issued with EncryptToken and
+// rejecting tokens that were revoked before they expired.
+type TokenVerifier struct {
+	authRepo         repositories.IAuthRepository
+	revokedTokenRepo repositories.IRevokedTokenRepository
+	jwtService       services.IJWTService
+	encryptService   services.IEncryptService
+	metrics          services.IMetrics
+}
+
+func NewTokenVerifier(
+	authRepo repositories.IAuthRepository,
+	revokedTokenRepo repositories.IRevokedTokenRepository,
+	jwtService services.IJWTService,
+	encryptService services.IEncryptService,
+	metrics services.IMetrics,
+) *TokenVerifier {
+	return &TokenVerifier{
+		authRepo:         authRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		jwtService:       jwtService,
+		encryptService:   encryptService,
+		metrics:          metrics,
+	}
+}
+
+func (v *TokenVerifier) VerifyAccessToken(ctx context.Context, accessToken string) (map[string]interface{}, models.Auth, error) {
+	return v.VerifyAccessTokenFor(ctx, accessToken, services.TokenExpectations{})
+}
+
+// VerifyAccessTokenFor is VerifyAccessToken for a caller that only accepts
+// tokens minted for a given audience or by a given issuer.
+func (v *TokenVerifier) VerifyAccessTokenFor(ctx context.Context, accessToken string, expectations services.TokenExpectations) (map[string]interface{}, models.Auth, error) {
+	claims, auth, err := v.verifyAccessToken(ctx, accessToken, expectations)
+	v.countVerification("access", err)
+	return claims, auth, err
+}
+
+func (v *TokenVerifier) VerifyRefreshToken(ctx context.Context, refreshToken string) (map[string]interface{}, models.Auth, error) {
+	claims, auth, err := v.verifyRefreshToken(ctx, refreshToken)
+	v.countVerification("refresh", err)
+	return claims, auth, err
+}
+
+func (v *TokenVerifier) verifyAccessToken(ctx context.Context, accessToken string, expectations services.TokenExpectations) (map[string]interface{}, models.Auth, error) {
+	if accessToken == "" {
+		return nil, nil, exceptions.NewBusinessException("access token is required")
+	}
+
+	accessToken, err := v.decrypt(ctx, accessToken)
+	if err != nil {
+		return nil, nil, exceptions.NewBusinessException("invalid access token")
+	}
+
+	claims, err := v.jwtService.ExtractClaims(ctx, accessToken, expectations)
+	if err != nil {
+		return nil, nil, exceptions.NewBusinessException("invalid access token")
+	}
+
+	return v.checkToken(ctx, accessToken, claims, "access token has been revoked")
+}
+
+func (v *TokenVerifier) verifyRefreshToken(ctx context.Context, refreshToken string) (map[string]interface{}, models.Auth, error) {
+	if refreshToken == "" {
+		return nil, nil, exceptions.NewBusinessException("refresh token is required")
+	}
+
+	refreshToken, err := v.decrypt(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, exceptions.NewBusinessException("invalid refresh token")
+	}
+
+	claims, err := v.jwtService.ExtractRefreshClaims(ctx, refreshToken)
+	if err != nil {
+		return nil, nil, exceptions.NewBusinessException("invalid refresh token")
+	}
+
+	return v.checkToken(ctx, refreshToken, claims, "refresh token has been revoked")
+}
+
+// countVerification counts a verification as valid or invalid. Errors that
+// say nothing about the token, such as a failing database, are not counted.
+func (v *TokenVerifier) countVerification(tokenType string, err error) {
+	if err == nil || isInvalidTokenError(err) {
+		v.metrics.TokenVerified(tokenType, err == nil)
+	}
+}
+
+// decrypt unwraps tokens issued with EncryptToken, choosing how from the
+// token's envelope. Plain JWTs are returned unchanged; an encrypted token
+// that fails to decrypt is an error rather than being passed on as a JWT.
+func (v *TokenVerifier) decrypt(ctx context.Context, token string) (string, error) {
+	if v.encryptService.Envelope(token) == services.TokenEnvelopeNone {
+		return token, nil
+	}
+	return v.encryptService.Decrypt(ctx, token)
+}
+
+func (v *TokenVerifier) checkToken(ctx context.Context, token string, claims map[string]interface{}, revokedMessage string) (map[string]interface{}, models.Auth, error) {
+	revoked, err := v.revokedTokenRepo.IsRevoked(ctx, revocationIDs(token, claims)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revoked {
+		return nil, nil, exceptions.NewBusinessException(revokedMessage)
+	}
+
+	auth, err := v.authRepo.GetByUserID(ctx, claims["sub"].(string))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return claims, auth, nil
+}
+
+// revocationIDs lists the ids a revocation of this token can be recorded
+// under: its own id and, when it belongs to one, its session. Tokens minted
+// before jti was introduced are identified by the hash of the signed JWT.
+func revocationIDs(token string, claims map[string]interface{}) []string {
+	ids := []string{tokenID(token, claims)}
+	if sessionID, _ := claims["sid"].(string); sessionID != "" {
+		ids = append(ids, sessionID)
+	}
+	return ids
+}
+
+func tokenID(token string, claims map[string]interface{}) string {
+	if jti, _ := claims["jti"].(string); jti != "" {
+		return jti
+	}
+	return utils.HashToken(token)
+}
+
+// claimStrings reads a claim that may hold a single string or an array of
+// strings, as aud does.
+func claimStrings(claims map[string]interface{}, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// claimRoles reads the roles claim. Tokens issued before roles became a
+// JSON array carry them as a comma separated string.
+func claimRoles(claims map[string]interface{}) []string {
+	if roles, ok := claims["roles"].(string); ok {
+		if roles == "" {
+			return nil
+		}
+		return strings.Split(roles, ",")
+	}
+	return claimStrings(claims, "roles")
+}
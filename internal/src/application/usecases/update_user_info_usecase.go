@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type updateUserInfoUsecase struct {
+	authRepo     repositories.IAuthRepository
+	auditService services.IAuditService
+}
+
+func NewUpdateUserInfoUsecase(authRepo repositories.IAuthRepository, auditService services.IAuditService) usecase.UseCaseWithProps[dtos.UpdateUserInfoDTO, *dtos.UserInfoDTO] {
+	return &updateUserInfoUsecase{
+		authRepo:     authRepo,
+		auditService: auditService,
+	}
+}
+
+// Execute renames the user when a name is given and merges the metadata:
+// keys in Metadata are set, keys in RemoveMetadataKeys are deleted and the
+// rest is kept. Roles are granted and revoked the same way, and a change of
+// roles is audited.
+func (uc updateUserInfoUsecase) Execute(ctx context.Context, props dtos.UpdateUserInfoDTO) (*dtos.UserInfoDTO, error) {
+	if props.UserID == "" {
+		return nil, exceptions.NewBusinessException("user ID is required")
+	}
+
+	auth, err := uc.authRepo.GetByUserID(ctx, props.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo := auth.GetUserInfo()
+	if props.Name != nil {
+		userInfo.Rename(*props.Name)
+	}
+	if err := userInfo.UpdateMetadata(props.Metadata, props.RemoveMetadataKeys); err != nil {
+		return nil, err
+	}
+
+	added, removed := userInfo.UpdateRoles(props.AddRoles, props.RemoveRoles)
+
+	err = uc.authRepo.UpdateUserInfo(ctx, userInfo)
+	if len(added) > 0 || len(removed) > 0 {
+		recordAudit(ctx, uc.auditService, services.AuditEvent{
+			Type:      auditEventRoleChange,
+			SubjectID: userInfo.GetUserID(),
+			TenantID:  auditTenant(userInfo),
+			Details: map[string]interface{}{
+				"added":   added,
+				"removed": removed,
+			},
+		}, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.UserInfoDTO{
+		UserID:   userInfo.GetUserID(),
+		Name:     userInfo.GetName(),
+		Roles:    userInfo.GetRoles(),
+		Metadata: userInfo.GetMetadata(),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	auditChainBatchSize   = 500
+	maxAuditChainProblems = 100
+
+	auditProblemGap        = "gap"
+	auditProblemModified   = "modified"
+	auditProblemBrokenLink = "broken_link"
+	auditProblemCheckpoint = "checkpoint"
+)
+
+type verifyAuditChainUsecase struct {
+	auditEventRepo      repositories.IAuditEventRepository
+	auditCheckpointRepo repositories.IAuditCheckpointRepository
+	auditService        services.IAuditService
+}
+
+func NewVerifyAuditChainUsecase(
+	auditEventRepo repositories.IAuditEventRepository,
+	auditCheckpointRepo repositories.IAuditCheckpointRepository,
+	auditService services.IAuditService,
+) usecase.UseCaseWithProps[dtos.VerifyAuditChainDTO, *dtos.AuditChainReportDTO] {
+	return &verifyAuditChainUsecase{
+		auditEventRepo:      auditEventRepo,
+		auditCheckpointRepo: auditCheckpointRepo,
+		auditService:        auditService,
+	}
+}
+
+// Execute walks the chain over the events that occurred in [From, To). Each
+// event must follow the previous one without a gap, link to its hash and
+// still hash to what was stored; each checkpoint in the range must carry a
+// valid signature over the hash of the event it names. Verification starts
+// from the event before the range so a modified first event is caught too,
+// or from the purge anchor when the range starts at the oldest event kept.
+func (uc verifyAuditChainUsecase) Execute(ctx context.Context, props dtos.VerifyAuditChainDTO) (*dtos.AuditChainReportDTO, error) {
+	if props.From != nil && props.To != nil && props.From.After(*props.To) {
+		return nil, exceptions.NewBusinessException("from must not be after to")
+	}
+
+	report := &dtos.AuditChainReportDTO{Problems: []dtos.AuditChainProblemDTO{}}
+
+	first, last, err := uc.auditEventRepo.SequenceRange(ctx, props.From, props.To)
+	if err != nil {
+		return nil, err
+	}
+	if first == 0 {
+		report.Valid = true
+		return report, nil
+	}
+	report.FirstSequence = first
+	report.LastSequence = last
+
+	oldest, newest, err := uc.auditEventRepo.SequenceRange(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := uc.auditCheckpointRepo.ListFrom(ctx, first)
+	if err != nil {
+		return nil, err
+	}
+	pending := map[int64]models.AuditCheckpoint{}
+	for _, checkpoint := range checkpoints {
+		if props.To != nil && checkpoint.GetSequence() > last {
+			break
+		}
+		if checkpoint.GetSequence() > newest {
+			// Events that were signed for are gone from the end of the log.
+			addAuditProblem(report, checkpoint.GetSequence(), auditProblemGap,
+				fmt.Sprintf("checkpoint covers sequence %d but the log ends at %d", checkpoint.GetSequence(), newest))
+			continue
+		}
+		if err := uc.auditService.VerifyCheckpoint(ctx, checkpoint); err != nil {
+			addAuditProblem(report, checkpoint.GetSequence(), auditProblemCheckpoint, err.Error())
+			continue
+		}
+		report.CheckpointsVerified++
+		pending[checkpoint.GetSequence()] = checkpoint
+	}
+
+	next := first
+	var prevHash *string
+	if first > oldest {
+		next = first - 1
+	} else if oldest > 1 {
+		if prevHash, err = uc.verifyAnchor(ctx, report, oldest); err != nil {
+			return nil, err
+		}
+	}
+
+	for next <= last {
+		events, err := uc.auditEventRepo.ListBySequence(ctx, next, last, auditChainBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			addAuditProblem(report, next, auditProblemGap, fmt.Sprintf("events %d to %d are missing", next, last))
+			break
+		}
+
+		for _, event := range events {
+			sequence := event.GetSequence()
+			if sequence != next {
+				addAuditProblem(report, next, auditProblemGap, fmt.Sprintf("events %d to %d are missing", next, sequence-1))
+				prevHash = nil
+			}
+
+			if prevHash != nil && event.GetPrevHash() != *prevHash {
+				addAuditProblem(report, sequence, auditProblemBrokenLink, "previous hash does not match the preceding event")
+			}
+			if event.GetHash() != event.ComputeHash() {
+				addAuditProblem(report, sequence, auditProblemModified, "event content does not match its hash")
+			}
+			if checkpoint, ok := pending[sequence]; ok && checkpoint.GetHash() != event.GetHash() {
+				addAuditProblem(report, sequence, auditProblemCheckpoint, "event hash differs from the signed checkpoint")
+			}
+
+			if sequence >= first {
+				report.EventsChecked++
+			}
+
+			hash := event.GetHash()
+			prevHash = &hash
+			next = sequence + 1
+		}
+	}
+
+	report.Valid = len(report.Problems) == 0
+	return report, nil
+}
+
+// verifyAnchor checks that the events before oldest were dropped by the
+// retention purge: the latest anchor must be validly signed for the event
+// right before oldest, or for a later one when a purge stopped after signing
+// it, in which case it is verified with the other checkpoints. It returns
+// the hash the oldest event must link to, when the anchor is right before it.
+func (uc verifyAuditChainUsecase) verifyAnchor(ctx context.Context, report *dtos.AuditChainReportDTO, oldest int64) (*string, error) {
+	anchor, err := uc.auditCheckpointRepo.GetLatestAnchor(ctx)
+	var notFound *exceptions.RepositoryNoDataFoundException
+	if errors.As(err, &notFound) {
+		addAuditProblem(report, oldest, auditProblemGap,
+			fmt.Sprintf("events before %d are missing and no purge anchor accounts for them", oldest))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case anchor.GetSequence() < oldest-1:
+		addAuditProblem(report, oldest, auditProblemGap,
+			fmt.Sprintf("events %d to %d are missing but were not purged", anchor.GetSequence()+1, oldest-1))
+		return nil, nil
+	case anchor.GetSequence() >= oldest:
+		return nil, nil
+	}
+
+	if err := uc.auditService.VerifyCheckpoint(ctx, anchor); err != nil {
+		addAuditProblem(report, anchor.GetSequence(), auditProblemCheckpoint, err.Error())
+		return nil, nil
+	}
+	report.CheckpointsVerified++
+
+	hash := anchor.GetHash()
+	return &hash, nil
+}
+
+// addAuditProblem records a problem, keeping the report to a readable size.
+func addAuditProblem(report *dtos.AuditChainReportDTO, sequence int64, kind, message string) {
+	if len(report.Problems) >= maxAuditChainProblems {
+		return
+	}
+
+	report.Problems = append(report.Problems, dtos.AuditChainProblemDTO{
+		Sequence: sequence,
+		Kind:     kind,
+		Message:  message,
+	})
+}
@@ -0,0 +1,476 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// fakeAuditEventRepo keeps the audit log in memory: chained events by
+// sequence and pending events in the order they were saved.
+type fakeAuditEventRepo struct {
+	repositories.IAuditEventRepository
+	chained map[int64]models.AuditEvent
+	pending []models.AuditEvent
+}
+
+func (r *fakeAuditEventRepo) Save(ctx context.Context, event models.AuditEvent) error {
+	r.pending = append(r.pending, event)
+	return nil
+}
+
+func (r *fakeAuditEventRepo) Seal(ctx context.Context, limit int) (int, error) {
+	sequence, prevHash := int64(1), ""
+	if head := r.sequences(); len(head) > 0 {
+		last := r.chained[head[len(head)-1]]
+		sequence, prevHash = last.GetSequence()+1, last.GetHash()
+	}
+
+	sealed := 0
+	for len(r.pending) > 0 && sealed < limit {
+		event := r.pending[0]
+		r.pending = r.pending[1:]
+		event.Chain(sequence, prevHash)
+		r.chained[sequence] = event
+		sequence, prevHash = sequence+1, event.GetHash()
+		sealed++
+	}
+	return sealed, nil
+}
+
+func (r *fakeAuditEventRepo) SequenceRange(ctx context.Context, from, to *time.Time) (int64, int64, error) {
+	var first, last int64
+	for _, sequence := range r.sequences() {
+		occurredAt := r.chained[sequence].GetOccurredAt()
+		if (from != nil && occurredAt.Before(*from)) || (to != nil && !occurredAt.Before(*to)) {
+			continue
+		}
+		if first == 0 {
+			first = sequence
+		}
+		last = sequence
+	}
+	return first, last, nil
+}
+
+func (r *fakeAuditEventRepo) ListBySequence(ctx context.Context, first, last int64, limit int) ([]models.AuditEvent, error) {
+	events := []models.AuditEvent{}
+	for _, sequence := range r.sequences() {
+		if sequence >= first && sequence <= last && len(events) < limit {
+			events = append(events, r.chained[sequence])
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeAuditEventRepo) DeleteBefore(ctx context.Context, before time.Time, sequence int64) (int64, error) {
+	var deleted int64
+	for _, stored := range r.sequences() {
+		if stored < sequence {
+			delete(r.chained, stored)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *fakeAuditEventRepo) sequences() []int64 {
+	sequences := make([]int64, 0, len(r.chained))
+	for sequence := range r.chained {
+		sequences = append(sequences, sequence)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	return sequences
+}
+
+// tamper replaces the event at sequence with a copy changed by modify.
+func (r *fakeAuditEventRepo) tamper(t *testing.T, sequence int64, rehash bool, modify func(*models.AuditEventProps)) {
+	t.Helper()
+
+	event := r.chained[sequence]
+	props := models.AuditEventProps{
+		ID:         event.GetID(),
+		Type:       event.GetType(),
+		Outcome:    event.GetOutcome(),
+		SubjectID:  event.GetSubjectID(),
+		OccurredAt: event.GetOccurredAt(),
+		Sequence:   event.GetSequence(),
+		PrevHash:   event.GetPrevHash(),
+		Hash:       event.GetHash(),
+	}
+	modify(&props)
+
+	tampered, er := models.LoadAuditEvent(props)
+	if er != nil {
+		t.Fatalf("LoadAuditEvent() error = %v", er)
+	}
+	if rehash {
+		tampered.Chain(props.Sequence, props.PrevHash)
+	}
+	r.chained[sequence] = tampered
+}
+
+type fakeAuditCheckpointRepo struct {
+	checkpoints map[int64]models.AuditCheckpoint
+}
+
+func (r *fakeAuditCheckpointRepo) Save(ctx context.Context, checkpoint models.AuditCheckpoint) error {
+	if _, ok := r.checkpoints[checkpoint.GetSequence()]; !ok || checkpoint.IsAnchor() {
+		r.checkpoints[checkpoint.GetSequence()] = checkpoint
+	}
+	return nil
+}
+
+func (r *fakeAuditCheckpointRepo) GetLatest(ctx context.Context) (models.AuditCheckpoint, error) {
+	return r.latest(func(models.AuditCheckpoint) bool { return true })
+}
+
+func (r *fakeAuditCheckpointRepo) GetLatestAnchor(ctx context.Context) (models.AuditCheckpoint, error) {
+	return r.latest(models.AuditCheckpoint.IsAnchor)
+}
+
+func (r *fakeAuditCheckpointRepo) ListFrom(ctx context.Context, sequence int64) ([]models.AuditCheckpoint, error) {
+	checkpoints := []models.AuditCheckpoint{}
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.GetSequence() >= sequence {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].GetSequence() < checkpoints[j].GetSequence() })
+	return checkpoints, nil
+}
+
+func (r *fakeAuditCheckpointRepo) DeleteBefore(ctx context.Context, sequence int64) error {
+	for stored := range r.checkpoints {
+		if stored < sequence {
+			delete(r.checkpoints, stored)
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuditCheckpointRepo) latest(match func(models.AuditCheckpoint) bool) (models.AuditCheckpoint, error) {
+	var latest models.AuditCheckpoint
+	for _, checkpoint := range r.checkpoints {
+		if match(checkpoint) && (latest == nil || checkpoint.GetSequence() > latest.GetSequence()) {
+			latest = checkpoint
+		}
+	}
+	if latest == nil {
+		return nil, exceptions.NewRepositoryNoDataFoundException("no audit checkpoint found")
+	}
+	return latest, nil
+}
+
+type fakeSigningKeyRepo struct {
+	keys []models.SigningKey
+}
+
+func (r *fakeSigningKeyRepo) Save(ctx context.Context, key models.SigningKey) error {
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeSigningKeyRepo) List(ctx context.Context) ([]models.SigningKey, error) {
+	return r.keys, nil
+}
+
+func (r *fakeSigningKeyRepo) Delete(ctx context.Context, keyID string) error {
+	return nil
+}
+
+// auditLogFixture is an audit log kept for a day, with the real audit
+// service chaining, signing and purging it.
+type auditLogFixture struct {
+	events      *fakeAuditEventRepo
+	checkpoints *fakeAuditCheckpointRepo
+	service     services.IAuditService
+}
+
+func newAuditLogFixture(t *testing.T) *auditLogFixture {
+	t.Helper()
+
+	f := &auditLogFixture{
+		events:      &fakeAuditEventRepo{chained: map[int64]models.AuditEvent{}},
+		checkpoints: &fakeAuditCheckpointRepo{checkpoints: map[int64]models.AuditCheckpoint{}},
+	}
+	f.service = adapters.NewAuditService(
+		config.AuditConfig{Retention: 24 * time.Hour, SigningAlgorithm: "EdDSA"},
+		f.events, f.checkpoints, &fakeSigningKeyRepo{},
+		adapters.NewKeyManagementService(config.KMSConfig{MasterKey: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	return f
+}
+
+// record appends one event per occurrence time to the chain.
+func (f *auditLogFixture) record(t *testing.T, occurredAt ...time.Time) {
+	t.Helper()
+
+	for i, at := range occurredAt {
+		event, er := models.NewAuditEvent(models.AuditEventProps{
+			Type:       auditEventLogout,
+			Outcome:    services.AuditOutcomeSuccess,
+			SubjectID:  fmt.Sprintf("user-%d", i),
+			OccurredAt: at.UTC().Truncate(time.Microsecond),
+		})
+		if er != nil {
+			t.Fatalf("NewAuditEvent() error = %v", er)
+		}
+		f.events.Save(context.Background(), event)
+	}
+	if err := f.service.Seal(context.Background()); err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+}
+
+func (f *auditLogFixture) checkpoint(t *testing.T) {
+	t.Helper()
+	if err := f.service.Checkpoint(context.Background()); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+}
+
+func (f *auditLogFixture) verify(t *testing.T, props dtos.VerifyAuditChainDTO) *dtos.AuditChainReportDTO {
+	t.Helper()
+
+	report, err := NewVerifyAuditChainUsecase(f.events, f.checkpoints, f.service).Execute(context.Background(), props)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return report
+}
+
+// minutesAgo returns n times, one minute apart, ending a minute ago, at
+// the precision the events are stored with.
+func minutesAgo(n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = time.Now().Add(-time.Duration(n-i) * time.Minute).UTC().Truncate(time.Microsecond)
+	}
+	return times
+}
+
+func wantAuditProblems(t *testing.T, report *dtos.AuditChainReportDTO, want ...dtos.AuditChainProblemDTO) {
+	t.Helper()
+
+	if report.Valid != (len(want) == 0) {
+		t.Errorf("Valid = %v with problems %+v", report.Valid, report.Problems)
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("problems = %+v, want %+v", report.Problems, want)
+	}
+	for i, problem := range report.Problems {
+		if problem.Sequence != want[i].Sequence || problem.Kind != want[i].Kind {
+			t.Errorf("problem %d = %+v, want %s at %d", i, problem, want[i].Kind, want[i].Sequence)
+		}
+	}
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(*testing.T, *auditLogFixture)
+		want   []dtos.AuditChainProblemDTO
+	}{
+		{
+			name:   "intact",
+			damage: func(*testing.T, *auditLogFixture) {},
+		},
+		{
+			name: "modified event",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				f.events.tamper(t, 3, false, func(p *models.AuditEventProps) { p.SubjectID = "someone-else" })
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 3, Kind: auditProblemModified}},
+		},
+		{
+			name: "modified and rehashed event",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				f.events.tamper(t, 3, true, func(p *models.AuditEventProps) { p.SubjectID = "someone-else" })
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 4, Kind: auditProblemBrokenLink}},
+		},
+		{
+			name: "rehashed event under a checkpoint",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				f.events.tamper(t, 6, true, func(p *models.AuditEventProps) { p.SubjectID = "someone-else" })
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 6, Kind: auditProblemCheckpoint}},
+		},
+		{
+			name: "deleted event",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				delete(f.events.chained, 4)
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 4, Kind: auditProblemGap}},
+		},
+		{
+			name: "deleted end of the log",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				delete(f.events.chained, 6)
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 6, Kind: auditProblemGap}},
+		},
+		{
+			name: "deleted start of the log",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				delete(f.events.chained, 1)
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 2, Kind: auditProblemGap}},
+		},
+		{
+			name: "forged checkpoint",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				checkpoint := f.checkpoints.checkpoints[6]
+				forged, _ := models.LoadAuditCheckpoint(models.AuditCheckpointProps{
+					Sequence:  6,
+					Hash:      f.events.chained[5].GetHash(),
+					KeyID:     checkpoint.GetKeyID(),
+					Signature: checkpoint.GetSignature(),
+				})
+				f.checkpoints.checkpoints[6] = forged
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 6, Kind: auditProblemCheckpoint}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuditLogFixture(t)
+			f.record(t, minutesAgo(6)...)
+			f.checkpoint(t)
+
+			tt.damage(t, f)
+
+			report := f.verify(t, dtos.VerifyAuditChainDTO{})
+			wantAuditProblems(t, report, tt.want...)
+		})
+	}
+}
+
+func TestVerifyAuditChainRangeStartsBeforeIt(t *testing.T) {
+	f := newAuditLogFixture(t)
+	times := minutesAgo(6)
+	f.record(t, times...)
+
+	// Event 3 opens the range; its link to event 2, outside the range, is
+	// still checked.
+	f.events.tamper(t, 3, true, func(p *models.AuditEventProps) { p.PrevHash = "" })
+
+	report := f.verify(t, dtos.VerifyAuditChainDTO{From: &times[2], To: &times[4]})
+	if report.FirstSequence != 3 || report.LastSequence != 4 || report.EventsChecked != 2 {
+		t.Errorf("report covers %d to %d with %d events, want 3 to 4 with 2", report.FirstSequence, report.LastSequence, report.EventsChecked)
+	}
+	wantAuditProblems(t, report,
+		dtos.AuditChainProblemDTO{Sequence: 3, Kind: auditProblemBrokenLink},
+		dtos.AuditChainProblemDTO{Sequence: 4, Kind: auditProblemBrokenLink},
+	)
+}
+
+func TestVerifyAuditChainAfterPurge(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	newTimes := minutesAgo(2)
+
+	tests := []struct {
+		name   string
+		damage func(*testing.T, *auditLogFixture)
+		want   []dtos.AuditChainProblemDTO
+	}{
+		{
+			name:   "purged by retention",
+			damage: func(*testing.T, *auditLogFixture) {},
+		},
+		{
+			name: "oldest kept event deleted",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				delete(f.events.chained, 4)
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 5, Kind: auditProblemGap}},
+		},
+		{
+			name: "oldest kept event rehashed",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				f.events.tamper(t, 4, true, func(p *models.AuditEventProps) { p.PrevHash = "" })
+			},
+			want: []dtos.AuditChainProblemDTO{
+				{Sequence: 4, Kind: auditProblemBrokenLink},
+				{Sequence: 5, Kind: auditProblemBrokenLink},
+			},
+		},
+		{
+			name: "anchor removed",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				delete(f.checkpoints.checkpoints, 3)
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 4, Kind: auditProblemGap}},
+		},
+		{
+			name: "anchor forged",
+			damage: func(t *testing.T, f *auditLogFixture) {
+				anchor := f.checkpoints.checkpoints[3]
+				forged, _ := models.LoadAuditCheckpoint(models.AuditCheckpointProps{
+					Sequence:  3,
+					Hash:      f.events.chained[4].GetPrevHash() + "0",
+					KeyID:     anchor.GetKeyID(),
+					Signature: anchor.GetSignature(),
+					Anchor:    true,
+				})
+				f.checkpoints.checkpoints[3] = forged
+			},
+			want: []dtos.AuditChainProblemDTO{{Sequence: 3, Kind: auditProblemCheckpoint}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuditLogFixture(t)
+			f.record(t, old, old.Add(time.Minute), old.Add(2*time.Minute))
+			f.record(t, newTimes...)
+
+			if err := f.service.PurgeExpired(context.Background()); err != nil {
+				t.Fatalf("PurgeExpired() error = %v", err)
+			}
+			if oldest, _, _ := f.events.SequenceRange(context.Background(), nil, nil); oldest != 4 {
+				t.Fatalf("oldest event after purge = %d, want 4", oldest)
+			}
+			if anchor, ok := f.checkpoints.checkpoints[3]; !ok || !anchor.IsAnchor() {
+				t.Fatalf("checkpoints = %v, want an anchor at 3", f.checkpoints.checkpoints)
+			}
+
+			tt.damage(t, f)
+
+			wantAuditProblems(t, f.verify(t, dtos.VerifyAuditChainDTO{}), tt.want...)
+		})
+	}
+}
+
+func TestPurgeExpiredKeepsNewestEvent(t *testing.T) {
+	f := newAuditLogFixture(t)
+	old := time.Now().Add(-48 * time.Hour)
+	f.record(t, old, old.Add(time.Minute), old.Add(2*time.Minute))
+
+	if err := f.service.PurgeExpired(context.Background()); err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if sequences := f.events.sequences(); len(sequences) != 1 || sequences[0] != 3 {
+		t.Fatalf("events left = %v, want the newest, 3", sequences)
+	}
+
+	// The chain goes on from the kept event.
+	f.record(t, minutesAgo(1)...)
+	wantAuditProblems(t, f.verify(t, dtos.VerifyAuditChainDTO{}))
+}
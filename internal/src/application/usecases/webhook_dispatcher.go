@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	webhookBatchSize     = 50
+	webhookConcurrency   = 8
+	webhookLease         = time.Minute
+	webhookBaseDelay     = 10 * time.Second
+	webhookMaxRetryDelay = time.Hour
+)
+
+// WebhookDispatcher fans domain events out to the matching webhook
+// subscriptions and delivers them. Each subscription gets its own delivery,
+// retried with exponential back-off and dead-lettered after the
+// subscription's MaxAttempts, so a failing receiver never holds up the
+// others.
+type WebhookDispatcher struct {
+	subscriptionRepo repositories.IWebhookSubscriptionRepository
+	deliveryRepo     repositories.IWebhookDeliveryRepository
+	sender           services.IWebhookSender
+	logger           *slog.Logger
+}
+
+func NewWebhookDispatcher(
+	subscriptionRepo repositories.IWebhookSubscriptionRepository,
+	deliveryRepo repositories.IWebhookDeliveryRepository,
+	sender services.IWebhookSender,
+	logger *slog.Logger,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		sender:           sender,
+		logger:           logger,
+	}
+}
+
+// Enqueue creates a delivery of event for every subscription that wants it.
+// Running it again for the same event adds nothing.
+func (d *WebhookDispatcher) Enqueue(ctx context.Context, event models.DomainEvent) error {
+	subscriptions, err := d.subscriptionRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.GetType()) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = models.EncodeDomainEvent(event); err != nil {
+				return err
+			}
+		}
+
+		delivery, er := models.NewWebhookDelivery(models.WebhookDeliveryProps{
+			SubscriptionID: subscription.GetID(),
+			EventID:        event.GetID(),
+			EventType:      event.GetType(),
+			Payload:        payload,
+		})
+		if er != nil {
+			return er
+		}
+
+		if err := d.deliveryRepo.Enqueue(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dispatch makes one attempt at every delivery that is due.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context) error {
+	deliveries, err := d.deliveryRepo.Claim(ctx, webhookBatchSize, time.Now().Add(webhookLease))
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, webhookConcurrency)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(delivery models.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := d.attempt(ctx, delivery); err != nil {
+				d.logger.ErrorContext(ctx, "Failed to process webhook delivery", "delivery_id", delivery.GetID(), "error", err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	subscription, err := d.subscriptionRepo.GetByID(ctx, delivery.GetSubscriptionID())
+	if err != nil {
+		var notFound *exceptions.RepositoryNoDataFoundException
+		if errors.As(err, &notFound) {
+			// The subscription was deleted along with its deliveries.
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	result := d.sender.Send(ctx, subscription.GetURL(), subscription.GetSecret(), delivery.GetID(), delivery.GetPayload(), now)
+
+	if result.Err != nil {
+		delivery.RecordFailure(now, result.StatusCode, result.Err.Error(), subscription.GetMaxAttempts(), webhookRetryDelay(delivery.GetAttempts()))
+		if delivery.GetStatus() == models.WebhookDeliveryDead {
+			d.logger.WarnContext(ctx, "Webhook delivery dead-lettered", "delivery_id", delivery.GetID(), "subscription_id", subscription.GetID(), "attempts", delivery.GetAttempts())
+		}
+	} else {
+		delivery.RecordSuccess(now, result.StatusCode)
+	}
+
+	// The outcome is stored even if the worker is stopping.
+	return d.deliveryRepo.Save(context.WithoutCancel(ctx), delivery)
+}
+
+// webhookRetryDelay doubles from ten seconds per failed attempt, capped at
+// an hour.
+func webhookRetryDelay(attempts int) time.Duration {
+	if attempts >= 10 {
+		return webhookMaxRetryDelay
+	}
+
+	delay := webhookBaseDelay << attempts
+	if delay > webhookMaxRetryDelay {
+		return webhookMaxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+type fakeSubscriptionRepo struct {
+	subscriptions []models.WebhookSubscription
+}
+
+func (r *fakeSubscriptionRepo) Save(ctx context.Context, subscription models.WebhookSubscription) error {
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) GetByID(ctx context.Context, id string) (models.WebhookSubscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.GetID() == id {
+			return subscription, nil
+		}
+	}
+	return nil, exceptions.NewRepositoryNoDataFoundException("webhook subscription not found")
+}
+
+func (r *fakeSubscriptionRepo) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return r.subscriptions, nil
+}
+
+func (r *fakeSubscriptionRepo) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+// fakeDeliveryRepo keeps deliveries in memory. Claim treats the clock as
+// skewed by ahead, so tests can make a scheduled retry due without waiting.
+type fakeDeliveryRepo struct {
+	mu         sync.Mutex
+	deliveries []models.WebhookDelivery
+	ahead      time.Duration
+}
+
+func (r *fakeDeliveryRepo) Enqueue(ctx context.Context, delivery models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) Save(ctx context.Context, delivery models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.deliveries {
+		if stored.GetID() == delivery.GetID() {
+			r.deliveries[i] = delivery
+		}
+	}
+	return nil
+}
+
+func (r *fakeDeliveryRepo) GetByID(ctx context.Context, id string) (models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range r.deliveries {
+		if delivery.GetID() == id {
+			return delivery, nil
+		}
+	}
+	return nil, exceptions.NewRepositoryNoDataFoundException("webhook delivery not found")
+}
+
+func (r *fakeDeliveryRepo) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().Add(r.ahead)
+	var due []models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.GetStatus() == models.WebhookDeliveryPending && !delivery.GetNextAttemptAt().After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeDeliveryRepo) Query(ctx context.Context, query repositories.WebhookDeliveryQuery) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *fakeDeliveryRepo) only(t *testing.T) models.WebhookDelivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(r.deliveries))
+	}
+	return r.deliveries[0]
+}
+
+// webhookReceiver answers with the given status codes in turn, and with the
+// last one after that, checking the signature of every request.
+type webhookReceiver struct {
+	t         *testing.T
+	mu        sync.Mutex
+	statuses  []int
+	calls     int
+	delivered []string
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(r.Header.Get("X-Authgate-Timestamp") + "." + string(body)))
+	want := "v1=" + hex.EncodeToString(mac.Sum(nil))
+	if got := r.Header.Get("X-Authgate-Signature"); !hmac.Equal([]byte(got), []byte(want)) {
+		rc.t.Errorf("X-Authgate-Signature = %q, want %q", got, want)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.delivered = append(rc.delivered, r.Header.Get("X-Authgate-Delivery"))
+	status := rc.statuses[min(rc.calls, len(rc.statuses)-1)]
+	rc.calls++
+	w.WriteHeader(status)
+}
+
+func (rc *webhookReceiver) callCount() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.calls
+}
+
+func newTestDispatcher(t *testing.T, receiverURL string, maxAttempts int) (*WebhookDispatcher, *fakeDeliveryRepo) {
+	t.Helper()
+
+	subscription, err := models.NewWebhookSubscription(models.WebhookSubscriptionProps{
+		URL:         receiverURL,
+		Secret:      testWebhookSecret,
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookSubscription() error = %v", err)
+	}
+
+	deliveries := &fakeDeliveryRepo{}
+	dispatcher := NewWebhookDispatcher(
+		&fakeSubscriptionRepo{subscriptions: []models.WebhookSubscription{subscription}},
+		deliveries,
+		adapters.NewWebhookSender(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	event, err := models.NewDomainEvent(models.DomainEventProps{
+		Type:   models.EventUserRegistered,
+		UserID: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("NewDomainEvent() error = %v", err)
+	}
+	if err := dispatcher.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	return dispatcher, deliveries
+}
+
+func TestWebhookDispatcherRetriesWithBackoff(t *testing.T) {
+	receiver := &webhookReceiver{t: t, statuses: []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	dispatcher, deliveries := newTestDispatcher(t, server.URL, 5)
+	ctx := context.Background()
+
+	for attempt, wantStatus := range []int{http.StatusServiceUnavailable, http.StatusInternalServerError} {
+		before := time.Now()
+		if err := dispatcher.Dispatch(ctx); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+
+		delivery := deliveries.only(t)
+		if delivery.GetStatus() != models.WebhookDeliveryPending {
+			t.Fatalf("attempt %d: status = %s, want %s", attempt+1, delivery.GetStatus(), models.WebhookDeliveryPending)
+		}
+		if delivery.GetAttempts() != attempt+1 {
+			t.Errorf("attempt %d: attempts = %d", attempt+1, delivery.GetAttempts())
+		}
+		if delivery.GetLastStatusCode() != wantStatus {
+			t.Errorf("attempt %d: last status code = %d, want %d", attempt+1, delivery.GetLastStatusCode(), wantStatus)
+		}
+		if delivery.GetLastError() == "" {
+			t.Errorf("attempt %d: last error is empty", attempt+1)
+		}
+
+		// Ten seconds after the first failure, twenty after the second.
+		delay := webhookBaseDelay << attempt
+		if next := delivery.GetNextAttemptAt(); next.Before(before.Add(delay)) || next.After(time.Now().Add(delay)) {
+			t.Errorf("attempt %d: next attempt in %v, want %v", attempt+1, next.Sub(before), delay)
+		}
+
+		// Nothing is sent again before the delay is over.
+		calls := receiver.callCount()
+		if err := dispatcher.Dispatch(ctx); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		if receiver.callCount() != calls {
+			t.Fatalf("attempt %d: delivery was retried before its back-off", attempt+1)
+		}
+
+		deliveries.ahead += delay
+	}
+
+	if err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	delivery := deliveries.only(t)
+	if delivery.GetStatus() != models.WebhookDeliverySucceeded {
+		t.Fatalf("status = %s, want %s", delivery.GetStatus(), models.WebhookDeliverySucceeded)
+	}
+	if delivery.GetAttempts() != 3 || delivery.GetLastStatusCode() != http.StatusOK || delivery.GetLastError() != "" {
+		t.Errorf("attempts = %d, last status code = %d, last error = %q", delivery.GetAttempts(), delivery.GetLastStatusCode(), delivery.GetLastError())
+	}
+	if delivery.GetDeliveredAt() == nil {
+		t.Error("delivered at is not recorded")
+	}
+
+	// Every attempt carries the same delivery id, so receivers can drop
+	// duplicates.
+	for _, id := range receiver.delivered {
+		if id != delivery.GetID() {
+			t.Errorf("X-Authgate-Delivery = %q, want %q", id, delivery.GetID())
+		}
+	}
+}
+
+func TestWebhookDispatcherDeadLetters(t *testing.T) {
+	receiver := &webhookReceiver{t: t, statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	dispatcher, deliveries := newTestDispatcher(t, server.URL, 2)
+	ctx := context.Background()
+
+	for range 3 {
+		if err := dispatcher.Dispatch(ctx); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		deliveries.ahead += webhookMaxRetryDelay
+	}
+
+	delivery := deliveries.only(t)
+	if delivery.GetStatus() != models.WebhookDeliveryDead {
+		t.Fatalf("status = %s, want %s", delivery.GetStatus(), models.WebhookDeliveryDead)
+	}
+	if delivery.GetAttempts() != 2 || receiver.callCount() != 2 {
+		t.Errorf("attempts = %d, receiver calls = %d, want 2", delivery.GetAttempts(), receiver.callCount())
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 10 * time.Second},
+		{attempts: 1, want: 20 * time.Second},
+		{attempts: 5, want: 320 * time.Second},
+		{attempts: 9, want: time.Hour},
+		{attempts: 40, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
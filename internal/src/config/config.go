@@ -0,0 +1,119 @@
+package config
+
+import (
+	"time"
+)
+
+// Config is the whole service configuration. Each setting is read, in
+// increasing order of precedence, from its default, the YAML file, its
+// environment variable and its command line flag. The YAML keys nest as the
+// sections do and the flag of a setting is its dotted YAML path, e.g.
+// --grpc.port. Durations are whole seconds everywhere.
+type Config struct {
+	Log             LogConfig             `yaml:"log"`
+	Database        DbConfig              `yaml:"database"`
+	GRPC            GRPCConfig            `yaml:"grpc"`
+	HTTP            HTTPConfig            `yaml:"http"`
+	Auth            AuthConfig            `yaml:"auth"`
+	JWT             JWTConfig             `yaml:"jwt"`
+	TokenEncryption TokenEncryptionConfig `yaml:"token_encryption"`
+	TokenExchange   TokenExchangeConfig   `yaml:"token_exchange"`
+	KMS             KMSConfig             `yaml:"kms"`
+	PII             PIIConfig             `yaml:"pii"`
+	Audit           AuditConfig           `yaml:"audit"`
+	Events          EventsConfig          `yaml:"events"`
+	Tracing         TracingConfig         `yaml:"tracing"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" default:"info" usage:"log level: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"json" usage:"log format: json or text"`
+}
+
+type GRPCConfig struct {
+	Port            int           `yaml:"port" env:"GRPC_PORT" default:"50051" usage:"gRPC listen port"`
+	Reflection      bool          `yaml:"reflection" env:"GRPC_REFLECTION" default:"false" usage:"serve gRPC server reflection"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout_seconds" env:"GRPC_SHUTDOWN_TIMEOUT_SECONDS" default:"10" usage:"seconds in-flight RPCs get to finish on stop"`
+	TLS             TLSConfig     `yaml:"tls"`
+	AdminIdentities []string      `yaml:"admin_identities" env:"GRPC_ADMIN_IDENTITIES" usage:"client identities allowed to call the admin RPCs, comma separated"`
+	// EventWatcherIdentities may call WatchAuthEvents without being admins.
+	EventWatcherIdentities []string `yaml:"event_watcher_identities" env:"GRPC_EVENT_WATCHER_IDENTITIES" usage:"client identities allowed to call WatchAuthEvents besides the admin identities, comma separated"`
+}
+
+type TLSConfig struct {
+	CertFile         string   `yaml:"cert_file" env:"GRPC_TLS_CERT_FILE" usage:"PEM server certificate chain"`
+	KeyFile          string   `yaml:"key_file" env:"GRPC_TLS_KEY_FILE" usage:"PEM server private key"`
+	ClientCAFile     string   `yaml:"client_ca_file" env:"GRPC_TLS_CLIENT_CA_FILE" usage:"PEM CA bundle client certificates are verified against; enables mutual TLS"`
+	ClientAuth       string   `yaml:"client_auth" env:"GRPC_TLS_CLIENT_AUTH" default:"require" usage:"client certificate policy: require or optional"`
+	ClientIdentities []string `yaml:"client_identities" env:"GRPC_TLS_CLIENT_IDENTITIES" sep:";" usage:"identity=subject pairs mapping client certificates to identities, semicolon separated"`
+}
+
+// MutualTLS reports whether client certificates are verified.
+func (c TLSConfig) MutualTLS() bool {
+	return c.CertFile != "" && c.ClientCAFile != ""
+}
+
+type HTTPConfig struct {
+	Port            int           `yaml:"port" env:"HTTP_PORT" default:"8080" usage:"HTTP listen port"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout_seconds" env:"HTTP_SHUTDOWN_TIMEOUT_SECONDS" default:"10" usage:"seconds in-flight HTTP requests get to finish on stop"`
+}
+
+// AuthConfig holds the defaults applied to users and tokens.
+type AuthConfig struct {
+	RefreshTokenTTL         time.Duration `yaml:"refresh_token_ttl_seconds" env:"REFRESH_TOKEN_TTL_SECONDS" default:"604800" usage:"refresh token lifetime in seconds"`
+	AuthorizationCodeTTL    time.Duration `yaml:"authorization_code_ttl_seconds" env:"AUTHORIZATION_CODE_TTL_SECONDS" default:"60" usage:"authorization code lifetime in seconds"`
+	DefaultMaxWrongAttempts int           `yaml:"default_max_wrong_attempts" env:"MAX_WRONG_ATTEMPTS" default:"5" usage:"failed logins before lockout for users registered without a limit; 0 disables lockout"`
+	DefaultMaxTokenAge      time.Duration `yaml:"default_max_token_age_seconds" env:"MAX_TOKEN_AGE_SECONDS" default:"604800" usage:"access token lifetime in seconds for users registered without one"`
+	BcryptCost              int           `yaml:"bcrypt_cost" env:"BCRYPT_COST" default:"10" usage:"bcrypt cost of password and client secret hashes"`
+}
+
+type JWTConfig struct {
+	Issuer              string        `yaml:"issuer" env:"JWT_ISSUER" default:"http://localhost:8080" usage:"iss of issued tokens"`
+	SigningAlgorithm    string        `yaml:"signing_algorithm" env:"JWT_SIGNING_ALGORITHM" default:"HS256" usage:"algorithm of a new access signing key: HS256, RS256, ES256 or EdDSA"`
+	SecretKey           string        `yaml:"secret_key" env:"JWT_SECRET_KEY" usage:"HMAC secret seeding the access signing key"`
+	SigningPrivateKey   string        `yaml:"signing_private_key" env:"JWT_SIGNING_PRIVATE_KEY" usage:"PEM private key seeding an asymmetric access signing key"`
+	RefreshSecretKey    string        `yaml:"refresh_secret_key" env:"JWT_REFRESH_SECRET_KEY" usage:"HMAC secret seeding the refresh signing key"`
+	MetadataClaims      string        `yaml:"metadata_claims" env:"JWT_METADATA_CLAIMS" usage:"user metadata keys copied into access tokens, e.g. plan,org_id,locale=lang"`
+	KeyOverlap          time.Duration `yaml:"key_overlap_seconds" env:"JWT_KEY_OVERLAP_SECONDS" default:"604800" usage:"seconds a rotated signing key keeps verifying"`
+	RetiredKeyRetention time.Duration `yaml:"retired_key_retention_seconds" env:"JWT_RETIRED_KEY_RETENTION_SECONDS" default:"2592000" usage:"seconds a retired signing key is kept before deletion"`
+	ClockSkew           time.Duration `yaml:"clock_skew_seconds" env:"JWT_CLOCK_SKEW_SECONDS" default:"30" usage:"tolerance in seconds applied to exp, nbf and iat"`
+}
+
+type TokenEncryptionConfig struct {
+	Format              string        `yaml:"format" env:"TOKEN_ENCRYPTION_FORMAT" default:"jwe" usage:"format of encrypted tokens: jwe or legacy"`
+	PrivateKey          string        `yaml:"private_key" env:"TOKEN_ENCRYPTION_PRIVATE_KEY" usage:"RSA or EC PEM private key seeding the encryption key"`
+	LegacyPrivateKey    string        `yaml:"legacy_private_key" env:"RSA_PRIVATE_KEY" usage:"RSA PEM private key of the legacy envelope"`
+	KeyOverlap          time.Duration `yaml:"key_overlap_seconds" env:"TOKEN_ENCRYPTION_KEY_OVERLAP_SECONDS" default:"604800" usage:"seconds a rotated encryption key keeps decrypting"`
+	RetiredKeyRetention time.Duration `yaml:"retired_key_retention_seconds" env:"TOKEN_ENCRYPTION_RETIRED_KEY_RETENTION_SECONDS" default:"2592000" usage:"seconds a retired encryption key is kept before deletion"`
+}
+
+type TokenExchangeConfig struct {
+	PolicyFile string `yaml:"policy_file" env:"TOKEN_EXCHANGE_POLICY_FILE" usage:"JSON token exchange rules; without it no exchange is allowed"`
+}
+
+type KMSConfig struct {
+	MasterKey string `yaml:"master_key" env:"KMS_MASTER_KEY" usage:"32 byte base64 master key wrapping stored key material"`
+}
+
+type PIIConfig struct {
+	DataKey  string `yaml:"data_key" env:"PII_DATA_KEY" usage:"32 byte base64 key encrypting personal data, or kms: and a wrapped key"`
+	IndexKey string `yaml:"index_key" env:"PII_INDEX_KEY" usage:"blind index key in the same format; derived from the data key when unset"`
+}
+
+type AuditConfig struct {
+	Retention          time.Duration `yaml:"retention_seconds" env:"AUDIT_RETENTION_SECONDS" default:"31536000" usage:"seconds audit events are kept"`
+	CheckpointInterval time.Duration `yaml:"checkpoint_interval_seconds" env:"AUDIT_CHECKPOINT_INTERVAL_SECONDS" default:"3600" usage:"seconds between signed audit checkpoints"`
+	SigningAlgorithm   string        `yaml:"signing_algorithm" env:"AUDIT_SIGNING_ALGORITHM" default:"EdDSA" usage:"algorithm of a new audit key: EdDSA, ES256 or RS256"`
+	SigningPrivateKey  string        `yaml:"signing_private_key" env:"AUDIT_SIGNING_PRIVATE_KEY" usage:"PEM private key seeding the audit key"`
+}
+
+type EventsConfig struct {
+	Publisher    string `yaml:"publisher" env:"EVENT_PUBLISHER" default:"memory" usage:"domain event publisher: memory, file or webhook"`
+	File         string `yaml:"file" env:"EVENT_PUBLISHER_FILE" usage:"file the file publisher appends events to"`
+	WebhookURL   string `yaml:"webhook_url" env:"EVENT_PUBLISHER_WEBHOOK_URL" usage:"URL the webhook publisher posts events to"`
+	WebhookToken string `yaml:"webhook_token" env:"EVENT_PUBLISHER_WEBHOOK_TOKEN" usage:"bearer token of the webhook publisher"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none" usage:"span exporter: otlp, stdout or none"`
+}
@@ -3,23 +3,14 @@ package config
 import "fmt"
 
 type DbConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DbName   string
-}
-
-func NewDbConfig(host, user, password, dbname string, port int) *DbConfig {
-	return &DbConfig{
-		Host:     host,
-		Port:     port,
-		User:     user,
-		Password: password,
-		DbName:   dbname,
-	}
+	Host     string `yaml:"host" env:"DB_HOST" default:"localhost" usage:"database host"`
+	Port     int    `yaml:"port" env:"DB_PORT" default:"5432" usage:"database port"`
+	User     string `yaml:"user" env:"DB_USER" usage:"database user"`
+	Password string `yaml:"password" env:"DB_PASSWORD" usage:"database password"`
+	DbName   string `yaml:"name" env:"DB_NAME" usage:"database name"`
+	SSLMode  string `yaml:"ssl_mode" env:"DB_SSL_MODE" default:"disable" usage:"PostgreSQL sslmode"`
 }
 
 func (db *DbConfig) ToString() string {
-	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", db.Host, db.Port, db.User, db.Password, db.DbName)
-}
\ No newline at end of file
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", db.Host, db.Port, db.User, db.Password, db.DbName, db.SSLMode)
+}
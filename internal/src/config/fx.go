@@ -0,0 +1,41 @@
+package config
+
+import "go.uber.org/fx"
+
+// Sections provides every section of the configuration on its own, so each
+// constructor only depends on the settings it reads.
+type Sections struct {
+	fx.Out
+
+	Log             LogConfig
+	Database        DbConfig
+	GRPC            GRPCConfig
+	HTTP            HTTPConfig
+	Auth            AuthConfig
+	JWT             JWTConfig
+	TokenEncryption TokenEncryptionConfig
+	TokenExchange   TokenExchangeConfig
+	KMS             KMSConfig
+	PII             PIIConfig
+	Audit           AuditConfig
+	Events          EventsConfig
+	Tracing         TracingConfig
+}
+
+func NewSections(cfg *Config) Sections {
+	return Sections{
+		Log:             cfg.Log,
+		Database:        cfg.Database,
+		GRPC:            cfg.GRPC,
+		HTTP:            cfg.HTTP,
+		Auth:            cfg.Auth,
+		JWT:             cfg.JWT,
+		TokenEncryption: cfg.TokenEncryption,
+		TokenExchange:   cfg.TokenExchange,
+		KMS:             cfg.KMS,
+		PII:             cfg.PII,
+		Audit:           cfg.Audit,
+		Events:          cfg.Events,
+		Tracing:         cfg.Tracing,
+	}
+}
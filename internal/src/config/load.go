@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileEnv names the YAML file when --config is not given.
+const configFileEnv = "AUTHGATE_CONFIG"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Error lists every problem found in the configuration, so they can all be
+// fixed in one go.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load reads the configuration from a .env file in the working directory,
+// when there is one, the YAML file named by --config or AUTHGATE_CONFIG, the
+// environment and args, the command line without the program name. It
+// returns flag.ErrHelp when args ask for the usage, which has been printed
+// by then, and an *Error listing every invalid setting.
+func Load(args []string) (*Config, error) {
+	var problems []string
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		problems = append(problems, fmt.Sprintf(".env: %v", err))
+	}
+
+	cfg := &Config{}
+	settings := settingsOf(reflect.ValueOf(cfg).Elem(), "")
+
+	flags := flag.NewFlagSet("authgate", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv(configFileEnv), "YAML configuration file ("+configFileEnv+")")
+	flagValues := map[string]string{}
+	for _, s := range settings {
+		flags.Var(&flagValue{setting: s, values: flagValues}, s.path, s.usage+" ("+s.env+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	for _, s := range settings {
+		if s.def != "" {
+			problems = append(problems, s.set("default "+s.path, s.def)...)
+		}
+	}
+
+	if *configFile != "" {
+		problems = append(problems, loadFile(*configFile, settings)...)
+	}
+
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			problems = append(problems, s.set(s.env, value)...)
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := flagValues[s.path]; ok {
+			problems = append(problems, s.set("--"+s.path, value)...)
+		}
+	}
+
+	problems = append(problems, cfg.validate(settings)...)
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+// setting is one configurable field: its dotted YAML path, which is also
+// its flag name, and the environment variable and default from its tags.
+type setting struct {
+	path  string
+	env   string
+	def   string
+	usage string
+	sep   string
+	value reflect.Value
+}
+
+func settingsOf(v reflect.Value, prefix string) []*setting {
+	var settings []*setting
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		path := prefix + field.Tag.Get("yaml")
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			settings = append(settings, settingsOf(v.Field(i), path+".")...)
+			continue
+		}
+
+		sep := field.Tag.Get("sep")
+		if sep == "" {
+			sep = ","
+		}
+		settings = append(settings, &setting{
+			path:  path,
+			env:   field.Tag.Get("env"),
+			def:   field.Tag.Get("default"),
+			usage: field.Tag.Get("usage"),
+			sep:   sep,
+			value: v.Field(i),
+		})
+	}
+	return settings
+}
+
+// set parses raw into the field; source names where raw came from in
+// problems.
+func (s *setting) set(source, raw string) []string {
+	switch {
+	case s.value.Type() == durationType:
+		seconds, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return []string{fmt.Sprintf("%s must be a whole number of seconds, got %q", source, raw)}
+		}
+		s.value.SetInt(int64(time.Duration(seconds) * time.Second))
+	case s.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return []string{fmt.Sprintf("%s must be a whole number, got %q", source, raw)}
+		}
+		s.value.SetInt(int64(n))
+	case s.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return []string{fmt.Sprintf("%s must be true or false, got %q", source, raw)}
+		}
+		s.value.SetBool(b)
+	case s.value.Kind() == reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, s.sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		s.value.SetString(raw)
+	}
+	return nil
+}
+
+// loadFile applies the YAML file at path. Keys that match no setting are
+// reported rather than ignored, since they are most likely typos.
+func loadFile(path string, settings []*setting) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read config file: %v", err)}
+	}
+
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+
+	values := map[string]interface{}{}
+	flatten(document, "", values)
+
+	var problems []string
+	for _, s := range settings {
+		value, ok := values[s.path]
+		if !ok {
+			continue
+		}
+		delete(values, s.path)
+
+		source := path + " " + s.path
+		if items, ok := value.([]interface{}); ok && s.value.Kind() == reflect.Slice {
+			strs := make([]string, len(items))
+			for i, item := range items {
+				strs[i] = fmt.Sprint(item)
+			}
+			s.value.Set(reflect.ValueOf(strs))
+			continue
+		}
+		if _, ok := value.([]interface{}); ok {
+			problems = append(problems, fmt.Sprintf("%s must be a single value", source))
+			continue
+		}
+		problems = append(problems, s.set(source, fmt.Sprint(value))...)
+	}
+
+	unknown := make([]string, 0, len(values))
+	for key := range values {
+		unknown = append(unknown, key)
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		problems = append(problems, fmt.Sprintf("%s %s is not a known setting", path, key))
+	}
+
+	return problems
+}
+
+// flatten keys the leaves of a YAML document by their dotted path. Null
+// leaves are left out, as if they were not set.
+func flatten(document map[string]interface{}, prefix string, values map[string]interface{}) {
+	for key, value := range document {
+		if value == nil {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(nested, prefix+key+".", values)
+			continue
+		}
+		values[prefix+key] = value
+	}
+}
+
+// flagValue records the raw value of a setting's flag; flags are applied
+// last, after the file and environment.
+type flagValue struct {
+	setting *setting
+	values  map[string]string
+}
+
+func (f *flagValue) String() string {
+	if f.setting == nil {
+		return ""
+	}
+	return f.setting.def
+}
+
+func (f *flagValue) Set(value string) error {
+	f.values[f.setting.path] = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.setting != nil && f.setting.value.Kind() == reflect.Bool
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKey = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+// setTestEnv runs the test in an empty directory, so no .env is picked up,
+// with every setting's variable cleared and then the ones in env set. The
+// settings without a default are filled in unless env overrides them.
+func setTestEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	t.Setenv(configFileEnv, "")
+	for _, s := range settingsOf(reflect.ValueOf(&Config{}).Elem(), "") {
+		t.Setenv(s.env, "")
+	}
+
+	required := map[string]string{
+		"DB_USER":        "authgate",
+		"DB_NAME":        "authgate",
+		"KMS_MASTER_KEY": testKey,
+		"PII_DATA_KEY":   testKey,
+	}
+	for name, value := range required {
+		t.Setenv(name, value)
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "authgate.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+// loadProblems returns the problems Load reports, failing the test on any
+// other error.
+func loadProblems(t *testing.T, args ...string) []string {
+	t.Helper()
+
+	_, err := Load(args)
+	if err == nil {
+		return nil
+	}
+	var invalid *Error
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Load() error = %v, want an *Error", err)
+	}
+	return invalid.Problems
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setTestEnv(t, nil)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.GRPC.Port != 50051 || cfg.Database.Host != "localhost" || cfg.Log.Level != "info" {
+		t.Errorf("Load() = grpc port %d, database host %s, log level %s, want the defaults", cfg.GRPC.Port, cfg.Database.Host, cfg.Log.Level)
+	}
+	if cfg.Auth.AuthorizationCodeTTL != time.Minute || cfg.Audit.Retention != 365*24*time.Hour {
+		t.Errorf("durations = %s and %s, want the defaults read as seconds", cfg.Auth.AuthorizationCodeTTL, cfg.Audit.Retention)
+	}
+	if cfg.Database.User != "authgate" || cfg.KMS.MasterKey != testKey {
+		t.Errorf("Load() did not read the environment: %+v", cfg.Database)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfigFile(t, `
+grpc:
+  port: 6000
+  shutdown_timeout_seconds: 20
+http:
+  port: 6001
+log:
+  level: debug
+`)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantGRPC int
+		wantHTTP int
+		wantLog  string
+	}{
+		{name: "file over defaults", args: []string{"--config", file}, wantGRPC: 6000, wantHTTP: 6001, wantLog: "debug"},
+		{name: "file named by the environment", env: map[string]string{configFileEnv: file}, wantGRPC: 6000, wantHTTP: 6001, wantLog: "debug"},
+		{
+			name:     "environment over file",
+			env:      map[string]string{"GRPC_PORT": "7000"},
+			args:     []string{"--config", file},
+			wantGRPC: 7000, wantHTTP: 6001, wantLog: "debug",
+		},
+		{
+			name:     "flags over environment",
+			env:      map[string]string{"GRPC_PORT": "7000", "LOG_LEVEL": "warn"},
+			args:     []string{"--config", file, "--grpc.port", "8000"},
+			wantGRPC: 8000, wantHTTP: 6001, wantLog: "warn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestEnv(t, tt.env)
+
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.GRPC.Port != tt.wantGRPC || cfg.HTTP.Port != tt.wantHTTP || cfg.Log.Level != tt.wantLog {
+				t.Errorf("Load() = grpc %d, http %d, log %s, want %d, %d, %s", cfg.GRPC.Port, cfg.HTTP.Port, cfg.Log.Level, tt.wantGRPC, tt.wantHTTP, tt.wantLog)
+			}
+			if cfg.GRPC.ShutdownTimeout != 20*time.Second {
+				t.Errorf("shutdown timeout = %s, want the file's 20s", cfg.GRPC.ShutdownTimeout)
+			}
+		})
+	}
+}
+
+func TestLoadLists(t *testing.T) {
+	file := writeConfigFile(t, `
+grpc:
+  admin_identities: [ops, deploy]
+`)
+	setTestEnv(t, map[string]string{
+		"GRPC_EVENT_WATCHER_IDENTITIES": "audit-sink, siem ,",
+		"GRPC_TLS_CLIENT_IDENTITIES":    "ops=CN=ops,O=Example;siem=CN=siem",
+		"GRPC_TLS_CERT_FILE":            "server.pem",
+		"GRPC_TLS_KEY_FILE":             "server-key.pem",
+		"GRPC_TLS_CLIENT_CA_FILE":       "ca.pem",
+	})
+
+	cfg, err := Load([]string{"--config", file})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := GRPCConfig{
+		AdminIdentities:        []string{"ops", "deploy"},
+		EventWatcherIdentities: []string{"audit-sink", "siem"},
+	}
+	if !reflect.DeepEqual(cfg.GRPC.AdminIdentities, want.AdminIdentities) || !reflect.DeepEqual(cfg.GRPC.EventWatcherIdentities, want.EventWatcherIdentities) {
+		t.Errorf("identities = %q and %q, want %q and %q", cfg.GRPC.AdminIdentities, cfg.GRPC.EventWatcherIdentities, want.AdminIdentities, want.EventWatcherIdentities)
+	}
+	// Subjects hold commas, so client identities are split on semicolons.
+	if wantPairs := []string{"ops=CN=ops,O=Example", "siem=CN=siem"}; !reflect.DeepEqual(cfg.GRPC.TLS.ClientIdentities, wantPairs) {
+		t.Errorf("client identities = %q, want %q", cfg.GRPC.TLS.ClientIdentities, wantPairs)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	file := writeConfigFile(t, `
+grpc:
+  prot: 6000
+http:
+  port: [1, 2]
+`)
+	setTestEnv(t, map[string]string{
+		"DB_PORT":                        "five",
+		"GRPC_REFLECTION":                "maybe",
+		"AUTHORIZATION_CODE_TTL_SECONDS": "1m",
+	})
+
+	problems := loadProblems(t, "--config", file)
+
+	want := []string{
+		file + " grpc.prot is not a known setting",
+		file + " http.port must be a single value",
+		`DB_PORT must be a whole number, got "five"`,
+		`GRPC_REFLECTION must be true or false, got "maybe"`,
+		`AUTHORIZATION_CODE_TTL_SECONDS must be a whole number of seconds, got "1m"`,
+	}
+	for _, w := range want {
+		if !containsProblem(problems, w) {
+			t.Errorf("problems = %q, want %q", problems, w)
+		}
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	setTestEnv(t, nil)
+
+	stderr := os.Stderr
+	devNull, _ := os.Open(os.DevNull)
+	os.Stderr = devNull
+	defer func() { os.Stderr = stderr; devNull.Close() }()
+
+	if _, err := Load([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) error = %v, want flag.ErrHelp", err)
+	}
+}
+
+func containsProblem(problems []string, want string) bool {
+	for _, problem := range problems {
+		if strings.Contains(problem, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const wrappedKeyPrefix = "kms:"
+
+// validator collects the problems of a loaded configuration, naming each
+// setting by its YAML path and environment variable.
+type validator struct {
+	envs     map[string]string
+	problems []string
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf("%s (%s) %s", path, v.envs[path], fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.fail(path, "must be %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+func (v *validator) port(path string, port int) {
+	if port < 1 || port > 65535 {
+		v.fail(path, "must be between 1 and 65535, got %d", port)
+	}
+}
+
+func (v *validator) positive(path string, d time.Duration) {
+	if d <= 0 {
+		v.fail(path, "must be a positive number of seconds")
+	}
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.fail(path, "must be set")
+	}
+}
+
+// key checks a 32 byte base64 key; wrapped allows a key wrapped by the key
+// management service, whose length is only known once unwrapped.
+func (v *validator) key(path, value string, wrapped bool) {
+	if value == "" {
+		v.fail(path, "must be set")
+		return
+	}
+	if wrapped && strings.HasPrefix(value, wrappedKeyPrefix) {
+		if _, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, wrappedKeyPrefix)); err != nil {
+			v.fail(path, "must be base64 encoded after %q", wrappedKeyPrefix)
+		}
+		return
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		v.fail(path, "must be 32 bytes encoded as base64")
+	}
+}
+
+func (c *Config) validate(settings []*setting) []string {
+	v := &validator{envs: map[string]string{}}
+	for _, s := range settings {
+		v.envs[s.path] = s.env
+	}
+
+	v.oneOf("log.level", strings.ToLower(c.Log.Level), "debug", "info", "warn", "error")
+	v.oneOf("log.format", strings.ToLower(c.Log.Format), "json", "text")
+
+	v.required("database.host", c.Database.Host)
+	v.port("database.port", c.Database.Port)
+	v.required("database.user", c.Database.User)
+	v.required("database.name", c.Database.DbName)
+	v.oneOf("database.ssl_mode", c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+
+	v.port("grpc.port", c.GRPC.Port)
+	v.positive("grpc.shutdown_timeout_seconds", c.GRPC.ShutdownTimeout)
+	c.GRPC.validate(v)
+
+	v.port("http.port", c.HTTP.Port)
+	v.positive("http.shutdown_timeout_seconds", c.HTTP.ShutdownTimeout)
+
+	v.positive("auth.refresh_token_ttl_seconds", c.Auth.RefreshTokenTTL)
+	v.positive("auth.authorization_code_ttl_seconds", c.Auth.AuthorizationCodeTTL)
+	if c.Auth.DefaultMaxWrongAttempts < 0 {
+		v.fail("auth.default_max_wrong_attempts", "must not be negative")
+	}
+	v.positive("auth.default_max_token_age_seconds", c.Auth.DefaultMaxTokenAge)
+	if c.Auth.BcryptCost < bcrypt.MinCost || c.Auth.BcryptCost > bcrypt.MaxCost {
+		v.fail("auth.bcrypt_cost", "must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c.Auth.BcryptCost)
+	}
+
+	v.required("jwt.issuer", c.JWT.Issuer)
+	v.oneOf("jwt.signing_algorithm", c.JWT.SigningAlgorithm, "HS256", "RS256", "ES256", "EdDSA")
+	v.positive("jwt.key_overlap_seconds", c.JWT.KeyOverlap)
+	v.positive("jwt.retired_key_retention_seconds", c.JWT.RetiredKeyRetention)
+	if c.JWT.ClockSkew < 0 {
+		v.fail("jwt.clock_skew_seconds", "must not be negative")
+	}
+
+	v.oneOf("token_encryption.format", c.TokenEncryption.Format, "jwe", "legacy")
+	v.positive("token_encryption.key_overlap_seconds", c.TokenEncryption.KeyOverlap)
+	v.positive("token_encryption.retired_key_retention_seconds", c.TokenEncryption.RetiredKeyRetention)
+
+	v.key("kms.master_key", c.KMS.MasterKey, false)
+	v.key("pii.data_key", c.PII.DataKey, true)
+	if c.PII.IndexKey != "" {
+		v.key("pii.index_key", c.PII.IndexKey, true)
+	}
+
+	v.positive("audit.retention_seconds", c.Audit.Retention)
+	v.positive("audit.checkpoint_interval_seconds", c.Audit.CheckpointInterval)
+	v.oneOf("audit.signing_algorithm", c.Audit.SigningAlgorithm, "EdDSA", "ES256", "RS256")
+
+	switch c.Events.Publisher {
+	case "memory":
+	case "file":
+		v.required("events.file", c.Events.File)
+	case "webhook":
+		v.required("events.webhook_url", c.Events.WebhookURL)
+	default:
+		v.oneOf("events.publisher", c.Events.Publisher, "memory", "file", "webhook")
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "otlp", "stdout", "none")
+
+	return v.problems
+}
+
+func (c GRPCConfig) validate(v *validator) {
+	tls := c.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		v.fail("grpc.tls.key_file", "must be set together with grpc.tls.cert_file (%s)", v.envs["grpc.tls.cert_file"])
+	}
+	if tls.ClientCAFile != "" && tls.CertFile == "" {
+		v.fail("grpc.tls.client_ca_file", "requires grpc.tls.cert_file (%s)", v.envs["grpc.tls.cert_file"])
+	}
+	v.oneOf("grpc.tls.client_auth", tls.ClientAuth, "require", "optional")
+
+	for _, pair := range tls.ClientIdentities {
+		identity, subject, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(identity) == "" || strings.TrimSpace(subject) == "" {
+			v.fail("grpc.tls.client_identities", "entries must look like identity=subject, got %q", pair)
+		}
+	}
+	if (len(tls.ClientIdentities) > 0 || len(c.AdminIdentities) > 0 || len(c.EventWatcherIdentities) > 0) && !tls.MutualTLS() {
+		v.fail("grpc.admin_identities", "grpc.event_watcher_identities and grpc.tls.client_identities require grpc.tls.client_ca_file (%s)", v.envs["grpc.tls.client_ca_file"])
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	mutualTLS := map[string]string{
+		"GRPC_TLS_CERT_FILE":      "server.pem",
+		"GRPC_TLS_KEY_FILE":       "server-key.pem",
+		"GRPC_TLS_CLIENT_CA_FILE": "ca.pem",
+	}
+	with := func(base map[string]string, name, value string) map[string]string {
+		env := map[string]string{name: value}
+		for k, v := range base {
+			env[k] = v
+		}
+		return env
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{name: "defaults"},
+		{name: "mutual TLS with identities", env: with(with(mutualTLS, "GRPC_ADMIN_IDENTITIES", "ops"), "GRPC_EVENT_WATCHER_IDENTITIES", "siem")},
+		{name: "wrapped data key", env: map[string]string{"PII_DATA_KEY": "kms:" + testKey}},
+		{
+			name: "missing database settings",
+			env:  map[string]string{"DB_USER": "", "DB_NAME": "", "DB_PORT": "0"},
+			want: []string{"database.user (DB_USER) must be set", "database.name (DB_NAME) must be set", "database.port (DB_PORT) must be between 1 and 65535, got 0"},
+		},
+		{
+			name: "unknown choices",
+			env:  map[string]string{"LOG_LEVEL": "trace", "JWT_SIGNING_ALGORITHM": "none", "TOKEN_ENCRYPTION_FORMAT": "plain", "OTEL_TRACES_EXPORTER": "zipkin"},
+			want: []string{
+				`log.level (LOG_LEVEL) must be debug, info, warn, error, got "trace"`,
+				`jwt.signing_algorithm (JWT_SIGNING_ALGORITHM) must be HS256, RS256, ES256, EdDSA, got "none"`,
+				`token_encryption.format (TOKEN_ENCRYPTION_FORMAT) must be jwe, legacy, got "plain"`,
+				`tracing.exporter (OTEL_TRACES_EXPORTER) must be otlp, stdout, none, got "zipkin"`,
+			},
+		},
+		{
+			name: "log level in capitals",
+			env:  map[string]string{"LOG_LEVEL": "DEBUG"},
+		},
+		{
+			name: "durations that must be positive",
+			env:  map[string]string{"AUTHORIZATION_CODE_TTL_SECONDS": "0", "AUDIT_RETENTION_SECONDS": "-1"},
+			want: []string{
+				"auth.authorization_code_ttl_seconds (AUTHORIZATION_CODE_TTL_SECONDS) must be a positive number of seconds",
+				"audit.retention_seconds (AUDIT_RETENTION_SECONDS) must be a positive number of seconds",
+			},
+		},
+		{
+			name: "negative clock skew and attempts",
+			env:  map[string]string{"JWT_CLOCK_SKEW_SECONDS": "-1", "MAX_WRONG_ATTEMPTS": "-1"},
+			want: []string{"jwt.clock_skew_seconds (JWT_CLOCK_SKEW_SECONDS) must not be negative", "auth.default_max_wrong_attempts (MAX_WRONG_ATTEMPTS) must not be negative"},
+		},
+		{
+			name: "bcrypt cost out of range",
+			env:  map[string]string{"BCRYPT_COST": "32"},
+			want: []string{"auth.bcrypt_cost (BCRYPT_COST) must be between 4 and 31, got 32"},
+		},
+		{
+			name: "keys",
+			env:  map[string]string{"KMS_MASTER_KEY": "", "PII_DATA_KEY": "c2hvcnQ=", "PII_INDEX_KEY": "kms:!"},
+			want: []string{
+				"kms.master_key (KMS_MASTER_KEY) must be set",
+				"pii.data_key (PII_DATA_KEY) must be 32 bytes encoded as base64",
+				`pii.index_key (PII_INDEX_KEY) must be base64 encoded after "kms:"`,
+			},
+		},
+		{
+			name: "master key cannot be wrapped",
+			env:  map[string]string{"KMS_MASTER_KEY": "kms:" + testKey},
+			want: []string{"kms.master_key (KMS_MASTER_KEY) must be 32 bytes encoded as base64"},
+		},
+		{
+			name: "publisher settings",
+			env:  map[string]string{"EVENT_PUBLISHER": "webhook"},
+			want: []string{"events.webhook_url (EVENT_PUBLISHER_WEBHOOK_URL) must be set"},
+		},
+		{
+			name: "unknown publisher",
+			env:  map[string]string{"EVENT_PUBLISHER": "kafka"},
+			want: []string{`events.publisher (EVENT_PUBLISHER) must be memory, file, webhook, got "kafka"`},
+		},
+		{
+			name: "certificate without its key",
+			env:  map[string]string{"GRPC_TLS_CERT_FILE": "server.pem"},
+			want: []string{"grpc.tls.key_file (GRPC_TLS_KEY_FILE) must be set together with grpc.tls.cert_file (GRPC_TLS_CERT_FILE)"},
+		},
+		{
+			name: "client CA without a certificate",
+			env:  map[string]string{"GRPC_TLS_CLIENT_CA_FILE": "ca.pem"},
+			want: []string{"grpc.tls.client_ca_file (GRPC_TLS_CLIENT_CA_FILE) requires grpc.tls.cert_file (GRPC_TLS_CERT_FILE)"},
+		},
+		{
+			name: "malformed client identity",
+			env:  with(mutualTLS, "GRPC_TLS_CLIENT_IDENTITIES", "ops=CN=ops;=CN=nobody;siem"),
+			want: []string{
+				`grpc.tls.client_identities (GRPC_TLS_CLIENT_IDENTITIES) entries must look like identity=subject, got "=CN=nobody"`,
+				`grpc.tls.client_identities (GRPC_TLS_CLIENT_IDENTITIES) entries must look like identity=subject, got "siem"`,
+			},
+		},
+		{
+			name: "admin identities without mutual TLS",
+			env:  map[string]string{"GRPC_ADMIN_IDENTITIES": "ops"},
+			want: []string{"grpc.admin_identities (GRPC_ADMIN_IDENTITIES) grpc.event_watcher_identities and grpc.tls.client_identities require grpc.tls.client_ca_file (GRPC_TLS_CLIENT_CA_FILE)"},
+		},
+		{
+			name: "event watchers without mutual TLS",
+			env:  map[string]string{"GRPC_TLS_CERT_FILE": "server.pem", "GRPC_TLS_KEY_FILE": "server-key.pem", "GRPC_EVENT_WATCHER_IDENTITIES": "siem"},
+			want: []string{"require grpc.tls.client_ca_file (GRPC_TLS_CLIENT_CA_FILE)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestEnv(t, tt.env)
+
+			problems := loadProblems(t)
+			if len(problems) != len(tt.want) {
+				t.Errorf("problems = %q, want %q", problems, tt.want)
+			}
+			for _, want := range tt.want {
+				if !containsProblem(problems, want) {
+					t.Errorf("problems = %q, want %q", problems, want)
+				}
+			}
+		})
+	}
+}
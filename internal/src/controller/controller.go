@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/usecases"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
 )
 
@@ -13,6 +15,31 @@ type Controller struct {
 	refreshUsecase usecase.UseCaseWithProps[dtos.RefreshTokenDTO, *dtos.RefreshTokenResponseDTO]
 	verifyUsecase usecase.UseCaseWithProps[dtos.VerifyTokenDTO, *dtos.UserInfoDTO]
 	deleteAuthUsecase usecase.UseCaseWithProps[string, *struct{}]
+	registerClientUsecase usecase.UseCaseWithProps[dtos.RegisterClientDTO, *dtos.RegisterClientResponseDTO]
+	validateAuthorizeUsecase usecase.UseCaseWithProps[dtos.AuthorizeRequestDTO, *dtos.AuthorizeResponseDTO]
+	authorizeUsecase usecase.UseCaseWithProps[dtos.AuthorizeDTO, *dtos.AuthorizeResponseDTO]
+	tokenUsecase usecase.UseCaseWithProps[dtos.TokenDTO, *dtos.TokenResponseDTO]
+	oidcUserInfoUsecase usecase.UseCaseWithProps[dtos.OIDCUserInfoRequestDTO, *dtos.OIDCUserInfoDTO]
+	openIDConfigurationUsecase usecase.UseCase[*dtos.OpenIDConfigurationDTO]
+	getJWKSUsecase usecase.UseCase[*dtos.JWKSDTO]
+	rotateSigningKeyUsecase usecase.UseCaseWithProps[dtos.RotateSigningKeyDTO, *dtos.SigningKeyDTO]
+	listSigningKeysUsecase usecase.UseCaseWithProps[dtos.ListSigningKeysDTO, *dtos.ListSigningKeysResponseDTO]
+	introspectTokenUsecase usecase.UseCaseWithProps[dtos.IntrospectDTO, *dtos.IntrospectionResponseDTO]
+	revokeTokenUsecase usecase.UseCaseWithProps[dtos.RevokeTokenDTO, *struct{}]
+	updateUserInfoUsecase usecase.UseCaseWithProps[dtos.UpdateUserInfoDTO, *dtos.UserInfoDTO]
+	rotateEncryptionKeyUsecase usecase.UseCaseWithProps[dtos.RotateEncryptionKeyDTO, *dtos.EncryptionKeyDTO]
+	listEncryptionKeysUsecase usecase.UseCaseWithProps[dtos.ListEncryptionKeysDTO, *dtos.ListEncryptionKeysResponseDTO]
+	setAudienceKeyUsecase usecase.UseCaseWithProps[dtos.SetAudienceKeyDTO, *dtos.AudienceKeyDTO]
+	queryAuditEventsUsecase usecase.UseCaseWithProps[dtos.QueryAuditEventsDTO, *dtos.QueryAuditEventsResponseDTO]
+	verifyAuditChainUsecase usecase.UseCaseWithProps[dtos.VerifyAuditChainDTO, *dtos.AuditChainReportDTO]
+	changePasswordUsecase usecase.UseCaseWithProps[dtos.ChangePasswordDTO, *struct{}]
+	createWebhookSubscriptionUsecase usecase.UseCaseWithProps[dtos.CreateWebhookSubscriptionDTO, *dtos.WebhookSubscriptionDTO]
+	listWebhookSubscriptionsUsecase usecase.UseCaseWithProps[dtos.ListWebhookSubscriptionsDTO, *dtos.ListWebhookSubscriptionsResponseDTO]
+	deleteWebhookSubscriptionUsecase usecase.UseCaseWithProps[dtos.DeleteWebhookSubscriptionDTO, *struct{}]
+	listWebhookDeliveriesUsecase usecase.UseCaseWithProps[dtos.ListWebhookDeliveriesDTO, *dtos.ListWebhookDeliveriesResponseDTO]
+	retryWebhookDeliveryUsecase usecase.UseCaseWithProps[dtos.RetryWebhookDeliveryDTO, *dtos.WebhookDeliveryDTO]
+	authEventWatcher *usecases.AuthEventWatcher
+	logger *slog.Logger
 }
 
 func NewController(
@@ -21,6 +48,31 @@ func NewController(
 	refreshUsecase usecase.UseCaseWithProps[dtos.RefreshTokenDTO, *dtos.RefreshTokenResponseDTO],
 	verifyUsecase usecase.UseCaseWithProps[dtos.VerifyTokenDTO, *dtos.UserInfoDTO],
 	deleteAuthUsecase usecase.UseCaseWithProps[string, *struct{}],
+	registerClientUsecase usecase.UseCaseWithProps[dtos.RegisterClientDTO, *dtos.RegisterClientResponseDTO],
+	validateAuthorizeUsecase usecase.UseCaseWithProps[dtos.AuthorizeRequestDTO, *dtos.AuthorizeResponseDTO],
+	authorizeUsecase usecase.UseCaseWithProps[dtos.AuthorizeDTO, *dtos.AuthorizeResponseDTO],
+	tokenUsecase usecase.UseCaseWithProps[dtos.TokenDTO, *dtos.TokenResponseDTO],
+	oidcUserInfoUsecase usecase.UseCaseWithProps[dtos.OIDCUserInfoRequestDTO, *dtos.OIDCUserInfoDTO],
+	openIDConfigurationUsecase usecase.UseCase[*dtos.OpenIDConfigurationDTO],
+	getJWKSUsecase usecase.UseCase[*dtos.JWKSDTO],
+	rotateSigningKeyUsecase usecase.UseCaseWithProps[dtos.RotateSigningKeyDTO, *dtos.SigningKeyDTO],
+	listSigningKeysUsecase usecase.UseCaseWithProps[dtos.ListSigningKeysDTO, *dtos.ListSigningKeysResponseDTO],
+	introspectTokenUsecase usecase.UseCaseWithProps[dtos.IntrospectDTO, *dtos.IntrospectionResponseDTO],
+	revokeTokenUsecase usecase.UseCaseWithProps[dtos.RevokeTokenDTO, *struct{}],
+	updateUserInfoUsecase usecase.UseCaseWithProps[dtos.UpdateUserInfoDTO, *dtos.UserInfoDTO],
+	rotateEncryptionKeyUsecase usecase.UseCaseWithProps[dtos.RotateEncryptionKeyDTO, *dtos.EncryptionKeyDTO],
+	listEncryptionKeysUsecase usecase.UseCaseWithProps[dtos.ListEncryptionKeysDTO, *dtos.ListEncryptionKeysResponseDTO],
+	setAudienceKeyUsecase usecase.UseCaseWithProps[dtos.SetAudienceKeyDTO, *dtos.AudienceKeyDTO],
+	queryAuditEventsUsecase usecase.UseCaseWithProps[dtos.QueryAuditEventsDTO, *dtos.QueryAuditEventsResponseDTO],
+	verifyAuditChainUsecase usecase.UseCaseWithProps[dtos.VerifyAuditChainDTO, *dtos.AuditChainReportDTO],
+	changePasswordUsecase usecase.UseCaseWithProps[dtos.ChangePasswordDTO, *struct{}],
+	createWebhookSubscriptionUsecase usecase.UseCaseWithProps[dtos.CreateWebhookSubscriptionDTO, *dtos.WebhookSubscriptionDTO],
+	listWebhookSubscriptionsUsecase usecase.UseCaseWithProps[dtos.ListWebhookSubscriptionsDTO, *dtos.ListWebhookSubscriptionsResponseDTO],
+	deleteWebhookSubscriptionUsecase usecase.UseCaseWithProps[dtos.DeleteWebhookSubscriptionDTO, *struct{}],
+	listWebhookDeliveriesUsecase usecase.UseCaseWithProps[dtos.ListWebhookDeliveriesDTO, *dtos.ListWebhookDeliveriesResponseDTO],
+	retryWebhookDeliveryUsecase usecase.UseCaseWithProps[dtos.RetryWebhookDeliveryDTO, *dtos.WebhookDeliveryDTO],
+	authEventWatcher *usecases.AuthEventWatcher,
+	logger *slog.Logger,
 ) *Controller {
 	controller := &Controller{
 		loginUsecase: loginUsecase,
@@ -28,13 +80,38 @@ func NewController(
 		refreshUsecase: refreshUsecase,
 		verifyUsecase: verifyUsecase,
 		deleteAuthUsecase: deleteAuthUsecase,
+		registerClientUsecase: registerClientUsecase,
+		validateAuthorizeUsecase: validateAuthorizeUsecase,
+		authorizeUsecase: authorizeUsecase,
+		tokenUsecase: tokenUsecase,
+		oidcUserInfoUsecase: oidcUserInfoUsecase,
+		openIDConfigurationUsecase: openIDConfigurationUsecase,
+		getJWKSUsecase: getJWKSUsecase,
+		rotateSigningKeyUsecase: rotateSigningKeyUsecase,
+		listSigningKeysUsecase: listSigningKeysUsecase,
+		introspectTokenUsecase: introspectTokenUsecase,
+		revokeTokenUsecase: revokeTokenUsecase,
+		updateUserInfoUsecase: updateUserInfoUsecase,
+		rotateEncryptionKeyUsecase: rotateEncryptionKeyUsecase,
+		listEncryptionKeysUsecase: listEncryptionKeysUsecase,
+		setAudienceKeyUsecase: setAudienceKeyUsecase,
+		queryAuditEventsUsecase: queryAuditEventsUsecase,
+		verifyAuditChainUsecase: verifyAuditChainUsecase,
+		changePasswordUsecase: changePasswordUsecase,
+		createWebhookSubscriptionUsecase: createWebhookSubscriptionUsecase,
+		listWebhookSubscriptionsUsecase: listWebhookSubscriptionsUsecase,
+		deleteWebhookSubscriptionUsecase: deleteWebhookSubscriptionUsecase,
+		listWebhookDeliveriesUsecase: listWebhookDeliveriesUsecase,
+		retryWebhookDeliveryUsecase: retryWebhookDeliveryUsecase,
+		authEventWatcher: authEventWatcher,
+		logger: logger,
 	}
 
 	return controller
 }
 
 func (c *Controller) Login(ctx context.Context, dto dtos.LoginDTO) (*dtos.LoginResponseDTO, error) {
-	response, err := usecase.ExecuteUseCaseWithProps(ctx, c.loginUsecase, dto)
+	response, err := executeWithProps(ctx, c.logger, "Login", c.loginUsecase, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +120,7 @@ func (c *Controller) Login(ctx context.Context, dto dtos.LoginDTO) (*dtos.LoginR
 }
 
 func (c *Controller) Register(ctx context.Context, dto dtos.RegisterDTO) (*dtos.RegisterResponseDTO, error) {
-	response, err := usecase.ExecuteUseCaseWithProps(ctx, c.registerUsecase, dto)
+	response, err := executeWithProps(ctx, c.logger, "Register", c.registerUsecase, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +129,7 @@ func (c *Controller) Register(ctx context.Context, dto dtos.RegisterDTO) (*dtos.
 }
 
 func (c *Controller) RefreshToken(ctx context.Context, dto dtos.RefreshTokenDTO) (*dtos.RefreshTokenResponseDTO, error) {
-	response, err := usecase.ExecuteUseCaseWithProps(ctx, c.refreshUsecase, dto)
+	response, err := executeWithProps(ctx, c.logger, "RefreshToken", c.refreshUsecase, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +138,7 @@ func (c *Controller) RefreshToken(ctx context.Context, dto dtos.RefreshTokenDTO)
 }
 
 func (c *Controller) VerifyToken(ctx context.Context, dto dtos.VerifyTokenDTO) (*dtos.UserInfoDTO, error) {
-	response, err := usecase.ExecuteUseCaseWithProps(ctx, c.verifyUsecase, dto)
+	response, err := executeWithProps(ctx, c.logger, "VerifyToken", c.verifyUsecase, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +147,223 @@ func (c *Controller) VerifyToken(ctx context.Context, dto dtos.VerifyTokenDTO) (
 }
 
 func (c *Controller) DeleteAuth(ctx context.Context, userID string) error {
-	_, err := usecase.ExecuteUseCaseWithProps(ctx, c.deleteAuthUsecase, userID)
+	_, err := executeWithProps(ctx, c.logger, "DeleteAuth", c.deleteAuthUsecase, userID)
 	if err != nil {
 		return err
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (c *Controller) RegisterClient(ctx context.Context, dto dtos.RegisterClientDTO) (*dtos.RegisterClientResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "RegisterClient", c.registerClientUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) ValidateAuthorize(ctx context.Context, dto dtos.AuthorizeRequestDTO) (*dtos.AuthorizeResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "ValidateAuthorize", c.validateAuthorizeUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) Authorize(ctx context.Context, dto dtos.AuthorizeDTO) (*dtos.AuthorizeResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "Authorize", c.authorizeUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) Token(ctx context.Context, dto dtos.TokenDTO) (*dtos.TokenResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "Token", c.tokenUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) OIDCUserInfo(ctx context.Context, dto dtos.OIDCUserInfoRequestDTO) (*dtos.OIDCUserInfoDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "OIDCUserInfo", c.oidcUserInfoUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) OpenIDConfiguration(ctx context.Context) (*dtos.OpenIDConfigurationDTO, error) {
+	response, err := execute(ctx, c.logger, "OpenIDConfiguration", c.openIDConfigurationUsecase)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) GetJWKS(ctx context.Context) (*dtos.JWKSDTO, error) {
+	response, err := execute(ctx, c.logger, "GetJWKS", c.getJWKSUsecase)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) RotateSigningKey(ctx context.Context, dto dtos.RotateSigningKeyDTO) (*dtos.SigningKeyDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "RotateSigningKey", c.rotateSigningKeyUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) ListSigningKeys(ctx context.Context, dto dtos.ListSigningKeysDTO) (*dtos.ListSigningKeysResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "ListSigningKeys", c.listSigningKeysUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) IntrospectToken(ctx context.Context, dto dtos.IntrospectDTO) (*dtos.IntrospectionResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "IntrospectToken", c.introspectTokenUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) RevokeToken(ctx context.Context, dto dtos.RevokeTokenDTO) error {
+	_, err := executeWithProps(ctx, c.logger, "RevokeToken", c.revokeTokenUsecase, dto)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Controller) UpdateUserInfo(ctx context.Context, dto dtos.UpdateUserInfoDTO) (*dtos.UserInfoDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "UpdateUserInfo", c.updateUserInfoUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) RotateEncryptionKey(ctx context.Context, dto dtos.RotateEncryptionKeyDTO) (*dtos.EncryptionKeyDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "RotateEncryptionKey", c.rotateEncryptionKeyUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) ListEncryptionKeys(ctx context.Context, dto dtos.ListEncryptionKeysDTO) (*dtos.ListEncryptionKeysResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "ListEncryptionKeys", c.listEncryptionKeysUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) SetAudienceKey(ctx context.Context, dto dtos.SetAudienceKeyDTO) (*dtos.AudienceKeyDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "SetAudienceKey", c.setAudienceKeyUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) QueryAuditEvents(ctx context.Context, dto dtos.QueryAuditEventsDTO) (*dtos.QueryAuditEventsResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "QueryAuditEvents", c.queryAuditEventsUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) VerifyAuditChain(ctx context.Context, dto dtos.VerifyAuditChainDTO) (*dtos.AuditChainReportDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "VerifyAuditChain", c.verifyAuditChainUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) ChangePassword(ctx context.Context, dto dtos.ChangePasswordDTO) error {
+	_, err := executeWithProps(ctx, c.logger, "ChangePassword", c.changePasswordUsecase, dto)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Controller) CreateWebhookSubscription(ctx context.Context, dto dtos.CreateWebhookSubscriptionDTO) (*dtos.WebhookSubscriptionDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "CreateWebhookSubscription", c.createWebhookSubscriptionUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) ListWebhookSubscriptions(ctx context.Context, dto dtos.ListWebhookSubscriptionsDTO) (*dtos.ListWebhookSubscriptionsResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "ListWebhookSubscriptions", c.listWebhookSubscriptionsUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) DeleteWebhookSubscription(ctx context.Context, dto dtos.DeleteWebhookSubscriptionDTO) error {
+	_, err := executeWithProps(ctx, c.logger, "DeleteWebhookSubscription", c.deleteWebhookSubscriptionUsecase, dto)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Controller) ListWebhookDeliveries(ctx context.Context, dto dtos.ListWebhookDeliveriesDTO) (*dtos.ListWebhookDeliveriesResponseDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "ListWebhookDeliveries", c.listWebhookDeliveriesUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *Controller) RetryWebhookDelivery(ctx context.Context, dto dtos.RetryWebhookDeliveryDTO) (*dtos.WebhookDeliveryDTO, error) {
+	response, err := executeWithProps(ctx, c.logger, "RetryWebhookDelivery", c.retryWebhookDeliveryUsecase, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// WatchAuthEvents streams auth events to send until ctx is done. It is not a
+// use case: it runs for as long as the consumer stays connected.
+func (c *Controller) WatchAuthEvents(ctx context.Context, dto dtos.WatchAuthEventsDTO, send func(dtos.AuthEventDTO) error) error {
+	return c.authEventWatcher.Watch(ctx, dto, send)
+}
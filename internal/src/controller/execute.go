@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/application/usecase"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/Gabriel-Schiestl/authgate/internal/src/controller")
+
+// executeWithProps runs useCase inside a usecase.<name> span, a child of the
+// RPC or HTTP request span carried by ctx, and logs how it went. Props and
+// results are not logged: they hold passwords and tokens.
+func executeWithProps[P, R any](ctx context.Context, logger *slog.Logger, name string, useCase usecase.UseCaseWithProps[P, R], props P) (R, error) {
+	ctx, span := tracer.Start(ctx, "usecase."+name)
+	start := time.Now()
+	response, err := usecase.ExecuteUseCaseWithProps(ctx, useCase, props)
+	logUseCase(ctx, logger, name, err, time.Since(start))
+	adapters.EndSpan(span, err)
+	return response, err
+}
+
+// execute is executeWithProps for use cases without props.
+func execute[R any](ctx context.Context, logger *slog.Logger, name string, useCase usecase.UseCase[R]) (R, error) {
+	ctx, span := tracer.Start(ctx, "usecase."+name)
+	start := time.Now()
+	response, err := usecase.ExecuteUseCase(ctx, useCase)
+	logUseCase(ctx, logger, name, err, time.Since(start))
+	adapters.EndSpan(span, err)
+	return response, err
+}
+
+func logUseCase(ctx context.Context, logger *slog.Logger, name string, err error, took time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("use_case", name),
+		slog.Duration("duration", took),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelDebug, "use case finished", attrs...)
+}
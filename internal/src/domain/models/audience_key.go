@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// AudienceKey is the public key a consuming service registered for its
+// audience. Access tokens issued for that audience are encrypted to it so the
+// service can decrypt and validate them on its own.
+type AudienceKey interface {
+	GetAudience() string
+	GetKeyID() string
+	GetAlgorithm() string
+	GetPublicJWK() string
+	GetCreatedAt() time.Time
+}
+
+type audienceKey struct {
+	audience  string
+	keyID     string
+	algorithm string
+	publicJWK string
+	createdAt time.Time
+}
+
+type AudienceKeyProps struct {
+	Audience  string
+	KeyID     string
+	Algorithm string
+	PublicJWK string
+	CreatedAt time.Time
+}
+
+func NewAudienceKey(props AudienceKeyProps) (AudienceKey, *exceptions.BusinessException) {
+	if props.Audience == "" {
+		return nil, exceptions.NewBusinessException("audience cannot be empty")
+	}
+	if props.KeyID == "" {
+		return nil, exceptions.NewBusinessException("audience key ID cannot be empty")
+	}
+	if props.Algorithm == "" {
+		return nil, exceptions.NewBusinessException("audience key algorithm cannot be empty")
+	}
+	if props.PublicJWK == "" {
+		return nil, exceptions.NewBusinessException("audience public key cannot be empty")
+	}
+
+	key := &audienceKey{
+		audience:  props.Audience,
+		keyID:     props.KeyID,
+		algorithm: props.Algorithm,
+		publicJWK: props.PublicJWK,
+		createdAt: props.CreatedAt,
+	}
+
+	if key.createdAt.IsZero() {
+		key.createdAt = time.Now()
+	}
+
+	return key, nil
+}
+
+func LoadAudienceKey(props AudienceKeyProps) (AudienceKey, *exceptions.BusinessException) {
+	return NewAudienceKey(props)
+}
+
+func (k *audienceKey) GetAudience() string {
+	return k.audience
+}
+
+func (k *audienceKey) GetKeyID() string {
+	return k.keyID
+}
+
+func (k *audienceKey) GetAlgorithm() string {
+	return k.algorithm
+}
+
+func (k *audienceKey) GetPublicJWK() string {
+	return k.publicJWK
+}
+
+func (k *audienceKey) GetCreatedAt() time.Time {
+	return k.createdAt
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// AuditCheckpoint is a signed statement of the audit chain's head at some
+// point: the sequence and hash of the last event. Rewriting the chain up to
+// a checkpoint would require the signing key. An anchor is signed by the
+// retention purge for the last event it drops, so the oldest remaining
+// event can still be checked against what came before it.
+type AuditCheckpoint interface {
+	GetSequence() int64
+	GetHash() string
+	GetKeyID() string
+	GetSignature() string
+	GetCreatedAt() time.Time
+	IsAnchor() bool
+}
+
+type auditCheckpoint struct {
+	sequence  int64
+	hash      string
+	keyID     string
+	signature string
+	createdAt time.Time
+	anchor    bool
+}
+
+type AuditCheckpointProps struct {
+	Sequence  int64
+	Hash      string
+	KeyID     string
+	Signature string
+	CreatedAt time.Time
+	Anchor    bool
+}
+
+func NewAuditCheckpoint(props AuditCheckpointProps) (AuditCheckpoint, *exceptions.BusinessException) {
+	if props.Sequence <= 0 {
+		return nil, exceptions.NewBusinessException("audit checkpoint sequence must be positive")
+	}
+	if props.Hash == "" {
+		return nil, exceptions.NewBusinessException("audit checkpoint hash cannot be empty")
+	}
+	if props.KeyID == "" || props.Signature == "" {
+		return nil, exceptions.NewBusinessException("audit checkpoint must be signed")
+	}
+
+	checkpoint := &auditCheckpoint{
+		sequence:  props.Sequence,
+		hash:      props.Hash,
+		keyID:     props.KeyID,
+		signature: props.Signature,
+		createdAt: props.CreatedAt,
+		anchor:    props.Anchor,
+	}
+
+	if checkpoint.createdAt.IsZero() {
+		checkpoint.createdAt = time.Now()
+	}
+
+	return checkpoint, nil
+}
+
+func LoadAuditCheckpoint(props AuditCheckpointProps) (AuditCheckpoint, *exceptions.BusinessException) {
+	return NewAuditCheckpoint(props)
+}
+
+func (c *auditCheckpoint) GetSequence() int64 {
+	return c.sequence
+}
+
+func (c *auditCheckpoint) GetHash() string {
+	return c.hash
+}
+
+func (c *auditCheckpoint) GetKeyID() string {
+	return c.keyID
+}
+
+func (c *auditCheckpoint) GetSignature() string {
+	return c.signature
+}
+
+func (c *auditCheckpoint) GetCreatedAt() time.Time {
+	return c.createdAt
+}
+
+func (c *auditCheckpoint) IsAnchor() bool {
+	return c.anchor
+}
@@ -0,0 +1,218 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+// AuditEvent is a recorded security relevant action: who did it, to which
+// user, through which client, from where and with what result.
+//
+// Stored events form a hash chain: each one carries its position in the log
+// and the hash of the event before it, and its own hash covers both, so an
+// event cannot be altered, removed or reordered without breaking the chain.
+type AuditEvent interface {
+	GetID() string
+	GetType() string
+	GetActorID() string
+	GetSubjectID() string
+	GetClientID() string
+	GetOutcome() string
+	GetReason() string
+	GetPeerIP() string
+	GetUserAgent() string
+	GetDetails() map[string]interface{}
+	GetOccurredAt() time.Time
+	GetSequence() int64
+	GetPrevHash() string
+	GetHash() string
+	ComputeHash() string
+	Chain(sequence int64, prevHash string)
+}
+
+type auditEvent struct {
+	id         string
+	eventType  string
+	actorID    string
+	subjectID  string
+	clientID   string
+	outcome    string
+	reason     string
+	peerIP     string
+	userAgent  string
+	details    map[string]interface{}
+	occurredAt time.Time
+	sequence   int64
+	prevHash   string
+	hash       string
+}
+
+type AuditEventProps struct {
+	ID         string
+	Type       string
+	ActorID    string
+	SubjectID  string
+	ClientID   string
+	Outcome    string
+	Reason     string
+	PeerIP     string
+	UserAgent  string
+	Details    map[string]interface{}
+	OccurredAt time.Time
+	Sequence   int64
+	PrevHash   string
+	Hash       string
+}
+
+func NewAuditEvent(props AuditEventProps) (AuditEvent, *exceptions.BusinessException) {
+	if props.Type == "" {
+		return nil, exceptions.NewBusinessException("audit event type cannot be empty")
+	}
+	if props.Outcome == "" {
+		return nil, exceptions.NewBusinessException("audit event outcome cannot be empty")
+	}
+
+	event := &auditEvent{
+		id:         props.ID,
+		eventType:  props.Type,
+		actorID:    props.ActorID,
+		subjectID:  props.SubjectID,
+		clientID:   props.ClientID,
+		outcome:    props.Outcome,
+		reason:     props.Reason,
+		peerIP:     props.PeerIP,
+		userAgent:  props.UserAgent,
+		details:    props.Details,
+		occurredAt: props.OccurredAt,
+		sequence:   props.Sequence,
+		prevHash:   props.PrevHash,
+		hash:       props.Hash,
+	}
+
+	if event.id == "" {
+		event.id = utils.GenerateUUID()
+	}
+	if event.occurredAt.IsZero() {
+		// The database keeps microseconds; the hash must survive the round
+		// trip.
+		event.occurredAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	if event.details == nil {
+		event.details = map[string]interface{}{}
+	}
+
+	return event, nil
+}
+
+func LoadAuditEvent(props AuditEventProps) (AuditEvent, *exceptions.BusinessException) {
+	return NewAuditEvent(props)
+}
+
+func (e *auditEvent) GetID() string {
+	return e.id
+}
+
+func (e *auditEvent) GetType() string {
+	return e.eventType
+}
+
+func (e *auditEvent) GetActorID() string {
+	return e.actorID
+}
+
+func (e *auditEvent) GetSubjectID() string {
+	return e.subjectID
+}
+
+func (e *auditEvent) GetClientID() string {
+	return e.clientID
+}
+
+func (e *auditEvent) GetOutcome() string {
+	return e.outcome
+}
+
+func (e *auditEvent) GetReason() string {
+	return e.reason
+}
+
+func (e *auditEvent) GetPeerIP() string {
+	return e.peerIP
+}
+
+func (e *auditEvent) GetUserAgent() string {
+	return e.userAgent
+}
+
+func (e *auditEvent) GetDetails() map[string]interface{} {
+	return e.details
+}
+
+func (e *auditEvent) GetOccurredAt() time.Time {
+	return e.occurredAt
+}
+
+func (e *auditEvent) GetSequence() int64 {
+	return e.sequence
+}
+
+func (e *auditEvent) GetPrevHash() string {
+	return e.prevHash
+}
+
+func (e *auditEvent) GetHash() string {
+	return e.hash
+}
+
+// Chain places the event right after the event whose hash is prevHash and
+// seals it. The first event of the log has sequence 1 and no prevHash.
+func (e *auditEvent) Chain(sequence int64, prevHash string) {
+	e.sequence = sequence
+	e.prevHash = prevHash
+	e.hash = e.ComputeHash()
+}
+
+// ComputeHash returns the hex SHA-256 of the event's content and chain
+// position. It is recomputed on verification and compared with the stored
+// hash.
+func (e *auditEvent) ComputeHash() string {
+	// Field order is fixed by the struct and encoding/json sorts the keys of
+	// details, which makes the encoding canonical.
+	canonical, _ := json.Marshal(struct {
+		Sequence   int64                  `json:"sequence"`
+		PrevHash   string                 `json:"prev_hash"`
+		ID         string                 `json:"id"`
+		Type       string                 `json:"type"`
+		ActorID    string                 `json:"actor_id"`
+		SubjectID  string                 `json:"subject_id"`
+		ClientID   string                 `json:"client_id"`
+		Outcome    string                 `json:"outcome"`
+		Reason     string                 `json:"reason"`
+		PeerIP     string                 `json:"peer_ip"`
+		UserAgent  string                 `json:"user_agent"`
+		Details    map[string]interface{} `json:"details"`
+		OccurredAt int64                  `json:"occurred_at"`
+	}{
+		Sequence:   e.sequence,
+		PrevHash:   e.prevHash,
+		ID:         e.id,
+		Type:       e.eventType,
+		ActorID:    e.actorID,
+		SubjectID:  e.subjectID,
+		ClientID:   e.clientID,
+		Outcome:    e.outcome,
+		Reason:     e.reason,
+		PeerIP:     e.peerIP,
+		UserAgent:  e.userAgent,
+		Details:    e.details,
+		OccurredAt: e.occurredAt.UnixMicro(),
+	})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
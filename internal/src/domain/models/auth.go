@@ -17,6 +17,7 @@ type Auth interface {
 	GetLastLoginAt() *time.Time
 	GetWrongAttempts() int
 	GetMaxWrongAttempts() *int
+	IsLocked() bool
 	GetRecoveryToken() *string
 	GetMaxTokenAgeSeconds() *int
 }
@@ -132,6 +133,12 @@ func (a *auth) GetMaxWrongAttempts() *int {
 	return a.maxWrongAttempts
 }
 
+// IsLocked reports whether the account reached its limit of consecutive
+// wrong passwords. A limit of zero or less disables the lockout.
+func (a *auth) IsLocked() bool {
+	return a.maxWrongAttempts != nil && *a.maxWrongAttempts > 0 && a.wrongAttempts >= *a.maxWrongAttempts
+}
+
 func (a *auth) GetRecoveryToken() *string {
 	return a.recoveryToken
 }
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const CodeChallengeMethodS256 = "S256"
+
+type AuthorizationCode interface {
+	GetCodeHash() string
+	GetClientID() string
+	GetUserID() string
+	GetRedirectURI() string
+	GetCodeChallenge() string
+	GetCodeChallengeMethod() string
+	GetScope() string
+	GetNonce() string
+	GetAuthTime() time.Time
+	GetExpiresAt() time.Time
+	IsExpired(now time.Time) bool
+}
+
+type authorizationCode struct {
+	codeHash            string
+	clientID            string
+	userID              string
+	redirectURI         string
+	codeChallenge       string
+	codeChallengeMethod string
+	scope               string
+	nonce               string
+	authTime            time.Time
+	expiresAt           time.Time
+}
+
+type AuthorizationCodeProps struct {
+	CodeHash            string
+	ClientID            string
+	UserID              string
+	RedirectURI         string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Scope               string
+	Nonce               string
+	AuthTime            time.Time
+	ExpiresAt           time.Time
+}
+
+func NewAuthorizationCode(props AuthorizationCodeProps) (AuthorizationCode, *exceptions.BusinessException) {
+	if props.CodeHash == "" {
+		return nil, exceptions.NewBusinessException("authorization code hash cannot be empty")
+	}
+	if props.ClientID == "" || props.UserID == "" {
+		return nil, exceptions.NewBusinessException("authorization code must belong to a client and a user")
+	}
+	if props.CodeChallengeMethod != CodeChallengeMethodS256 {
+		return nil, exceptions.NewBusinessException("only the S256 code challenge method is supported")
+	}
+	if props.CodeChallenge == "" {
+		return nil, exceptions.NewBusinessException("code challenge cannot be empty")
+	}
+
+	return &authorizationCode{
+		codeHash:            props.CodeHash,
+		clientID:            props.ClientID,
+		userID:              props.UserID,
+		redirectURI:         props.RedirectURI,
+		codeChallenge:       props.CodeChallenge,
+		codeChallengeMethod: props.CodeChallengeMethod,
+		scope:               props.Scope,
+		nonce:               props.Nonce,
+		authTime:            props.AuthTime,
+		expiresAt:           props.ExpiresAt,
+	}, nil
+}
+
+func LoadAuthorizationCode(props AuthorizationCodeProps) (AuthorizationCode, *exceptions.BusinessException) {
+	return NewAuthorizationCode(props)
+}
+
+func (c *authorizationCode) GetCodeHash() string {
+	return c.codeHash
+}
+
+func (c *authorizationCode) GetClientID() string {
+	return c.clientID
+}
+
+func (c *authorizationCode) GetUserID() string {
+	return c.userID
+}
+
+func (c *authorizationCode) GetRedirectURI() string {
+	return c.redirectURI
+}
+
+func (c *authorizationCode) GetCodeChallenge() string {
+	return c.codeChallenge
+}
+
+func (c *authorizationCode) GetCodeChallengeMethod() string {
+	return c.codeChallengeMethod
+}
+
+func (c *authorizationCode) GetScope() string {
+	return c.scope
+}
+
+func (c *authorizationCode) GetNonce() string {
+	return c.nonce
+}
+
+func (c *authorizationCode) GetAuthTime() time.Time {
+	return c.authTime
+}
+
+func (c *authorizationCode) GetExpiresAt() time.Time {
+	return c.expiresAt
+}
+
+func (c *authorizationCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.expiresAt)
+}
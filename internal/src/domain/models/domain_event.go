@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+const (
+	EventUserRegistered  = "user.registered"
+	EventUserDeleted     = "user.deleted"
+	EventPasswordChanged = "password.changed"
+)
+
+// DomainEventTypes lists every event type authgate emits.
+var DomainEventTypes = []string{EventUserRegistered, EventUserDeleted, EventPasswordChanged}
+
+// DomainEvent is a fact about a user that other services may react to. It is
+// written to the outbox together with the change it describes and delivered
+// at least once, in order per user; consumers deduplicate on the ID.
+type DomainEvent interface {
+	GetID() string
+	GetType() string
+	GetUserID() string
+	GetData() map[string]interface{}
+	GetOccurredAt() time.Time
+}
+
+type domainEvent struct {
+	id         string
+	eventType  string
+	userID     string
+	data       map[string]interface{}
+	occurredAt time.Time
+}
+
+type DomainEventProps struct {
+	ID         string
+	Type       string
+	UserID     string
+	Data       map[string]interface{}
+	OccurredAt time.Time
+}
+
+func NewDomainEvent(props DomainEventProps) (DomainEvent, *exceptions.BusinessException) {
+	if props.Type == "" {
+		return nil, exceptions.NewBusinessException("domain event type cannot be empty")
+	}
+	if props.UserID == "" {
+		return nil, exceptions.NewBusinessException("domain event user ID cannot be empty")
+	}
+
+	event := &domainEvent{
+		id:         props.ID,
+		eventType:  props.Type,
+		userID:     props.UserID,
+		data:       props.Data,
+		occurredAt: props.OccurredAt,
+	}
+
+	if event.id == "" {
+		event.id = utils.GenerateUUID()
+	}
+	if event.occurredAt.IsZero() {
+		event.occurredAt = time.Now().UTC()
+	}
+	if event.data == nil {
+		event.data = map[string]interface{}{}
+	}
+
+	return event, nil
+}
+
+func LoadDomainEvent(props DomainEventProps) (DomainEvent, *exceptions.BusinessException) {
+	return NewDomainEvent(props)
+}
+
+func (e *domainEvent) GetID() string {
+	return e.id
+}
+
+func (e *domainEvent) GetType() string {
+	return e.eventType
+}
+
+func (e *domainEvent) GetUserID() string {
+	return e.userID
+}
+
+func (e *domainEvent) GetData() map[string]interface{} {
+	return e.data
+}
+
+func (e *domainEvent) GetOccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// EncodeDomainEvent renders event in the JSON envelope every consumer
+// receives, whatever the transport.
+func EncodeDomainEvent(event DomainEvent) ([]byte, error) {
+	return json.Marshal(struct {
+		ID         string                 `json:"id"`
+		Type       string                 `json:"type"`
+		UserID     string                 `json:"user_id"`
+		OccurredAt time.Time              `json:"occurred_at"`
+		Data       map[string]interface{} `json:"data"`
+	}{
+		ID:         event.GetID(),
+		Type:       event.GetType(),
+		UserID:     event.GetUserID(),
+		OccurredAt: event.GetOccurredAt(),
+		Data:       event.GetData(),
+	})
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type EncryptionKeyState string
+
+const (
+	// EncryptionKeyStateActive keys encrypt new tokens once their activation
+	// time has passed. When several active keys qualify the most recently
+	// activated one wins.
+	EncryptionKeyStateActive EncryptionKeyState = "active"
+	// EncryptionKeyStateDecryptOnly keys no longer encrypt but still decrypt
+	// tokens until they expire.
+	EncryptionKeyStateDecryptOnly EncryptionKeyState = "decrypt-only"
+	// EncryptionKeyStateRetired keys decrypt nothing and are waiting to be
+	// removed.
+	EncryptionKeyStateRetired EncryptionKeyState = "retired"
+)
+
+type EncryptionKey interface {
+	GetKeyID() string
+	GetAlgorithm() string
+	GetWrappedMaterial() []byte
+	GetState() EncryptionKeyState
+	GetActivatesAt() time.Time
+	GetExpiresAt() *time.Time
+	GetRemoveAt() *time.Time
+	GetLastUsedAt() *time.Time
+	GetCreatedAt() time.Time
+	CanEncrypt(now time.Time) bool
+	CanDecrypt(now time.Time) bool
+	ExpireAt(at time.Time)
+	Demote()
+	Retire(now time.Time, retention time.Duration)
+	MarkUsed(at time.Time) bool
+}
+
+type encryptionKey struct {
+	keyID           string
+	algorithm       string
+	wrappedMaterial []byte
+	state           EncryptionKeyState
+	activatesAt     time.Time
+	expiresAt       *time.Time
+	removeAt        *time.Time
+	lastUsedAt      *time.Time
+	createdAt       time.Time
+}
+
+type EncryptionKeyProps struct {
+	KeyID           string
+	Algorithm       string
+	WrappedMaterial []byte
+	State           EncryptionKeyState
+	ActivatesAt     time.Time
+	ExpiresAt       *time.Time
+	RemoveAt        *time.Time
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+func NewEncryptionKey(props EncryptionKeyProps) (EncryptionKey, *exceptions.BusinessException) {
+	if props.KeyID == "" {
+		return nil, exceptions.NewBusinessException("encryption key ID cannot be empty")
+	}
+	if props.Algorithm == "" {
+		return nil, exceptions.NewBusinessException("encryption key algorithm cannot be empty")
+	}
+	if len(props.WrappedMaterial) == 0 {
+		return nil, exceptions.NewBusinessException("encryption key material cannot be empty")
+	}
+
+	key := &encryptionKey{
+		keyID:           props.KeyID,
+		algorithm:       props.Algorithm,
+		wrappedMaterial: props.WrappedMaterial,
+		state:           props.State,
+		activatesAt:     props.ActivatesAt,
+		expiresAt:       props.ExpiresAt,
+		removeAt:        props.RemoveAt,
+		lastUsedAt:      props.LastUsedAt,
+		createdAt:       props.CreatedAt,
+	}
+
+	if key.state == "" {
+		key.state = EncryptionKeyStateActive
+	}
+	if key.createdAt.IsZero() {
+		key.createdAt = time.Now()
+	}
+	if key.activatesAt.IsZero() {
+		key.activatesAt = key.createdAt
+	}
+
+	return key, nil
+}
+
+func LoadEncryptionKey(props EncryptionKeyProps) (EncryptionKey, *exceptions.BusinessException) {
+	return NewEncryptionKey(props)
+}
+
+func (k *encryptionKey) GetKeyID() string {
+	return k.keyID
+}
+
+func (k *encryptionKey) GetAlgorithm() string {
+	return k.algorithm
+}
+
+func (k *encryptionKey) GetWrappedMaterial() []byte {
+	return k.wrappedMaterial
+}
+
+func (k *encryptionKey) GetState() EncryptionKeyState {
+	return k.state
+}
+
+func (k *encryptionKey) GetActivatesAt() time.Time {
+	return k.activatesAt
+}
+
+func (k *encryptionKey) GetExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+func (k *encryptionKey) GetRemoveAt() *time.Time {
+	return k.removeAt
+}
+
+// GetLastUsedAt is the last time the key encrypted or decrypted a token, as
+// last recorded by the key maintenance job.
+func (k *encryptionKey) GetLastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
+
+func (k *encryptionKey) GetCreatedAt() time.Time {
+	return k.createdAt
+}
+
+func (k *encryptionKey) CanEncrypt(now time.Time) bool {
+	return k.state == EncryptionKeyStateActive && !now.Before(k.activatesAt) && k.CanDecrypt(now)
+}
+
+func (k *encryptionKey) CanDecrypt(now time.Time) bool {
+	if k.state == EncryptionKeyStateRetired {
+		return false
+	}
+	return k.expiresAt == nil || now.Before(*k.expiresAt)
+}
+
+// ExpireAt bounds the decryption window of the key. An earlier existing
+// bound is kept so overlapping rotations never extend a key's lifetime.
+func (k *encryptionKey) ExpireAt(at time.Time) {
+	if k.expiresAt != nil && k.expiresAt.Before(at) {
+		return
+	}
+	k.expiresAt = &at
+}
+
+func (k *encryptionKey) Demote() {
+	if k.state == EncryptionKeyStateActive {
+		k.state = EncryptionKeyStateDecryptOnly
+	}
+}
+
+func (k *encryptionKey) Retire(now time.Time, retention time.Duration) {
+	removeAt := now.Add(retention)
+	k.state = EncryptionKeyStateRetired
+	k.removeAt = &removeAt
+}
+
+// MarkUsed records a use of the key and reports whether it is newer than
+// the one already recorded.
+func (k *encryptionKey) MarkUsed(at time.Time) bool {
+	if k.lastUsedAt != nil && !at.After(*k.lastUsedAt) {
+		return false
+	}
+	k.lastUsedAt = &at
+	return true
+}
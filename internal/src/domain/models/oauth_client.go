@@ -0,0 +1,99 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+type OAuthClient interface {
+	GetClientID() string
+	GetName() string
+	GetSecretHash() *string
+	GetRedirectURIs() []string
+	IsPublic() bool
+	GetEncryptTokens() bool
+	HasRedirectURI(uri string) bool
+}
+
+type oauthClient struct {
+	clientID      string
+	name          string
+	secretHash    *string
+	redirectURIs  []string
+	public        bool
+	encryptTokens bool
+}
+
+type OAuthClientProps struct {
+	ClientID      string
+	Name          string
+	SecretHash    *string
+	RedirectURIs  []string
+	Public        bool
+	EncryptTokens bool
+}
+
+func NewOAuthClient(props OAuthClientProps) (OAuthClient, *exceptions.BusinessException) {
+	if props.Name == "" {
+		return nil, exceptions.NewBusinessException("client name cannot be empty")
+	}
+	if len(props.RedirectURIs) == 0 {
+		return nil, exceptions.NewBusinessException("at least one redirect URI is required")
+	}
+	if !props.Public && props.SecretHash == nil {
+		return nil, exceptions.NewBusinessException("confidential clients must have a secret")
+	}
+
+	client := &oauthClient{
+		clientID:      props.ClientID,
+		name:          props.Name,
+		secretHash:    props.SecretHash,
+		redirectURIs:  props.RedirectURIs,
+		public:        props.Public,
+		encryptTokens: props.EncryptTokens,
+	}
+
+	if client.clientID == "" {
+		client.clientID = utils.GenerateUUID()
+	}
+
+	return client, nil
+}
+
+func LoadOAuthClient(props OAuthClientProps) (OAuthClient, *exceptions.BusinessException) {
+	return NewOAuthClient(props)
+}
+
+func (c *oauthClient) GetClientID() string {
+	return c.clientID
+}
+
+func (c *oauthClient) GetName() string {
+	return c.name
+}
+
+func (c *oauthClient) GetSecretHash() *string {
+	return c.secretHash
+}
+
+func (c *oauthClient) GetRedirectURIs() []string {
+	return c.redirectURIs
+}
+
+func (c *oauthClient) IsPublic() bool {
+	return c.public
+}
+
+// GetEncryptTokens reports whether access tokens issued to the client must
+// be encrypted, whatever the user's own EncryptToken setting.
+func (c *oauthClient) GetEncryptTokens() bool {
+	return c.encryptTokens
+}
+
+// HasRedirectURI reports whether uri exactly matches one of the registered
+// redirect URIs. No prefix or wildcard matching is performed.
+func (c *oauthClient) HasRedirectURI(uri string) bool {
+	return slices.Contains(c.redirectURIs, uri)
+}
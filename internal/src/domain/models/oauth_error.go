@@ -0,0 +1,39 @@
+package models
+
+// OAuth 2.0 error codes as defined in RFC 6749 sections 4.1.2.1 and 5.2.
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorUnauthorizedClient      = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorAccessDenied            = "access_denied"
+	OAuthErrorServerError             = "server_error"
+	// Bearer token errors from RFC 6750 section 3.1.
+	OAuthErrorInvalidToken      = "invalid_token"
+	OAuthErrorInsufficientScope = "insufficient_scope"
+	// Revocation error from RFC 7009 section 2.2.1.
+	OAuthErrorUnsupportedTokenType = "unsupported_token_type"
+	// Token exchange error from RFC 8693 section 2.2.2.
+	OAuthErrorInvalidTarget = "invalid_target"
+)
+
+// OAuthError carries an RFC 6749 error code so the HTTP layer can render
+// the standard error response instead of a generic failure.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
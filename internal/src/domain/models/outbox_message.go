@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// OutboxMessage is a domain event waiting in the outbox, with the state of
+// its delivery.
+type OutboxMessage interface {
+	GetEvent() DomainEvent
+	GetAttempts() int
+	GetLastError() string
+	GetPublishedAt() *time.Time
+}
+
+type outboxMessage struct {
+	event       DomainEvent
+	attempts    int
+	lastError   string
+	publishedAt *time.Time
+}
+
+type OutboxMessageProps struct {
+	Event       DomainEvent
+	Attempts    int
+	LastError   string
+	PublishedAt *time.Time
+}
+
+func LoadOutboxMessage(props OutboxMessageProps) (OutboxMessage, *exceptions.BusinessException) {
+	if props.Event == nil {
+		return nil, exceptions.NewBusinessException("outbox message must carry an event")
+	}
+
+	return &outboxMessage{
+		event:       props.Event,
+		attempts:    props.Attempts,
+		lastError:   props.LastError,
+		publishedAt: props.PublishedAt,
+	}, nil
+}
+
+func (m *outboxMessage) GetEvent() DomainEvent {
+	return m.event
+}
+
+func (m *outboxMessage) GetAttempts() int {
+	return m.attempts
+}
+
+func (m *outboxMessage) GetLastError() string {
+	return m.lastError
+}
+
+func (m *outboxMessage) GetPublishedAt() *time.Time {
+	return m.publishedAt
+}
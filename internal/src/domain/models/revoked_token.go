@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// RevokedToken records a token that must no longer be accepted even though
+// its signature and expiry are still valid. The token id is either the jti
+// of a single token or the sid shared by a whole session. The record is only
+// needed until the tokens would have expired on their own.
+type RevokedToken interface {
+	GetTokenID() string
+	GetUserID() string
+	GetRevokedAt() time.Time
+	GetExpiresAt() time.Time
+}
+
+type revokedToken struct {
+	tokenID   string
+	userID    string
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+type RevokedTokenProps struct {
+	TokenID   string
+	UserID    string
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewRevokedToken(props RevokedTokenProps) (RevokedToken, *exceptions.BusinessException) {
+	if props.TokenID == "" {
+		return nil, exceptions.NewBusinessException("revoked token id cannot be empty")
+	}
+	if props.RevokedAt.IsZero() {
+		props.RevokedAt = time.Now()
+	}
+
+	return &revokedToken{
+		tokenID:   props.TokenID,
+		userID:    props.UserID,
+		revokedAt: props.RevokedAt,
+		expiresAt: props.ExpiresAt,
+	}, nil
+}
+
+func LoadRevokedToken(props RevokedTokenProps) (RevokedToken, *exceptions.BusinessException) {
+	return NewRevokedToken(props)
+}
+
+func (t *revokedToken) GetTokenID() string {
+	return t.tokenID
+}
+
+func (t *revokedToken) GetUserID() string {
+	return t.userID
+}
+
+func (t *revokedToken) GetRevokedAt() time.Time {
+	return t.revokedAt
+}
+
+func (t *revokedToken) GetExpiresAt() time.Time {
+	return t.expiresAt
+}
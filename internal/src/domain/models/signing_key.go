@@ -0,0 +1,190 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type SigningKeyPurpose string
+
+const (
+	SigningKeyPurposeAccess  SigningKeyPurpose = "access"
+	SigningKeyPurposeRefresh SigningKeyPurpose = "refresh"
+	// SigningKeyPurposeAudit keys sign audit log checkpoints. They are never
+	// rotated out, since old checkpoints must stay verifiable for as long as
+	// the log is kept.
+	SigningKeyPurposeAudit SigningKeyPurpose = "audit"
+)
+
+type SigningKeyState string
+
+const (
+	// SigningKeyStateActive keys sign new tokens once their activation time
+	// has passed. When several active keys qualify the most recently
+	// activated one wins.
+	SigningKeyStateActive SigningKeyState = "active"
+	// SigningKeyStateVerifyOnly keys no longer sign but still verify tokens
+	// until they expire.
+	SigningKeyStateVerifyOnly SigningKeyState = "verify-only"
+	// SigningKeyStateRetired keys verify nothing and are waiting to be
+	// removed.
+	SigningKeyStateRetired SigningKeyState = "retired"
+)
+
+type SigningKey interface {
+	GetKeyID() string
+	GetPurpose() SigningKeyPurpose
+	GetAlgorithm() string
+	GetWrappedMaterial() []byte
+	GetState() SigningKeyState
+	GetActivatesAt() time.Time
+	GetExpiresAt() *time.Time
+	GetRemoveAt() *time.Time
+	GetCreatedAt() time.Time
+	CanSign(now time.Time) bool
+	CanVerify(now time.Time) bool
+	ExpireAt(at time.Time)
+	Demote()
+	Retire(now time.Time, retention time.Duration)
+}
+
+type signingKey struct {
+	keyID           string
+	purpose         SigningKeyPurpose
+	algorithm       string
+	wrappedMaterial []byte
+	state           SigningKeyState
+	activatesAt     time.Time
+	expiresAt       *time.Time
+	removeAt        *time.Time
+	createdAt       time.Time
+}
+
+type SigningKeyProps struct {
+	KeyID           string
+	Purpose         SigningKeyPurpose
+	Algorithm       string
+	WrappedMaterial []byte
+	State           SigningKeyState
+	ActivatesAt     time.Time
+	ExpiresAt       *time.Time
+	RemoveAt        *time.Time
+	CreatedAt       time.Time
+}
+
+func NewSigningKey(props SigningKeyProps) (SigningKey, *exceptions.BusinessException) {
+	if props.KeyID == "" {
+		return nil, exceptions.NewBusinessException("signing key ID cannot be empty")
+	}
+	switch props.Purpose {
+	case SigningKeyPurposeAccess, SigningKeyPurposeRefresh, SigningKeyPurposeAudit:
+	default:
+		return nil, exceptions.NewBusinessException("signing key purpose must be access, refresh or audit")
+	}
+	if props.Algorithm == "" {
+		return nil, exceptions.NewBusinessException("signing key algorithm cannot be empty")
+	}
+	if len(props.WrappedMaterial) == 0 {
+		return nil, exceptions.NewBusinessException("signing key material cannot be empty")
+	}
+
+	key := &signingKey{
+		keyID:           props.KeyID,
+		purpose:         props.Purpose,
+		algorithm:       props.Algorithm,
+		wrappedMaterial: props.WrappedMaterial,
+		state:           props.State,
+		activatesAt:     props.ActivatesAt,
+		expiresAt:       props.ExpiresAt,
+		removeAt:        props.RemoveAt,
+		createdAt:       props.CreatedAt,
+	}
+
+	if key.state == "" {
+		key.state = SigningKeyStateActive
+	}
+	if key.createdAt.IsZero() {
+		key.createdAt = time.Now()
+	}
+	if key.activatesAt.IsZero() {
+		key.activatesAt = key.createdAt
+	}
+
+	return key, nil
+}
+
+func LoadSigningKey(props SigningKeyProps) (SigningKey, *exceptions.BusinessException) {
+	return NewSigningKey(props)
+}
+
+func (k *signingKey) GetKeyID() string {
+	return k.keyID
+}
+
+func (k *signingKey) GetPurpose() SigningKeyPurpose {
+	return k.purpose
+}
+
+func (k *signingKey) GetAlgorithm() string {
+	return k.algorithm
+}
+
+func (k *signingKey) GetWrappedMaterial() []byte {
+	return k.wrappedMaterial
+}
+
+func (k *signingKey) GetState() SigningKeyState {
+	return k.state
+}
+
+func (k *signingKey) GetActivatesAt() time.Time {
+	return k.activatesAt
+}
+
+func (k *signingKey) GetExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+func (k *signingKey) GetRemoveAt() *time.Time {
+	return k.removeAt
+}
+
+func (k *signingKey) GetCreatedAt() time.Time {
+	return k.createdAt
+}
+
+func (k *signingKey) CanSign(now time.Time) bool {
+	return k.state == SigningKeyStateActive && !now.Before(k.activatesAt) && k.CanVerify(now)
+}
+
+// CanVerify reports whether tokens signed with the key are still accepted.
+// Keys that are published ahead of their activation already verify, which
+// lets relying parties pick them up before the first token appears.
+func (k *signingKey) CanVerify(now time.Time) bool {
+	if k.state == SigningKeyStateRetired {
+		return false
+	}
+	return k.expiresAt == nil || now.Before(*k.expiresAt)
+}
+
+// ExpireAt bounds the verification window of the key. An earlier existing
+// bound is kept so overlapping rotations never extend a key's lifetime.
+func (k *signingKey) ExpireAt(at time.Time) {
+	if k.expiresAt != nil && k.expiresAt.Before(at) {
+		return
+	}
+	k.expiresAt = &at
+}
+
+func (k *signingKey) Demote() {
+	if k.state == SigningKeyStateActive {
+		k.state = SigningKeyStateVerifyOnly
+	}
+}
+
+func (k *signingKey) Retire(now time.Time, retention time.Duration) {
+	removeAt := now.Add(retention)
+	k.state = SigningKeyStateRetired
+	k.removeAt = &removeAt
+}
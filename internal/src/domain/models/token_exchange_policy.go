@@ -0,0 +1,92 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+// TokenExchangeRequest is what a token exchange policy decides on: the
+// client performing the exchange, who acts and on whose behalf, and what the
+// new token is asked to carry.
+type TokenExchangeRequest struct {
+	ClientID      string
+	HasActor      bool
+	ActorRoles    []string
+	SubjectRoles  []string
+	Impersonation bool
+	Audiences     []string
+	Scopes        []string
+}
+
+// TokenExchangeRule allows one client to exchange tokens. An exchange is
+// allowed when every condition of a rule holds; scopes, audiences and the
+// lifetime of the issued token are capped by the rule.
+type TokenExchangeRule struct {
+	// ClientID is the client the rule applies to, or "*" for any client.
+	ClientID string `json:"client_id"`
+	// ActorRoles requires an actor token whose user holds one of the roles.
+	// When empty, the client itself is the actor.
+	ActorRoles []string `json:"actor_roles"`
+	// Impersonate allows the subject to be named by user id instead of by
+	// one of their tokens. It requires ActorRoles.
+	Impersonate bool `json:"impersonate"`
+	// ExcludedSubjectRoles lists roles whose holders can never be the
+	// subject of an exchange under this rule.
+	ExcludedSubjectRoles []string `json:"excluded_subject_roles"`
+	Audiences            []string `json:"audiences"`
+	Scopes               []string `json:"scopes"`
+	MaxTTLSeconds        int      `json:"max_ttl_seconds"`
+}
+
+func (r TokenExchangeRule) Validate() *exceptions.BusinessException {
+	if r.ClientID == "" {
+		return exceptions.NewBusinessException("token exchange rule must name a client or \"*\"")
+	}
+	if r.Impersonate && len(r.ActorRoles) == 0 {
+		return exceptions.NewBusinessException("impersonation rules must require actor roles")
+	}
+	if len(r.Audiences) == 0 {
+		return exceptions.NewBusinessException("token exchange rule must allow at least one audience")
+	}
+	if r.MaxTTLSeconds < 0 {
+		return exceptions.NewBusinessException("token exchange rule ttl cannot be negative")
+	}
+	return nil
+}
+
+// Allows reports whether the rule permits request.
+func (r TokenExchangeRule) Allows(request TokenExchangeRequest) bool {
+	if r.ClientID != "*" && r.ClientID != request.ClientID {
+		return false
+	}
+	if request.Impersonation && !r.Impersonate {
+		return false
+	}
+	if len(r.ActorRoles) > 0 && (!request.HasActor || !hasAnyRole(request.ActorRoles, r.ActorRoles)) {
+		return false
+	}
+	if hasAnyRole(request.SubjectRoles, r.ExcludedSubjectRoles) {
+		return false
+	}
+	for _, audience := range request.Audiences {
+		if !slices.Contains(r.Audiences, audience) {
+			return false
+		}
+	}
+	for _, scope := range request.Scopes {
+		if !slices.Contains(r.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyRole(roles, wanted []string) bool {
+	for _, role := range roles {
+		if slices.Contains(wanted, role) {
+			return true
+		}
+	}
+	return false
+}
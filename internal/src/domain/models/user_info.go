@@ -1,34 +1,59 @@
 package models
 
-import "github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+const (
+	maxMetadataEntries     = 50
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 1024
+)
 
 type UserInfo interface {
 	GetUserID() string
 	GetName() string
 	GetRoles() []string
+	GetMetadata() map[string]string
+	Rename(name string)
+	UpdateMetadata(set map[string]string, remove []string) *exceptions.BusinessException
+	UpdateRoles(add []string, remove []string) (added []string, removed []string)
 }
 
 type userInfo struct {
-	userID string
-	name   string
-	roles  []string
+	userID   string
+	name     string
+	roles    []string
+	metadata map[string]string
 }
 
 type UserInfoProps struct {
-	UserID string
-	Name   string
-	Roles  []string
+	UserID   string
+	Name     string
+	Roles    []string
+	Metadata map[string]string
 }
 
 func NewUserInfo(props UserInfoProps) (UserInfo, *exceptions.BusinessException) {
 	if props.UserID == "" {
 		return nil, exceptions.NewBusinessException("user ID cannot be empty")
 	}
+	if err := validateMetadata(props.Metadata); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]string{}
+	maps.Copy(metadata, props.Metadata)
 
 	return &userInfo{
-		userID: props.UserID,
-		name:   props.Name,
-		roles:  props.Roles,
+		userID:   props.UserID,
+		name:     props.Name,
+		roles:    props.Roles,
+		metadata: metadata,
 	}, nil
 }
 
@@ -40,4 +65,75 @@ func (u *userInfo) GetName() string {
 }
 func (u *userInfo) GetRoles() []string {
 	return u.roles
-}
\ No newline at end of file
+}
+
+// GetMetadata returns a copy of the free-form attributes attached to the
+// user, such as plan, org_id or locale.
+func (u *userInfo) GetMetadata() map[string]string {
+	return maps.Clone(u.metadata)
+}
+
+func (u *userInfo) Rename(name string) {
+	u.name = name
+}
+
+// UpdateMetadata sets the given keys and then deletes the removed ones. The
+// user is left unchanged when the result would be invalid.
+func (u *userInfo) UpdateMetadata(set map[string]string, remove []string) *exceptions.BusinessException {
+	metadata := maps.Clone(u.metadata)
+	maps.Copy(metadata, set)
+	for _, key := range remove {
+		delete(metadata, key)
+	}
+
+	if err := validateMetadata(metadata); err != nil {
+		return err
+	}
+
+	u.metadata = metadata
+	return nil
+}
+
+// UpdateRoles grants the added roles and then revokes the removed ones. It
+// returns the roles that were actually granted and revoked.
+func (u *userInfo) UpdateRoles(add []string, remove []string) ([]string, []string) {
+	var added, removed []string
+
+	roles := slices.Clone(u.roles)
+	for _, role := range add {
+		if role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+			added = append(added, role)
+		}
+	}
+	for _, role := range remove {
+		if index := slices.Index(roles, role); index >= 0 {
+			roles = slices.Delete(roles, index, index+1)
+			if i := slices.Index(added, role); i >= 0 {
+				added = slices.Delete(added, i, i+1)
+			} else {
+				removed = append(removed, role)
+			}
+		}
+	}
+
+	u.roles = roles
+	return added, removed
+}
+
+func validateMetadata(metadata map[string]string) *exceptions.BusinessException {
+	if len(metadata) > maxMetadataEntries {
+		return exceptions.NewBusinessException(fmt.Sprintf("user metadata cannot have more than %d entries", maxMetadataEntries))
+	}
+
+	for key, value := range metadata {
+		if key == "" || len(key) > maxMetadataKeyLength {
+			return exceptions.NewBusinessException(fmt.Sprintf("user metadata keys must be 1 to %d characters long", maxMetadataKeyLength))
+		}
+		if len(value) > maxMetadataValueLength {
+			return exceptions.NewBusinessException(fmt.Sprintf("user metadata value of %q exceeds %d characters", key, maxMetadataValueLength))
+		}
+	}
+
+	return nil
+}
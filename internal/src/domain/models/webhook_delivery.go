@@ -0,0 +1,193 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending deliveries are waiting for their next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded deliveries got a 2xx answer.
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead deliveries failed too often and are only retried
+	// on request.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event on its way to one subscription, together
+// with the outcome of the latest attempt. Payload is the exact body that is
+// signed and sent.
+type WebhookDelivery interface {
+	GetID() string
+	GetSubscriptionID() string
+	GetEventID() string
+	GetEventType() string
+	GetPayload() []byte
+	GetStatus() WebhookDeliveryStatus
+	GetAttempts() int
+	GetNextAttemptAt() time.Time
+	GetLastStatusCode() int
+	GetLastError() string
+	GetCreatedAt() time.Time
+	GetDeliveredAt() *time.Time
+	RecordSuccess(at time.Time, statusCode int)
+	RecordFailure(at time.Time, statusCode int, reason string, maxAttempts int, retryDelay time.Duration)
+	Requeue(at time.Time)
+}
+
+type webhookDelivery struct {
+	id             string
+	subscriptionID string
+	eventID        string
+	eventType      string
+	payload        []byte
+	status         WebhookDeliveryStatus
+	attempts       int
+	nextAttemptAt  time.Time
+	lastStatusCode int
+	lastError      string
+	createdAt      time.Time
+	deliveredAt    *time.Time
+}
+
+type WebhookDeliveryProps struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewWebhookDelivery(props WebhookDeliveryProps) (WebhookDelivery, *exceptions.BusinessException) {
+	if props.SubscriptionID == "" || props.EventID == "" {
+		return nil, exceptions.NewBusinessException("webhook delivery needs a subscription and an event")
+	}
+	if len(props.Payload) == 0 {
+		return nil, exceptions.NewBusinessException("webhook delivery payload cannot be empty")
+	}
+
+	delivery := &webhookDelivery{
+		id:             props.ID,
+		subscriptionID: props.SubscriptionID,
+		eventID:        props.EventID,
+		eventType:      props.EventType,
+		payload:        props.Payload,
+		status:         props.Status,
+		attempts:       props.Attempts,
+		nextAttemptAt:  props.NextAttemptAt,
+		lastStatusCode: props.LastStatusCode,
+		lastError:      props.LastError,
+		createdAt:      props.CreatedAt,
+		deliveredAt:    props.DeliveredAt,
+	}
+
+	if delivery.id == "" {
+		delivery.id = utils.GenerateUUID()
+	}
+	if delivery.status == "" {
+		delivery.status = WebhookDeliveryPending
+	}
+	if delivery.createdAt.IsZero() {
+		delivery.createdAt = time.Now()
+	}
+	if delivery.nextAttemptAt.IsZero() {
+		delivery.nextAttemptAt = delivery.createdAt
+	}
+
+	return delivery, nil
+}
+
+func LoadWebhookDelivery(props WebhookDeliveryProps) (WebhookDelivery, *exceptions.BusinessException) {
+	return NewWebhookDelivery(props)
+}
+
+func (d *webhookDelivery) GetID() string {
+	return d.id
+}
+
+func (d *webhookDelivery) GetSubscriptionID() string {
+	return d.subscriptionID
+}
+
+func (d *webhookDelivery) GetEventID() string {
+	return d.eventID
+}
+
+func (d *webhookDelivery) GetEventType() string {
+	return d.eventType
+}
+
+func (d *webhookDelivery) GetPayload() []byte {
+	return d.payload
+}
+
+func (d *webhookDelivery) GetStatus() WebhookDeliveryStatus {
+	return d.status
+}
+
+func (d *webhookDelivery) GetAttempts() int {
+	return d.attempts
+}
+
+func (d *webhookDelivery) GetNextAttemptAt() time.Time {
+	return d.nextAttemptAt
+}
+
+func (d *webhookDelivery) GetLastStatusCode() int {
+	return d.lastStatusCode
+}
+
+func (d *webhookDelivery) GetLastError() string {
+	return d.lastError
+}
+
+func (d *webhookDelivery) GetCreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *webhookDelivery) GetDeliveredAt() *time.Time {
+	return d.deliveredAt
+}
+
+func (d *webhookDelivery) RecordSuccess(at time.Time, statusCode int) {
+	d.attempts++
+	d.status = WebhookDeliverySucceeded
+	d.lastStatusCode = statusCode
+	d.lastError = ""
+	d.deliveredAt = &at
+}
+
+// RecordFailure counts a failed attempt and schedules the next one after
+// retryDelay, or dead-letters the delivery once maxAttempts is reached.
+func (d *webhookDelivery) RecordFailure(at time.Time, statusCode int, reason string, maxAttempts int, retryDelay time.Duration) {
+	d.attempts++
+	d.lastStatusCode = statusCode
+	d.lastError = reason
+
+	if d.attempts >= maxAttempts {
+		d.status = WebhookDeliveryDead
+		return
+	}
+
+	d.status = WebhookDeliveryPending
+	d.nextAttemptAt = at.Add(retryDelay)
+}
+
+// Requeue gives a dead delivery a fresh set of attempts starting at at.
+func (d *webhookDelivery) Requeue(at time.Time) {
+	d.status = WebhookDeliveryPending
+	d.attempts = 0
+	d.nextAttemptAt = at
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+)
+
+const (
+	DefaultWebhookMaxAttempts = 8
+	maxWebhookMaxAttempts     = 50
+	minWebhookSecretLength    = 16
+)
+
+// WebhookSubscription asks for the domain events of the given types to be
+// POSTed to URL, signed with Secret. No event types means every type.
+// Deliveries are dead-lettered after MaxAttempts failures.
+type WebhookSubscription interface {
+	GetID() string
+	GetURL() string
+	GetEventTypes() []string
+	GetSecret() string
+	GetMaxAttempts() int
+	GetCreatedAt() time.Time
+	Matches(eventType string) bool
+}
+
+type webhookSubscription struct {
+	id          string
+	url         string
+	eventTypes  []string
+	secret      string
+	maxAttempts int
+	createdAt   time.Time
+}
+
+type WebhookSubscriptionProps struct {
+	ID          string
+	URL         string
+	EventTypes  []string
+	Secret      string
+	MaxAttempts int
+	CreatedAt   time.Time
+}
+
+func NewWebhookSubscription(props WebhookSubscriptionProps) (WebhookSubscription, *exceptions.BusinessException) {
+	target, err := url.Parse(props.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return nil, exceptions.NewBusinessException("webhook URL must be an absolute http or https URL")
+	}
+	if target.User != nil {
+		return nil, exceptions.NewBusinessException("webhook URL must not carry credentials")
+	}
+	for _, eventType := range props.EventTypes {
+		if !slices.Contains(DomainEventTypes, eventType) {
+			return nil, exceptions.NewBusinessException("unknown event type: " + eventType)
+		}
+	}
+	if len(props.Secret) < minWebhookSecretLength {
+		return nil, exceptions.NewBusinessException("webhook secret must be at least 16 characters")
+	}
+	if props.MaxAttempts < 0 || props.MaxAttempts > maxWebhookMaxAttempts {
+		return nil, exceptions.NewBusinessException("webhook max attempts must be between 1 and 50")
+	}
+
+	subscription := &webhookSubscription{
+		id:          props.ID,
+		url:         props.URL,
+		eventTypes:  props.EventTypes,
+		secret:      props.Secret,
+		maxAttempts: props.MaxAttempts,
+		createdAt:   props.CreatedAt,
+	}
+
+	if subscription.id == "" {
+		subscription.id = utils.GenerateUUID()
+	}
+	if subscription.eventTypes == nil {
+		subscription.eventTypes = []string{}
+	}
+	if subscription.maxAttempts == 0 {
+		subscription.maxAttempts = DefaultWebhookMaxAttempts
+	}
+	if subscription.createdAt.IsZero() {
+		subscription.createdAt = time.Now()
+	}
+
+	return subscription, nil
+}
+
+func LoadWebhookSubscription(props WebhookSubscriptionProps) (WebhookSubscription, *exceptions.BusinessException) {
+	return NewWebhookSubscription(props)
+}
+
+func (s *webhookSubscription) GetID() string {
+	return s.id
+}
+
+func (s *webhookSubscription) GetURL() string {
+	return s.url
+}
+
+func (s *webhookSubscription) GetEventTypes() []string {
+	return s.eventTypes
+}
+
+func (s *webhookSubscription) GetSecret() string {
+	return s.secret
+}
+
+func (s *webhookSubscription) GetMaxAttempts() int {
+	return s.maxAttempts
+}
+
+func (s *webhookSubscription) GetCreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *webhookSubscription) Matches(eventType string) bool {
+	return len(s.eventTypes) == 0 || slices.Contains(s.eventTypes, eventType)
+}
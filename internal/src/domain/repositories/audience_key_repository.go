@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type IAudienceKeyRepository interface {
+	Save(ctx context.Context, key models.AudienceKey) error
+	GetByAudience(ctx context.Context, audience string) (models.AudienceKey, error)
+	Delete(ctx context.Context, audience string) error
+}
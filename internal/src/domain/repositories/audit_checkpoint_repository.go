@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// IAuditCheckpointRepository stores signed audit checkpoints. Saving a
+// checkpoint for a sequence that already has one is a no-op, so several
+// instances may checkpoint the same head, except that an anchor replaces
+// it. GetLatestAnchor returns the anchor with the highest sequence.
+type IAuditCheckpointRepository interface {
+	Save(ctx context.Context, checkpoint models.AuditCheckpoint) error
+	GetLatest(ctx context.Context) (models.AuditCheckpoint, error)
+	GetLatestAnchor(ctx context.Context) (models.AuditCheckpoint, error)
+	ListFrom(ctx context.Context, sequence int64) ([]models.AuditCheckpoint, error)
+	DeleteBefore(ctx context.Context, sequence int64) error
+}
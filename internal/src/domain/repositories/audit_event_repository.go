@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// AuditEventQuery selects audit events newest first. UserID matches either
+// the actor or the subject. After continues a previous page from the last
+// event it returned.
+type AuditEventQuery struct {
+	UserID string
+	Types  []string
+	From   *time.Time
+	To     *time.Time
+	After  *AuditEventCursor
+	Limit  int
+}
+
+type AuditEventCursor struct {
+	OccurredAt time.Time
+	ID         string
+}
+
+// IAuditEventRepository stores the audit log. Save stores an event as
+// pending and Seal appends up to limit pending events to the hash chain,
+// returning how many it appended. Only chained events have a sequence.
+// SequenceRange returns the first and last chain positions of the events
+// that occurred in [from, to), or zeros when there are none; nil bounds are
+// open. ListBySequence returns chained events in [first, last] in chain
+// order. DeleteBefore removes the chain before sequence, a prefix of it and
+// never a hole, along with unchained events that occurred before before.
+type IAuditEventRepository interface {
+	Save(ctx context.Context, event models.AuditEvent) error
+	Seal(ctx context.Context, limit int) (int, error)
+	Query(ctx context.Context, query AuditEventQuery) ([]models.AuditEvent, error)
+	SequenceRange(ctx context.Context, from, to *time.Time) (int64, int64, error)
+	ListBySequence(ctx context.Context, first, last int64, limit int) ([]models.AuditEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time, sequence int64) (int64, error)
+}
@@ -6,9 +6,16 @@ import (
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
 )
 
+// IAuthRepository persists users. Save, Delete and UpdatePassword also write
+// the matching domain event to the outbox in the same transaction.
+// RecordLoginAttempt counts consecutive wrong passwords and returns the new
+// count; a successful login resets it, and so does UpdatePassword.
 type IAuthRepository interface {
 	Save(ctx context.Context, auth models.Auth) (models.Auth, error)
 	GetByUserID(ctx context.Context, userID string) (models.Auth, error)
 	GetByIdentifier(ctx context.Context, identifierType int, identifierValue string) (models.Auth, error)
+	UpdateUserInfo(ctx context.Context, userInfo models.UserInfo) error
 	Delete(ctx context.Context, userID string) error
+	UpdatePassword(ctx context.Context, userID string, password string) error
+	RecordLoginAttempt(ctx context.Context, userID string, success bool) (int, error)
 }
\ No newline at end of file
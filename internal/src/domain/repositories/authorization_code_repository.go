@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type IAuthorizationCodeRepository interface {
+	Save(ctx context.Context, code models.AuthorizationCode) error
+	// Consume atomically removes the code and returns it, so a code can be
+	// redeemed at most once even under concurrent token requests.
+	Consume(ctx context.Context, codeHash string) (models.AuthorizationCode, error)
+}
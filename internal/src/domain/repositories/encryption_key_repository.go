@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type IEncryptionKeyRepository interface {
+	Save(ctx context.Context, key models.EncryptionKey) error
+	List(ctx context.Context) ([]models.EncryptionKey, error)
+	Delete(ctx context.Context, keyID string) error
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type IOAuthClientRepository interface {
+	Save(ctx context.Context, client models.OAuthClient) (models.OAuthClient, error)
+	GetByClientID(ctx context.Context, clientID string) (models.OAuthClient, error)
+}
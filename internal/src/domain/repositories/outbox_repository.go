@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// IOutboxRepository hands out the events the authRepository wrote to the
+// outbox. Claim leases up to limit messages until leaseUntil, and only the
+// oldest undelivered message of each user, so messages of one user are
+// never in flight at the same time. A message whose lease expires before it
+// is marked is claimed again.
+type IOutboxRepository interface {
+	Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]models.OutboxMessage, error)
+	MarkPublished(ctx context.Context, eventID string) error
+	MarkFailed(ctx context.Context, eventID string, reason string, retryAt time.Time) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
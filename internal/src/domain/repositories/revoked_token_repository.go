@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type IRevokedTokenRepository interface {
+	Save(ctx context.Context, token models.RevokedToken) error
+	// IsRevoked reports whether any of the given token or session ids has
+	// been revoked.
+	IsRevoked(ctx context.Context, tokenIDs ...string) (bool, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+type ISigningKeyRepository interface {
+	Save(ctx context.Context, key models.SigningKey) error
+	List(ctx context.Context) ([]models.SigningKey, error)
+	Delete(ctx context.Context, keyID string) error
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// WebhookDeliveryQuery selects the deliveries of a subscription newest
+// first. After continues a previous page from the last delivery it returned.
+type WebhookDeliveryQuery struct {
+	SubscriptionID string
+	Status         models.WebhookDeliveryStatus
+	After          *WebhookDeliveryCursor
+	Limit          int
+}
+
+type WebhookDeliveryCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// IWebhookDeliveryRepository stores webhook deliveries. Enqueue ignores a
+// delivery of an event the subscription already has, which makes fan-out
+// safe to repeat. Claim leases due pending deliveries until leaseUntil; a
+// delivery whose lease expires before it is saved is claimed again.
+type IWebhookDeliveryRepository interface {
+	Enqueue(ctx context.Context, delivery models.WebhookDelivery) error
+	Save(ctx context.Context, delivery models.WebhookDelivery) error
+	GetByID(ctx context.Context, id string) (models.WebhookDelivery, error)
+	Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error)
+	Query(ctx context.Context, query WebhookDeliveryQuery) ([]models.WebhookDelivery, error)
+}
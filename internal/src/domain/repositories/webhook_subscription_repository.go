@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// IWebhookSubscriptionRepository stores webhook subscriptions. Deleting a
+// subscription also deletes its deliveries.
+type IWebhookSubscriptionRepository interface {
+	Save(ctx context.Context, subscription models.WebhookSubscription) error
+	GetByID(ctx context.Context, id string) (models.WebhookSubscription, error)
+	List(ctx context.Context) ([]models.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeDenied  = "denied"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent describes a security relevant action: who did it, to whom,
+// through which client and with what result. Where the request came from is
+// taken from the context by the service. TenantID is the tenant of the
+// subject, when it has one; it is stored as the tenant_id detail.
+type AuditEvent struct {
+	Type      string
+	ActorID   string
+	SubjectID string
+	ClientID  string
+	TenantID  string
+	Outcome   string
+	Reason    string
+	Details   map[string]interface{}
+}
+
+// IAuditService records audit events. Recording never fails the action being
+// audited; an event that cannot be stored is logged instead.
+//
+// Seal appends the events stored since the last run to the audit chain.
+//
+// Checkpoint signs the current head of the audit chain when the last
+// checkpoint is old enough, and VerifyCheckpoint checks such a signature.
+//
+// Recorded returns a channel that is closed the next time this instance
+// appends events to the chain, so followers of the log can wait for new events instead
+// of polling.
+type IAuditService interface {
+	Record(ctx context.Context, event AuditEvent)
+	Seal(ctx context.Context) error
+	PurgeExpired(ctx context.Context) error
+	Checkpoint(ctx context.Context) error
+	VerifyCheckpoint(ctx context.Context, checkpoint models.AuditCheckpoint) error
+	Recorded() <-chan struct{}
+}
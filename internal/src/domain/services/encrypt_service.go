@@ -1,8 +1,41 @@
 package services
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// TokenEnvelope is the encryption format a token was issued in.
+type TokenEnvelope string
+
+const (
+	// TokenEnvelopeNone is a plain signed JWT.
+	TokenEnvelopeNone TokenEnvelope = "none"
+	// TokenEnvelopeJWE is a compact serialized JWE.
+	TokenEnvelopeJWE TokenEnvelope = "jwe"
+	// TokenEnvelopeLegacy is the headerless RSA-OAEP+AES-GCM envelope
+	// issued before JWE support.
+	TokenEnvelopeLegacy TokenEnvelope = "legacy"
+)
+
+// PublicEncryptionKey is a consumer's public key normalized to a JWK with
+// its use, alg and kid filled in.
+type PublicEncryptionKey struct {
+	KeyID     string
+	Algorithm string
+	JWK       string
+}
 
 type IEncryptService interface {
 	Encrypt(ctx context.Context, text string) (*string, error)
 	Decrypt(ctx context.Context, encryptedText string) (string, error)
-}
\ No newline at end of file
+	EncryptFor(ctx context.Context, text string, key models.AudienceKey) (*string, error)
+	ParsePublicKey(publicKey string) (*PublicEncryptionKey, error)
+	Envelope(token string) TokenEnvelope
+	RotateEncryptionKey(ctx context.Context, algorithm string, activateAt time.Time) (models.EncryptionKey, error)
+	ListEncryptionKeys(ctx context.Context) ([]models.EncryptionKey, error)
+	MaintainEncryptionKeys(ctx context.Context) error
+	CheckKeys(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// IEventPublisher delivers domain events to other services. A nil error
+// means the event was accepted; anything else makes the relay retry it.
+type IEventPublisher interface {
+	Publish(ctx context.Context, event models.DomainEvent) error
+}
@@ -0,0 +1,13 @@
+package services
+
+import "context"
+
+// IFieldEncryptionService protects personal data stored at rest. Encrypted
+// values are randomized, so exact-match lookups go through BlindIndex, a
+// keyed hash of the plaintext.
+type IFieldEncryptionService interface {
+	EncryptField(ctx context.Context, plaintext string) (string, error)
+	DecryptField(ctx context.Context, value string) (string, error)
+	IsEncrypted(value string) bool
+	BlindIndex(value string) string
+}
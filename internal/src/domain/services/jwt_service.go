@@ -1,10 +1,71 @@
 package services
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// AccessTokenClaims describes what goes into an access token. Optional
+// fields are left out of the token when empty. Actor is the RFC 8693 act
+// claim of tokens obtained through token exchange. Only the metadata keys
+// enabled by the claim mapping end up in the token.
+type AccessTokenClaims struct {
+	UserID    string
+	Roles     []string
+	ExpiresIn int
+	Scope     string
+	ClientID  string
+	SessionID string
+	Audience  []string
+	Actor     map[string]interface{}
+	Metadata  map[string]string
+}
+
+// RefreshTokenClaims describes what goes into a refresh token. The session
+// id ties it to the access tokens minted from it so they can be revoked
+// together.
+type RefreshTokenClaims struct {
+	UserID    string
+	ExpiresIn int
+	Scope     string
+	ClientID  string
+	SessionID string
+	Audience  []string
+}
+
+// TokenExpectations narrows which access tokens ExtractClaims accepts.
+// Empty fields are not checked.
+type TokenExpectations struct {
+	Audience string
+	Issuer   string
+}
+
+// JSONWebKey is the RFC 7517 representation of a public verification key.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
 
 type IJWTService interface {
-	GenerateToken(ctx context.Context, userID string, roles []string, exp int) (*string, error)
-	GenerateRefreshToken(ctx context.Context, userID string, exp int) (*string, error)
-	ExtractClaims(ctx context.Context, token string) (map[string]interface{}, error)
+	GenerateToken(ctx context.Context, claims AccessTokenClaims) (*string, error)
+	GenerateRefreshToken(ctx context.Context, claims RefreshTokenClaims) (*string, error)
+	GenerateIDToken(ctx context.Context, claims map[string]interface{}, exp int) (*string, error)
+	ExtractClaims(ctx context.Context, token string, expectations TokenExpectations) (map[string]interface{}, error)
 	ExtractRefreshClaims(ctx context.Context, token string) (map[string]interface{}, error)
-}
\ No newline at end of file
+	GetIssuer() string
+	GetSigningAlgorithm() string
+	GetPublicKeys(ctx context.Context) ([]JSONWebKey, error)
+	RotateSigningKey(ctx context.Context, purpose models.SigningKeyPurpose, algorithm string, activateAt time.Time) (models.SigningKey, error)
+	ListSigningKeys(ctx context.Context) ([]models.SigningKey, error)
+	MaintainSigningKeys(ctx context.Context) error
+	CheckKeys(ctx context.Context) error
+}
@@ -0,0 +1,11 @@
+package services
+
+import "context"
+
+// IKeyManagementService protects key material at rest. Wrap and Unwrap are
+// the only operations authgate needs, which keeps the port small enough to
+// back with a cloud KMS later.
+type IKeyManagementService interface {
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
@@ -0,0 +1,24 @@
+package services
+
+import "time"
+
+// Outcomes of a login attempt, as counted by IMetrics.
+const (
+	LoginOutcomeSuccess     = "success"
+	LoginOutcomeBadPassword = "bad_password"
+	LoginOutcomeLocked      = "locked"
+	LoginOutcomeUnknownUser = "unknown_user"
+	LoginOutcomeError       = "error"
+)
+
+// IMetrics records what the service does for monitoring. Every method is
+// cheap and safe to call concurrently.
+type IMetrics interface {
+	ObserveRPC(method, code string, took time.Duration)
+	LoginAttempt(outcome string)
+	TokenIssued(tokenType string)
+	TokenVerified(tokenType string, valid bool)
+	TokenRefreshed(success bool)
+	ObservePasswordHash(operation string, took time.Duration)
+	ObserveEncryption(operation string, took time.Duration)
+}
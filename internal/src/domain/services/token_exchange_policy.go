@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+// ITokenExchangePolicy decides who may exchange tokens for whom. Evaluate
+// returns the first rule that allows the request, or nil when none does.
+type ITokenExchangePolicy interface {
+	Evaluate(ctx context.Context, request models.TokenExchangeRequest) *models.TokenExchangeRule
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookResult is the outcome of one webhook request. StatusCode is zero
+// when no response was received.
+type WebhookResult struct {
+	StatusCode int
+	Err        error
+}
+
+// IWebhookSender signs payload with secret and POSTs it to url. The
+// signature covers sentAt, so receivers can refuse replays.
+type IWebhookSender interface {
+	Send(ctx context.Context, url, secret, deliveryID string, payload []byte, sentAt time.Time) WebhookResult
+}
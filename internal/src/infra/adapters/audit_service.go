@@ -0,0 +1,376 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type auditService struct {
+	auditEventRepo      repositories.IAuditEventRepository
+	auditCheckpointRepo repositories.IAuditCheckpointRepository
+	signingKeyRepo      repositories.ISigningKeyRepository
+	kms                 services.IKeyManagementService
+	ring                *signingKeyRing
+	retention           time.Duration
+	checkpointInterval  time.Duration
+	seeds               config.AuditConfig
+	logger              *slog.Logger
+
+	mu       sync.Mutex
+	recorded chan struct{}
+}
+
+// NewAuditService stores audit events in the database and keeps them for
+// the configured retention, one year by default. Every checkpoint interval,
+// one hour by default, the head of the chain is signed with the audit key.
+// That key lives in the signing key ring; an empty ring is seeded from the
+// configured PEM private key (Ed25519, P-256 or RSA, matching the signing
+// algorithm) or generated.
+func NewAuditService(
+	cfg config.AuditConfig,
+	auditEventRepo repositories.IAuditEventRepository,
+	auditCheckpointRepo repositories.IAuditCheckpointRepository,
+	signingKeyRepo repositories.ISigningKeyRepository,
+	kms services.IKeyManagementService,
+	logger *slog.Logger,
+) services.IAuditService {
+	service := &auditService{
+		auditEventRepo:      auditEventRepo,
+		auditCheckpointRepo: auditCheckpointRepo,
+		signingKeyRepo:      signingKeyRepo,
+		kms:                 kms,
+		ring:                newSigningKeyRing(models.SigningKeyPurposeAudit, kms),
+		retention:           cfg.Retention,
+		checkpointInterval:  cfg.CheckpointInterval,
+		seeds:               cfg,
+		logger:              logger,
+		recorded:            make(chan struct{}),
+	}
+
+	ctx := context.Background()
+	if err := service.seedSigningKey(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to seed audit signing key: %v", err))
+	}
+	if err := service.reload(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to load audit signing key: %v", err))
+	}
+
+	return service
+}
+
+func (s *auditService) Record(ctx context.Context, event services.AuditEvent) {
+	info := utils.RequestInfoFromContext(ctx)
+
+	details := event.Details
+	if event.TenantID != "" {
+		details = maps.Clone(details)
+		if details == nil {
+			details = map[string]interface{}{}
+		}
+		details["tenant_id"] = event.TenantID
+	}
+
+	model, er := models.NewAuditEvent(models.AuditEventProps{
+		Type:      event.Type,
+		ActorID:   event.ActorID,
+		SubjectID: event.SubjectID,
+		ClientID:  event.ClientID,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		PeerIP:    info.PeerIP,
+		UserAgent: info.UserAgent,
+		Details:   details,
+	})
+	if er != nil {
+		s.logger.ErrorContext(ctx, "Invalid audit event", "event_type", event.Type, "error", er)
+		return
+	}
+
+	// The event is stored even when the request was cancelled right after
+	// the action it describes.
+	if err := s.auditEventRepo.Save(context.WithoutCancel(ctx), model); err != nil {
+		line, _ := json.Marshal(event)
+		s.logger.ErrorContext(ctx, "Failed to store audit event", "event", string(line), "error", err)
+	}
+}
+
+// auditSealBatchSize caps the events chained in one transaction.
+const auditSealBatchSize = 500
+
+func (s *auditService) Seal(ctx context.Context) error {
+	for {
+		sealed, err := s.auditEventRepo.Seal(ctx, auditSealBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if sealed > 0 {
+			s.mu.Lock()
+			close(s.recorded)
+			s.recorded = make(chan struct{})
+			s.mu.Unlock()
+		}
+		if sealed < auditSealBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *auditService) Recorded() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recorded
+}
+
+// PurgeExpired drops events past retention together with the checkpoints
+// that covered them. The chain is cut right before the first event still
+// within retention, or before the newest event when all of them expired, so
+// it always goes on from a stored event. The last event to go is signed as
+// an anchor before anything is dropped; the oldest remaining event is
+// verified against it, so events cut from the start of the log by anyone
+// but the purge are noticed.
+func (s *auditService) PurgeExpired(ctx context.Context) error {
+	before := time.Now().Add(-s.retention)
+
+	cut, _, err := s.auditEventRepo.SequenceRange(ctx, &before, nil)
+	if err != nil {
+		return err
+	}
+	if cut == 0 {
+		if _, cut, err = s.auditEventRepo.SequenceRange(ctx, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	if cut > 1 {
+		if err := s.anchor(ctx, cut-1); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.auditEventRepo.DeleteBefore(ctx, before, cut); err != nil {
+		return err
+	}
+	if cut <= 1 {
+		return nil
+	}
+
+	return s.auditCheckpointRepo.DeleteBefore(ctx, cut-1)
+}
+
+// anchor signs the event at sequence as the anchor of the chain, unless an
+// anchor at or after it exists. An event purged before anchors were signed
+// cannot be anchored any more.
+func (s *auditService) anchor(ctx context.Context, sequence int64) error {
+	latest, err := s.auditCheckpointRepo.GetLatestAnchor(ctx)
+	var notFound *exceptions.RepositoryNoDataFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	if latest != nil && latest.GetSequence() >= sequence {
+		return nil
+	}
+
+	events, err := s.auditEventRepo.ListBySequence(ctx, sequence, sequence, 1)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	checkpoint, err := s.sign(events[0], true, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.auditCheckpointRepo.Save(ctx, checkpoint)
+}
+
+func (s *auditService) Checkpoint(ctx context.Context) error {
+	now := time.Now()
+
+	latest, err := s.auditCheckpointRepo.GetLatest(ctx)
+	var notFound *exceptions.RepositoryNoDataFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	if latest != nil && now.Sub(latest.GetCreatedAt()) < s.checkpointInterval {
+		return nil
+	}
+
+	_, last, err := s.auditEventRepo.SequenceRange(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	if last == 0 || (latest != nil && latest.GetSequence() >= last) {
+		return nil
+	}
+
+	events, err := s.auditEventRepo.ListBySequence(ctx, last, last, 1)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	checkpoint, err := s.sign(events[0], false, now)
+	if err != nil {
+		return err
+	}
+
+	return s.auditCheckpointRepo.Save(ctx, checkpoint)
+}
+
+// sign signs the sequence and hash of event with the audit key.
+func (s *auditService) sign(event models.AuditEvent, anchor bool, now time.Time) (models.AuditCheckpoint, error) {
+	key, err := s.ring.signer(now)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{
+		"seq":  event.GetSequence(),
+		"hash": event.GetHash(),
+		"iat":  now.Unix(),
+	}
+	if anchor {
+		claims["anchor"] = true
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	signature, err := token.SignedString(key.signKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign audit checkpoint: %w", err)
+	}
+
+	checkpoint, er := models.NewAuditCheckpoint(models.AuditCheckpointProps{
+		Sequence:  event.GetSequence(),
+		Hash:      event.GetHash(),
+		KeyID:     key.kid,
+		Signature: signature,
+		CreatedAt: now,
+		Anchor:    anchor,
+	})
+	if er != nil {
+		return nil, er
+	}
+
+	return checkpoint, nil
+}
+
+// VerifyCheckpoint checks that the checkpoint was signed by an audit key and
+// that the signed sequence, hash and anchor flag are the ones stored next to
+// it.
+func (s *auditService) VerifyCheckpoint(ctx context.Context, checkpoint models.AuditCheckpoint) error {
+	key := s.ring.lookup(checkpoint.GetKeyID(), time.Now())
+	if key == nil {
+		if err := s.reload(ctx); err != nil {
+			return err
+		}
+		key = s.ring.lookup(checkpoint.GetKeyID(), time.Now())
+	}
+	if key == nil {
+		return fmt.Errorf("unknown audit signing key %s", checkpoint.GetKeyID())
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(checkpoint.GetSignature(), claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		if kid, _ := token.Header["kid"].(string); kid != key.kid {
+			return nil, fmt.Errorf("signature key id does not match")
+		}
+		return key.verifyKey, nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	sequence, _ := claims["seq"].(float64)
+	hash, _ := claims["hash"].(string)
+	anchor, _ := claims["anchor"].(bool)
+	if int64(sequence) != checkpoint.GetSequence() || hash != checkpoint.GetHash() || anchor != checkpoint.IsAnchor() {
+		return fmt.Errorf("signed head does not match the checkpoint")
+	}
+
+	return nil
+}
+
+func (s *auditService) reload(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.ring.load(ctx, keys)
+}
+
+func (s *auditService) seedSigningKey(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.GetPurpose() == models.SigningKeyPurposeAudit {
+			return nil
+		}
+	}
+
+	algorithm := s.seeds.SigningAlgorithm
+	if algorithm == "HS256" {
+		return fmt.Errorf("audit checkpoints need an asymmetric algorithm")
+	}
+
+	var key *signingKey
+	if seed := s.seeds.SigningPrivateKey; seed != "" {
+		key, err = newAsymmetricSigningKey(algorithm, seed)
+	} else {
+		key, err = generateSigningKey(algorithm)
+	}
+	if err != nil {
+		return err
+	}
+
+	material, err := key.material()
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := s.kms.Wrap(ctx, material)
+	if err != nil {
+		return err
+	}
+
+	model, er := models.NewSigningKey(models.SigningKeyProps{
+		KeyID:           key.kid,
+		Purpose:         models.SigningKeyPurposeAudit,
+		Algorithm:       key.method.Alg(),
+		WrappedMaterial: wrapped,
+		State:           models.SigningKeyStateActive,
+		ActivatesAt:     time.Now(),
+	})
+	if er != nil {
+		return er
+	}
+
+	return s.signingKeyRepo.Save(ctx, model)
+}
@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedClaims are set by the token service itself and can never be
+// overridden by user metadata.
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"type": true, "roles": true, "scope": true, "client_id": true, "sid": true, "act": true,
+	"azp": true, "auth_time": true, "nonce": true, "cnf": true,
+}
+
+// parseClaimMapping reads a comma separated list of metadata keys that are
+// copied into access tokens. An entry may rename the claim with key=claim,
+// e.g. "plan,org_id,locale=lang". Keys not listed stay server-side.
+func parseClaimMapping(spec string) (map[string]string, error) {
+	mapping := map[string]string{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, claim, renamed := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		claim = strings.TrimSpace(claim)
+		if !renamed {
+			claim = key
+		}
+
+		if key == "" || claim == "" {
+			return nil, fmt.Errorf("invalid claim mapping entry %q", entry)
+		}
+		if reservedClaims[claim] {
+			return nil, fmt.Errorf("metadata key %q cannot be mapped onto reserved claim %q", key, claim)
+		}
+		mapping[key] = claim
+	}
+
+	return mapping, nil
+}
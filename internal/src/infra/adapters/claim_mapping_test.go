@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+func TestParseClaimMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: map[string]string{}},
+		{name: "keys", spec: "plan,org_id", want: map[string]string{"plan": "plan", "org_id": "org_id"}},
+		{name: "renamed", spec: " plan , locale = lang ,", want: map[string]string{"plan": "plan", "locale": "lang"}},
+		{name: "no key", spec: "=lang", wantErr: true},
+		{name: "no claim", spec: "locale=", wantErr: true},
+		{name: "reserved claim", spec: "sub", wantErr: true},
+		{name: "renamed onto a reserved claim", spec: "team=roles", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseClaimMapping(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseClaimMapping() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseClaimMapping() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseClaimMapping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappedMetadataIsCopiedIntoAccessTokens(t *testing.T) {
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "HS256", MetadataClaims: "plan,locale=lang"})
+
+	token, err := service.GenerateToken(context.Background(), services.AccessTokenClaims{
+		UserID:    "user-1",
+		ExpiresIn: 300,
+		Metadata:  map[string]string{"plan": "pro", "locale": "pt-BR", "internal_note": "vip"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	claims, err := service.ExtractClaims(context.Background(), *token, services.TokenExpectations{})
+	if err != nil {
+		t.Fatalf("ExtractClaims() error = %v", err)
+	}
+
+	if claims["plan"] != "pro" || claims["lang"] != "pt-BR" {
+		t.Errorf("claims = %v, want plan pro and lang pt-BR", claims)
+	}
+	for _, unmapped := range []string{"locale", "internal_note"} {
+		if _, ok := claims[unmapped]; ok {
+			t.Errorf("claims = %v, want no %s", claims, unmapped)
+		}
+	}
+}
+
+func TestNewJWTServiceRejectsReservedClaimMapping(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewJWTService() accepted metadata mapped onto sub")
+		}
+	}()
+	newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "HS256", MetadataClaims: "user=sub"})
+}
@@ -9,167 +9,604 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"io"
-	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/go-jose/go-jose/v4"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	tokenEncryptionFormatJWE    = "jwe"
+	tokenEncryptionFormatLegacy = "legacy"
 )
 
 type encryptService struct {
-    publicKey  *rsa.PublicKey
-    privateKey *rsa.PrivateKey
+	encryptionKeyRepo repositories.IEncryptionKeyRepository
+	kms               services.IKeyManagementService
+	metrics           services.IMetrics
+	ring              *encryptionKeyRing
+	format            string
+	overlap           time.Duration
+	retention         time.Duration
+	seeds             config.TokenEncryptionConfig
+
+	reloadMu   sync.Mutex
+	lastReload time.Time
 }
 
-func NewEncryptService() services.IEncryptService {
-    publicKeyPEM := os.Getenv("RSA_PUBLIC_KEY")
-    privateKeyPEM := os.Getenv("RSA_PRIVATE_KEY")
+// NewEncryptService builds the service on top of the encryption key ring
+// persisted in the database. The configuration only seeds an empty ring:
+// the private key (RSA or EC PEM) becomes the active key and the legacy
+// private key, the RSA key of the legacy envelope, is imported as active
+// when it is the only one configured and as decrypt-only otherwise. A key is
+// generated when neither is set.
+//
+// Tokens are encrypted as compact JWE unless the format is legacy, which
+// keeps issuing the legacy envelope while clients migrate.
+func NewEncryptService(cfg config.TokenEncryptionConfig, encryptionKeyRepo repositories.IEncryptionKeyRepository, kms services.IKeyManagementService, metrics services.IMetrics) services.IEncryptService {
+
+	service := &encryptService{
+		encryptionKeyRepo: encryptionKeyRepo,
+		kms:               kms,
+		metrics:           metrics,
+		ring:              newEncryptionKeyRing(kms),
+		format:            cfg.Format,
+		overlap:           cfg.KeyOverlap,
+		retention:         cfg.RetiredKeyRetention,
+		seeds:             cfg,
+	}
+
+	ctx := context.Background()
+	if err := service.seedKeyRing(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to seed token encryption keys: %v", err))
+	}
+	if err := service.reload(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to load token encryption keys: %v", err))
+	}
+
+	return service
+}
 
-    if publicKeyPEM == "" || privateKeyPEM == "" {
-        panic("RSA_PUBLIC_KEY and RSA_PRIVATE_KEY environment variables must be set")
-    }
+// CheckKeys fails when tokens cannot be encrypted right now.
+func (s *encryptService) CheckKeys(ctx context.Context) error {
+	if _, err := s.ring.encrypter(time.Now()); err != nil {
+		return fmt.Errorf("token encryption key: %w", err)
+	}
 
-    publicKey, err := parsePublicKey(publicKeyPEM)
-    if err != nil {
-        panic(fmt.Sprintf("Failed to parse RSA public key: %v", err))
-    }
+	return nil
+}
 
-    privateKey, err := parsePrivateKey(privateKeyPEM)
-    if err != nil {
-        panic(fmt.Sprintf("Failed to parse RSA private key: %v", err))
-    }
+func (s *encryptService) Encrypt(ctx context.Context, token string) (*string, error) {
+	defer observeSince(s.metrics.ObserveEncryption, "encrypt", time.Now())
+	ctx, span := tracer.Start(ctx, "encryptService.Encrypt")
 
-    return &encryptService{
-        publicKey:  publicKey,
-        privateKey: privateKey,
-    }
+	encryptedToken, err := s.encrypt(ctx, token)
+	EndSpan(span, err)
+	return encryptedToken, err
 }
 
-func (s *encryptService) Encrypt(ctx context.Context, token string) (*string, error) {
-    aesKey := make([]byte, 32)
-    if _, err := io.ReadFull(rand.Reader, aesKey); err != nil {
-        return nil, fmt.Errorf("failed to generate AES key: %w", err)
-    }
-
-    block, err := aes.NewCipher(aesKey)
-    if err != nil {
-        return nil, fmt.Errorf("failed to create AES cipher: %w", err)
-    }
-
-    gcm, err := cipher.NewGCM(block)
-    if err != nil {
-        return nil, fmt.Errorf("failed to create GCM: %w", err)
-    }
-
-    nonce := make([]byte, gcm.NonceSize())
-    if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-        return nil, fmt.Errorf("failed to generate nonce: %w", err)
-    }
-
-    ciphertext := gcm.Seal(nonce, nonce, []byte(token), nil)
-
-    encryptedAESKey, err := rsa.EncryptOAEP(
-        sha256.New(),
-        rand.Reader,
-        s.publicKey,
-        aesKey,
-        nil,
-    )
-    if err != nil {
-        return nil, fmt.Errorf("failed to encrypt AES key: %w", err)
-    }
-
-    result := append(encryptedAESKey, ciphertext...)
-    encryptedToken := base64.StdEncoding.EncodeToString(result)
-    
-    return &encryptedToken, nil
+func (s *encryptService) encrypt(ctx context.Context, token string) (*string, error) {
+	key, err := s.ring.encrypter(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var encryptedToken string
+	if s.format == tokenEncryptionFormatLegacy {
+		privateKey, ok := key.privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("the legacy token envelope requires an RSA encryption key")
+		}
+		encryptedToken, err = encryptLegacy(&privateKey.PublicKey, token)
+	} else {
+		encryptedToken, err = key.encrypt(token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.ring.markUsed(key.kid, time.Now())
+	return &encryptedToken, nil
 }
 
 func (s *encryptService) Decrypt(ctx context.Context, encryptedToken string) (string, error) {
-    data, err := base64.StdEncoding.DecodeString(encryptedToken)
-    if err != nil {
-        return "", fmt.Errorf("failed to decode base64: %w", err)
-    }
-
-    rsaKeySize := s.privateKey.Size()
-    if len(data) < rsaKeySize {
-        return "", fmt.Errorf("encrypted data too short")
-    }
-
-    encryptedAESKey := data[:rsaKeySize]
-    encryptedData := data[rsaKeySize:]
-
-    aesKey, err := rsa.DecryptOAEP(
-        sha256.New(),
-        rand.Reader,
-        s.privateKey,
-        encryptedAESKey,
-        nil,
-    )
-    if err != nil {
-        return "", fmt.Errorf("failed to decrypt AES key: %w", err)
-    }
-
-    block, err := aes.NewCipher(aesKey)
-    if err != nil {
-        return "", fmt.Errorf("failed to create AES cipher: %w", err)
-    }
-
-    gcm, err := cipher.NewGCM(block)
-    if err != nil {
-        return "", fmt.Errorf("failed to create GCM: %w", err)
-    }
-
-    nonceSize := gcm.NonceSize()
-    if len(encryptedData) < nonceSize {
-        return "", fmt.Errorf("encrypted data too short for nonce")
-    }
-
-    nonce, ciphertext := encryptedData[:nonceSize], encryptedData[nonceSize:]
-    plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-    if err != nil {
-        return "", fmt.Errorf("failed to decrypt data: %w", err)
-    }
-
-    return string(plaintext), nil
-}
-
-func parsePublicKey(publicKeyPEM string) (*rsa.PublicKey, error) {
-    block, _ := pem.Decode([]byte(publicKeyPEM))
-    if block == nil {
-        return nil, fmt.Errorf("failed to parse PEM block containing public key")
-    }
-
-    pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-    if err != nil {
-        return nil, fmt.Errorf("failed to parse public key: %w", err)
-    }
-
-    rsaPublicKey, ok := pub.(*rsa.PublicKey)
-    if !ok {
-        return nil, fmt.Errorf("key is not RSA public key")
-    }
-
-    return rsaPublicKey, nil
+	defer observeSince(s.metrics.ObserveEncryption, "decrypt", time.Now())
+	ctx, span := tracer.Start(ctx, "encryptService.Decrypt")
+	span.SetAttributes(attribute.String("token.envelope", string(s.Envelope(encryptedToken))))
+
+	token, err := s.decrypt(ctx, encryptedToken)
+	EndSpan(span, err)
+	return token, err
+}
+
+func (s *encryptService) decrypt(ctx context.Context, encryptedToken string) (string, error) {
+	switch s.Envelope(encryptedToken) {
+	case services.TokenEnvelopeJWE:
+		return s.decryptJWE(ctx, encryptedToken)
+	case services.TokenEnvelopeLegacy:
+		return s.decryptLegacyToken(encryptedToken)
+	default:
+		return "", fmt.Errorf("token is not encrypted")
+	}
+}
+
+// Envelope tells the formats apart by shape: a compact JWE has five parts
+// and an "enc" header, a signed JWT three parts, and the legacy envelope is
+// a single base64 blob.
+func (s *encryptService) Envelope(token string) services.TokenEnvelope {
+	switch strings.Count(token, ".") {
+	case 0:
+		if token != "" {
+			return services.TokenEnvelopeLegacy
+		}
+	case 4:
+		if header, ok := compactHeader(token); ok && header["enc"] != nil {
+			return services.TokenEnvelopeJWE
+		}
+	}
+	return services.TokenEnvelopeNone
+}
+
+// EncryptFor encrypts token to a consuming service's registered key instead
+// of authgate's own, so only that service can read it.
+func (s *encryptService) EncryptFor(ctx context.Context, token string, key models.AudienceKey) (*string, error) {
+	defer observeSince(s.metrics.ObserveEncryption, "encrypt_for_audience", time.Now())
+	_, span := tracer.Start(ctx, "encryptService.EncryptFor")
+	span.SetAttributes(attribute.String("token.audience", key.GetAudience()))
+
+	encryptedToken, err := s.encryptFor(token, key)
+	EndSpan(span, err)
+	return encryptedToken, err
+}
+
+func (s *encryptService) encryptFor(token string, key models.AudienceKey) (*string, error) {
+	var jwk jose.JSONWebKey
+	if err := jwk.UnmarshalJSON([]byte(key.GetPublicJWK())); err != nil {
+		return nil, fmt.Errorf("failed to parse key of audience %s: %w", key.GetAudience(), err)
+	}
+
+	encryptedToken, err := encryptJWE(jose.KeyAlgorithm(key.GetAlgorithm()), jwk.Key, key.GetKeyID(), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedToken, nil
+}
+
+func (s *encryptService) ParsePublicKey(publicKey string) (*services.PublicEncryptionKey, error) {
+	jwk, err := parsePublicEncryptionJWK(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := jwk.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JWK: %w", err)
+	}
+
+	return &services.PublicEncryptionKey{
+		KeyID:     jwk.KeyID,
+		Algorithm: jwk.Algorithm,
+		JWK:       string(encoded),
+	}, nil
+}
+
+// RotateEncryptionKey creates a new active key that takes over encryption at
+// activateAt. Keys that were active until then keep decrypting for the
+// configured overlap window after the new key activates.
+func (s *encryptService) RotateEncryptionKey(ctx context.Context, algorithm string, activateAt time.Time) (models.EncryptionKey, error) {
+	if algorithm == "" {
+		algorithm = string(jose.RSA_OAEP_256)
+		if current, err := s.ring.encrypter(time.Now()); err == nil {
+			algorithm = string(current.algorithm)
+		}
+	}
+	if s.format == tokenEncryptionFormatLegacy && algorithm != string(jose.RSA_OAEP_256) {
+		return nil, fmt.Errorf("the legacy token envelope requires an %s key", jose.RSA_OAEP_256)
+	}
+
+	existing, err := s.encryptionKeyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := generateEncryptionKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey, err := s.storeEncryptionKey(ctx, key, models.EncryptionKeyStateActive, activateAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range existing {
+		if key.GetState() != models.EncryptionKeyStateActive {
+			continue
+		}
+		key.ExpireAt(activateAt.Add(s.overlap))
+		if err := s.encryptionKeyRepo.Save(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return newKey, nil
+}
+
+func (s *encryptService) ListEncryptionKeys(ctx context.Context) ([]models.EncryptionKey, error) {
+	return s.encryptionKeyRepo.List(ctx)
+}
+
+// MaintainEncryptionKeys moves keys through their lifecycle like
+// MaintainSigningKeys does for signing keys, and records when this instance
+// last used each key. It is safe to run concurrently on several instances.
+func (s *encryptService) MaintainEncryptionKeys(ctx context.Context) error {
+	usage := s.ring.takeUsage()
+
+	keys, err := s.encryptionKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		changed := false
+
+		if usedAt, ok := usage[key.GetKeyID()]; ok && key.MarkUsed(usedAt) {
+			changed = true
+		}
+
+		if key.GetState() == models.EncryptionKeyStateActive && isEncryptionKeySuperseded(key, keys, now) {
+			key.Demote()
+			key.ExpireAt(now.Add(s.overlap))
+			changed = true
+		}
+
+		if key.GetState() != models.EncryptionKeyStateRetired && !key.CanDecrypt(now) {
+			key.Retire(now, s.retention)
+			changed = true
+		}
+
+		if key.GetState() == models.EncryptionKeyStateRetired && key.GetRemoveAt() != nil && !now.Before(*key.GetRemoveAt()) {
+			if err := s.encryptionKeyRepo.Delete(ctx, key.GetKeyID()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if changed {
+			if err := s.encryptionKeyRepo.Save(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.reload(ctx)
+}
+
+func (s *encryptService) decryptJWE(ctx context.Context, encryptedToken string) (string, error) {
+	object, err := jose.ParseEncryptedCompact(
+		encryptedToken,
+		[]jose.KeyAlgorithm{jose.RSA_OAEP_256, jose.ECDH_ES},
+		[]jose.ContentEncryption{jose.A256GCM},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JWE: %w", err)
+	}
+
+	kid := object.Header.KeyID
+	now := time.Now()
+	key := s.ring.lookup(kid, now)
+	if key == nil && s.reloadIfStale(ctx) {
+		key = s.ring.lookup(kid, now)
+	}
+	if key == nil {
+		return "", fmt.Errorf("token was encrypted to unknown key %q", kid)
+	}
+
+	plaintext, err := key.decrypt(object)
+	if err != nil {
+		return "", err
+	}
+
+	s.ring.markUsed(key.kid, now)
+	return plaintext, nil
+}
+
+// decryptLegacyToken tries every RSA key that still decrypts, since the legacy
+// envelope does not say which key it was made with.
+func (s *encryptService) decryptLegacyToken(encryptedToken string) (string, error) {
+	now := time.Now()
+	keys := s.ring.rsaKeys(now)
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no RSA key can decrypt legacy encrypted tokens")
+	}
+
+	var lastErr error
+	for _, key := range keys {
+		plaintext, err := decryptLegacy(key.privateKey.(*rsa.PrivateKey), encryptedToken)
+		if err == nil {
+			s.ring.markUsed(key.kid, now)
+			return plaintext, nil
+		}
+		lastErr = err
+	}
+
+	return "", lastErr
+}
+
+func (s *encryptService) reload(ctx context.Context) error {
+	keys, err := s.encryptionKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ring.load(ctx, keys); err != nil {
+		return err
+	}
+
+	s.reloadMu.Lock()
+	s.lastReload = time.Now()
+	s.reloadMu.Unlock()
+
+	return nil
+}
+
+// reloadIfStale reloads the ring unless that happened recently, so a flood
+// of tokens with a bogus kid cannot turn into a flood of queries.
+func (s *encryptService) reloadIfStale(ctx context.Context) bool {
+	s.reloadMu.Lock()
+	stale := time.Since(s.lastReload) >= keyRingReloadInterval
+	s.reloadMu.Unlock()
+
+	if !stale {
+		return false
+	}
+
+	return s.reload(ctx) == nil
+}
+
+func (s *encryptService) seedKeyRing(ctx context.Context) error {
+	keys, err := s.encryptionKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	legacyPEM := s.seeds.LegacyPrivateKey
+	encryptionPEM := s.seeds.PrivateKey
+
+	if encryptionPEM == "" && legacyPEM == "" {
+		key, err := generateEncryptionKey(string(jose.RSA_OAEP_256))
+		if err != nil {
+			return err
+		}
+		_, err = s.storeEncryptionKey(ctx, key, models.EncryptionKeyStateActive, now)
+		return err
+	}
+
+	var legacyKey *encryptionKey
+	if legacyPEM != "" {
+		privateKey, err := parsePrivateKey(legacyPEM)
+		if err != nil {
+			return fmt.Errorf("failed to parse RSA_PRIVATE_KEY: %w", err)
+		}
+		if legacyKey, err = newEncryptionKey(privateKey); err != nil {
+			return err
+		}
+	}
+
+	if encryptionPEM == "" {
+		_, err := s.storeEncryptionKey(ctx, legacyKey, models.EncryptionKeyStateActive, now)
+		return err
+	}
+
+	privateKey, err := parseSigningPrivateKey(encryptionPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse TOKEN_ENCRYPTION_PRIVATE_KEY: %w", err)
+	}
+	key, err := newEncryptionKey(privateKey)
+	if err != nil {
+		return err
+	}
+	if _, err := s.storeEncryptionKey(ctx, key, models.EncryptionKeyStateActive, now); err != nil {
+		return err
+	}
+
+	if legacyKey != nil && legacyKey.kid != key.kid {
+		if _, err := s.storeEncryptionKey(ctx, legacyKey, models.EncryptionKeyStateDecryptOnly, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *encryptService) storeEncryptionKey(ctx context.Context, key *encryptionKey, state models.EncryptionKeyState, activateAt time.Time) (models.EncryptionKey, error) {
+	material, err := key.material()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := s.kms.Wrap(ctx, material)
+	if err != nil {
+		return nil, err
+	}
+
+	props := models.EncryptionKeyProps{
+		KeyID:           key.kid,
+		Algorithm:       string(key.algorithm),
+		WrappedMaterial: wrapped,
+		State:           state,
+		ActivatesAt:     activateAt,
+	}
+	if state == models.EncryptionKeyStateDecryptOnly {
+		expiresAt := activateAt.Add(s.overlap)
+		props.ExpiresAt = &expiresAt
+	}
+
+	model, er := models.NewEncryptionKey(props)
+	if er != nil {
+		return nil, er
+	}
+
+	if err := s.encryptionKeyRepo.Save(ctx, model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// isEncryptionKeySuperseded reports whether another active key activated
+// after key and is already encrypting.
+func isEncryptionKeySuperseded(key models.EncryptionKey, keys []models.EncryptionKey, now time.Time) bool {
+	for _, other := range keys {
+		if other.GetKeyID() == key.GetKeyID() {
+			continue
+		}
+		if other.CanEncrypt(now) && other.GetActivatesAt().After(key.GetActivatesAt()) {
+			return true
+		}
+	}
+	return false
+}
+
+func compactHeader(token string) (map[string]interface{}, bool) {
+	encoded, _, _ := strings.Cut(token, ".")
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	var header map[string]interface{}
+	if err := json.Unmarshal(decoded, &header); err != nil {
+		return nil, false
+	}
+	return header, true
+}
+
+func encryptLegacy(publicKey *rsa.PublicKey, token string) (string, error) {
+	aesKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, aesKey); err != nil {
+		return "", fmt.Errorf("failed to generate AES key: %w", err)
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(token), nil)
+
+	encryptedAESKey, err := rsa.EncryptOAEP(
+		sha256.New(),
+		rand.Reader,
+		publicKey,
+		aesKey,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt AES key: %w", err)
+	}
+
+	result := append(encryptedAESKey, ciphertext...)
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+// decryptLegacy opens the pre-JWE envelope,
+// base64(RSA-OAEP(aesKey) || nonce || ciphertext).
+func decryptLegacy(privateKey *rsa.PrivateKey, encryptedToken string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encryptedToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	rsaKeySize := privateKey.Size()
+	if len(data) < rsaKeySize {
+		return "", fmt.Errorf("encrypted data too short")
+	}
+
+	encryptedAESKey := data[:rsaKeySize]
+	encryptedData := data[rsaKeySize:]
+
+	aesKey, err := rsa.DecryptOAEP(
+		sha256.New(),
+		rand.Reader,
+		privateKey,
+		encryptedAESKey,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt AES key: %w", err)
+	}
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(encryptedData) < nonceSize {
+		return "", fmt.Errorf("encrypted data too short for nonce")
+	}
+
+	nonce, ciphertext := encryptedData[:nonceSize], encryptedData[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return string(plaintext), nil
 }
 
 func parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
-    block, _ := pem.Decode([]byte(privateKeyPEM))
-    if block == nil {
-        return nil, fmt.Errorf("failed to parse PEM block containing private key")
-    }
-
-    if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
-        if rsaKey, ok := key.(*rsa.PrivateKey); ok {
-            return rsaKey, nil
-        }
-        return nil, fmt.Errorf("PKCS#8 key is not RSA private key")
-    }
-
-    if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
-        return key, nil
-    }
-
-    return nil, fmt.Errorf("failed to parse private key: unsupported format")
-}
\ No newline at end of file
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("PKCS#8 key is not RSA private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("failed to parse private key: unsupported format")
+}
@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// encryptionKey is a key access tokens are encrypted to as JWE. RSA keys use
+// RSA-OAEP-256 and EC keys ECDH-ES; content is always A256GCM. The kid is
+// the RFC 7638 thumbprint of the public key.
+type encryptionKey struct {
+	kid        string
+	algorithm  jose.KeyAlgorithm
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+func newEncryptionKey(privateKey crypto.PrivateKey) (*encryptionKey, error) {
+	var algorithm jose.KeyAlgorithm
+	var publicKey crypto.PublicKey
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = jose.RSA_OAEP_256
+		publicKey = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		algorithm = jose.ECDH_ES
+		publicKey = &key.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported encryption key type %T", privateKey)
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: publicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute key thumbprint: %w", err)
+	}
+
+	return &encryptionKey{
+		kid:        base64.RawURLEncoding.EncodeToString(thumbprint),
+		algorithm:  algorithm,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+// newEncryptionKeyFromMaterial rebuilds a key from the PKCS#8 DER kept in
+// the key ring and checks that it fits algorithm.
+func newEncryptionKeyFromMaterial(algorithm string, material []byte) (*encryptionKey, error) {
+	privateKey, err := x509.ParsePKCS8PrivateKey(material)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	key, err := newEncryptionKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if string(key.algorithm) != algorithm {
+		return nil, fmt.Errorf("%s key cannot be used with %s", key.algorithm, algorithm)
+	}
+
+	return key, nil
+}
+
+// generateEncryptionKey creates a key with fresh material for algorithm.
+func generateEncryptionKey(algorithm string) (*encryptionKey, error) {
+	var privateKey crypto.PrivateKey
+	var err error
+
+	switch jose.KeyAlgorithm(algorithm) {
+	case jose.RSA_OAEP_256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 3072)
+	case jose.ECDH_ES:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", algorithm, err)
+	}
+
+	return newEncryptionKey(privateKey)
+}
+
+func (k *encryptionKey) material() ([]byte, error) {
+	material, err := x509.MarshalPKCS8PrivateKey(k.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return material, nil
+}
+
+func (k *encryptionKey) encrypt(plaintext string) (string, error) {
+	return encryptJWE(k.algorithm, k.publicKey, k.kid, plaintext)
+}
+
+func (k *encryptionKey) decrypt(object *jose.JSONWebEncryption) (string, error) {
+	plaintext, err := object.Decrypt(k.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// encryptJWE serializes plaintext, a signed JWT, as a compact JWE for the
+// given recipient key.
+func encryptJWE(algorithm jose.KeyAlgorithm, publicKey crypto.PublicKey, kid, plaintext string) (string, error) {
+	encrypter, err := jose.NewEncrypter(
+		jose.A256GCM,
+		jose.Recipient{Algorithm: algorithm, Key: publicKey, KeyID: kid},
+		(&jose.EncrypterOptions{}).WithContentType("JWT"),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create JWE encrypter: %w", err)
+	}
+
+	object, err := encrypter.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt token: %w", err)
+	}
+
+	return object.CompactSerialize()
+}
+
+// parsePublicEncryptionJWK reads a consumer's public key given either as a
+// JWK or as a PEM encoded SubjectPublicKeyInfo. The result carries use, alg
+// and kid; a kid chosen by the consumer is kept, otherwise the RFC 7638
+// thumbprint is used.
+func parsePublicEncryptionJWK(publicKey string) (*jose.JSONWebKey, error) {
+	trimmed := strings.TrimSpace(publicKey)
+
+	var jwk jose.JSONWebKey
+	if strings.HasPrefix(trimmed, "{") {
+		if err := jwk.UnmarshalJSON([]byte(trimmed)); err != nil {
+			return nil, fmt.Errorf("failed to parse JWK: %w", err)
+		}
+		if !jwk.IsPublic() {
+			return nil, fmt.Errorf("JWK must be a public key")
+		}
+	} else {
+		block, _ := pem.Decode([]byte(trimmed))
+		if block == nil {
+			return nil, fmt.Errorf("public key must be a JWK or a PEM block")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		jwk = jose.JSONWebKey{Key: key}
+	}
+
+	var algorithm jose.KeyAlgorithm
+	switch key := jwk.Key.(type) {
+	case *rsa.PublicKey:
+		if key.Size()*8 < 2048 {
+			return nil, fmt.Errorf("RSA encryption keys must be at least 2048 bits")
+		}
+		algorithm = jose.RSA_OAEP_256
+	case *ecdsa.PublicKey:
+		algorithm = jose.ECDH_ES
+	default:
+		return nil, fmt.Errorf("unsupported encryption key type %T", jwk.Key)
+	}
+
+	if jwk.Algorithm != "" && jwk.Algorithm != string(algorithm) {
+		return nil, fmt.Errorf("unsupported key algorithm %s, expected %s", jwk.Algorithm, algorithm)
+	}
+	if jwk.Use != "" && jwk.Use != "enc" {
+		return nil, fmt.Errorf("key use must be enc")
+	}
+
+	if jwk.KeyID == "" {
+		thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute key thumbprint: %w", err)
+		}
+		jwk.KeyID = base64.RawURLEncoding.EncodeToString(thumbprint)
+	}
+	jwk.Algorithm = string(algorithm)
+	jwk.Use = "enc"
+
+	return &jwk, nil
+}
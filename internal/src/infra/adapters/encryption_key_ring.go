@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+type encryptionRingEntry struct {
+	model models.EncryptionKey
+	key   *encryptionKey
+}
+
+// encryptionKeyRing holds the usable token encryption keys, decoded from the
+// encryption_keys table, and remembers when each was last used until the
+// maintenance job records it.
+type encryptionKeyRing struct {
+	kms services.IKeyManagementService
+
+	mu      sync.RWMutex
+	entries map[string]encryptionRingEntry
+
+	usageMu sync.Mutex
+	usage   map[string]time.Time
+}
+
+func newEncryptionKeyRing(kms services.IKeyManagementService) *encryptionKeyRing {
+	return &encryptionKeyRing{
+		kms:     kms,
+		entries: map[string]encryptionRingEntry{},
+		usage:   map[string]time.Time{},
+	}
+}
+
+// load replaces the ring with the non-retired keys found in models.
+// Material already decoded for a key ID is reused instead of unwrapped again.
+func (r *encryptionKeyRing) load(ctx context.Context, keys []models.EncryptionKey) error {
+	r.mu.RLock()
+	previous := r.entries
+	r.mu.RUnlock()
+
+	entries := make(map[string]encryptionRingEntry, len(keys))
+	for _, model := range keys {
+		if model.GetState() == models.EncryptionKeyStateRetired {
+			continue
+		}
+
+		if existing, ok := previous[model.GetKeyID()]; ok {
+			entries[model.GetKeyID()] = encryptionRingEntry{model: model, key: existing.key}
+			continue
+		}
+
+		material, err := r.kms.Unwrap(ctx, model.GetWrappedMaterial())
+		if err != nil {
+			return fmt.Errorf("failed to unwrap encryption key %s: %w", model.GetKeyID(), err)
+		}
+
+		key, err := newEncryptionKeyFromMaterial(model.GetAlgorithm(), material)
+		if err != nil {
+			return fmt.Errorf("failed to load encryption key %s: %w", model.GetKeyID(), err)
+		}
+
+		entries[model.GetKeyID()] = encryptionRingEntry{model: model, key: key}
+	}
+
+	r.mu.Lock()
+	r.entries = entries
+	r.mu.Unlock()
+
+	return nil
+}
+
+// encrypter returns the most recently activated key that may encrypt at now.
+func (r *encryptionKeyRing) encrypter(now time.Time) (*encryptionKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var current *encryptionRingEntry
+	for _, entry := range r.entries {
+		if !entry.model.CanEncrypt(now) {
+			continue
+		}
+		if current == nil || entry.model.GetActivatesAt().After(current.model.GetActivatesAt()) {
+			e := entry
+			current = &e
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("no active token encryption key")
+	}
+
+	return current.key, nil
+}
+
+func (r *encryptionKeyRing) lookup(kid string, now time.Time) *encryptionKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[kid]
+	if !ok || !entry.model.CanDecrypt(now) {
+		return nil
+	}
+
+	return entry.key
+}
+
+// rsaKeys returns the RSA keys that still decrypt at now. Tokens in the
+// legacy envelope carry no key ID and are tried against each of them.
+func (r *encryptionKeyRing) rsaKeys(now time.Time) []*encryptionKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []*encryptionKey
+	for _, entry := range r.entries {
+		if _, ok := entry.key.privateKey.(*rsa.PrivateKey); ok && entry.model.CanDecrypt(now) {
+			keys = append(keys, entry.key)
+		}
+	}
+
+	return keys
+}
+
+func (r *encryptionKeyRing) markUsed(kid string, at time.Time) {
+	r.usageMu.Lock()
+	r.usage[kid] = at
+	r.usageMu.Unlock()
+}
+
+// takeUsage returns the uses seen since the previous call and resets them.
+func (r *encryptionKeyRing) takeUsage() map[string]time.Time {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+
+	usage := r.usage
+	r.usage = map[string]time.Time{}
+	return usage
+}
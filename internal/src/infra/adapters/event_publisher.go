@@ -0,0 +1,168 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const (
+	memoryEventPublisherCapacity = 1000
+	webhookPublisherTimeout      = 10 * time.Second
+)
+
+// NewEventPublisher picks the configured publisher:
+//   - memory (default) keeps the latest events in process, for development;
+//   - file appends one JSON event per line to the configured file;
+//   - webhook POSTs each event to the configured URL, with the configured
+//     token as bearer token when set.
+func NewEventPublisher(cfg config.EventsConfig, logger *slog.Logger) services.IEventPublisher {
+	switch kind := cfg.Publisher; kind {
+	case "", "memory":
+		logger.Warn("Domain events are kept in memory; set EVENT_PUBLISHER to deliver them")
+		return newMemoryEventPublisher(memoryEventPublisherCapacity)
+	case "file":
+		publisher, err := newFileEventPublisher(cfg.File)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid file event publisher: %v", err))
+		}
+		return publisher
+	case "webhook":
+		publisher, err := newWebhookEventPublisher(cfg.WebhookURL, cfg.WebhookToken)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid webhook event publisher: %v", err))
+		}
+		return publisher
+	default:
+		panic(fmt.Sprintf("EVENT_PUBLISHER must be memory, file or webhook, got %q", kind))
+	}
+}
+
+// memoryEventPublisher keeps the last capacity events.
+type memoryEventPublisher struct {
+	mu       sync.Mutex
+	events   []models.DomainEvent
+	capacity int
+}
+
+func newMemoryEventPublisher(capacity int) *memoryEventPublisher {
+	return &memoryEventPublisher{capacity: capacity}
+}
+
+func (p *memoryEventPublisher) Publish(ctx context.Context, event models.DomainEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	if len(p.events) > p.capacity {
+		p.events = p.events[len(p.events)-p.capacity:]
+	}
+
+	return nil
+}
+
+// Events returns the retained events, oldest first.
+func (p *memoryEventPublisher) Events() []models.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]models.DomainEvent(nil), p.events...)
+}
+
+// fileEventPublisher appends events as JSON lines and syncs after each one,
+// so an accepted event survives a crash.
+type fileEventPublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileEventPublisher(path string) (*fileEventPublisher, error) {
+	if path == "" {
+		return nil, fmt.Errorf("EVENT_PUBLISHER_FILE is required")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileEventPublisher{file: file}, nil
+}
+
+func (p *fileEventPublisher) Publish(ctx context.Context, event models.DomainEvent) error {
+	line, err := models.EncodeDomainEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+
+	return p.file.Sync()
+}
+
+// webhookEventPublisher POSTs each event as JSON. Any 2xx response accepts
+// it; the X-Event-Id header lets receivers drop redeliveries.
+type webhookEventPublisher struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newWebhookEventPublisher(url, token string) (*webhookEventPublisher, error) {
+	if url == "" {
+		return nil, fmt.Errorf("EVENT_PUBLISHER_WEBHOOK_URL is required")
+	}
+
+	return &webhookEventPublisher{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: webhookPublisherTimeout},
+	}, nil
+}
+
+func (p *webhookEventPublisher) Publish(ctx context.Context, event models.DomainEvent) error {
+	body, err := models.EncodeDomainEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.GetID())
+	req.Header.Set("X-Event-Type", event.GetType())
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const (
+	encryptedFieldPrefix = "enc:v1:"
+	wrappedDataKeyPrefix = "kms:"
+)
+
+// fieldEncryptionService encrypts columns holding personal data with
+// AES-256-GCM under a data key, and derives blind indexes with HMAC-SHA256
+// under a separate index key.
+type fieldEncryptionService struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// NewFieldEncryptionService takes the data key from the configuration: 32
+// bytes encoded as base64, or "kms:" followed by a key wrapped with the key
+// management service so the plaintext key never sits in configuration. The
+// blind index key is in the same format; when it is not set one is derived
+// from the data key.
+func NewFieldEncryptionService(cfg config.PIIConfig, kms services.IKeyManagementService) services.IFieldEncryptionService {
+	dataKey, err := loadDataKey(kms, cfg.DataKey)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid PII_DATA_KEY: %v", err))
+	}
+
+	var indexKey []byte
+	if encoded := cfg.IndexKey; encoded != "" {
+		indexKey, err = loadDataKey(kms, encoded)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid PII_INDEX_KEY: %v", err))
+		}
+	} else {
+		mac := hmac.New(sha256.New, dataKey)
+		mac.Write([]byte("authgate blind index key"))
+		indexKey = mac.Sum(nil)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create PII cipher: %v", err))
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create PII GCM: %v", err))
+	}
+
+	return &fieldEncryptionService{
+		aead:     aead,
+		indexKey: indexKey,
+	}
+}
+
+func loadDataKey(kms services.IKeyManagementService, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("must be set")
+	}
+
+	wrapped := strings.HasPrefix(value, wrappedDataKeyPrefix)
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, wrappedDataKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("must be base64 encoded")
+	}
+
+	if wrapped {
+		key, err = kms.Unwrap(context.Background(), key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("must be 32 bytes")
+	}
+
+	return key, nil
+}
+
+func (s *fieldEncryptionService) EncryptField(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), []byte(encryptedFieldPrefix))
+	return encryptedFieldPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptField opens a value produced by EncryptField. Values without the
+// encrypted prefix were written before field encryption and are returned as
+// they are, so rows keep working until they are migrated.
+func (s *fieldEncryptionService) DecryptField(ctx context.Context, value string) (string, error) {
+	if !s.IsEncrypted(value) {
+		return value, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedFieldPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted field: %w", err)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", fmt.Errorf("encrypted field too short")
+	}
+
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(encryptedFieldPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt field: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func (s *fieldEncryptionService) IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedFieldPrefix)
+}
+
+func (s *fieldEncryptionService) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, s.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
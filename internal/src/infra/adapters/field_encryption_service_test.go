@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+func testDataKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, 32))
+}
+
+func TestFieldEncryptionRoundTrip(t *testing.T) {
+	service := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1)}, newTestKMS())
+	ctx := context.Background()
+
+	first, err := service.EncryptField(ctx, "user@example.com")
+	if err != nil {
+		t.Fatalf("EncryptField() error = %v", err)
+	}
+	second, err := service.EncryptField(ctx, "user@example.com")
+	if err != nil {
+		t.Fatalf("EncryptField() error = %v", err)
+	}
+
+	if !service.IsEncrypted(first) || strings.Contains(first, "user@example.com") {
+		t.Errorf("EncryptField() = %q, want an encrypted value", first)
+	}
+	if first == second {
+		t.Error("EncryptField() is deterministic, want a fresh nonce per value")
+	}
+
+	for _, encrypted := range []string{first, second} {
+		if plaintext, err := service.DecryptField(ctx, encrypted); err != nil || plaintext != "user@example.com" {
+			t.Errorf("DecryptField() = %q, %v, want user@example.com", plaintext, err)
+		}
+	}
+}
+
+func TestDecryptFieldPassesUnmigratedValuesThrough(t *testing.T) {
+	service := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1)}, newTestKMS())
+
+	for _, value := range []string{"", "user@example.com"} {
+		if plaintext, err := service.DecryptField(context.Background(), value); err != nil || plaintext != value {
+			t.Errorf("DecryptField(%q) = %q, %v, want it unchanged", value, plaintext, err)
+		}
+	}
+	if encrypted, err := service.EncryptField(context.Background(), ""); err != nil || encrypted != "" {
+		t.Errorf("EncryptField(\"\") = %q, %v, want an empty value", encrypted, err)
+	}
+}
+
+func TestDecryptFieldRejectsTamperedValues(t *testing.T) {
+	service := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1)}, newTestKMS())
+	other := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(2)}, newTestKMS())
+
+	encrypted, err := service.EncryptField(context.Background(), "user@example.com")
+	if err != nil {
+		t.Fatalf("EncryptField() error = %v", err)
+	}
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedFieldPrefix))
+	sealed[len(sealed)-1] ^= 1
+	tampered := encryptedFieldPrefix + base64.StdEncoding.EncodeToString(sealed)
+
+	tests := map[string]struct {
+		service services.IFieldEncryptionService
+		value   string
+	}{
+		"tampered":   {service: service, value: tampered},
+		"other key":  {service: other, value: encrypted},
+		"not base64": {service: service, value: encryptedFieldPrefix + "!"},
+		"too short":  {service: service, value: encryptedFieldPrefix + "AAAA"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if plaintext, err := tt.service.DecryptField(context.Background(), tt.value); err == nil {
+				t.Errorf("DecryptField() = %q, want an error", plaintext)
+			}
+		})
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	service := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1)}, newTestKMS())
+	otherIndexKey := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1), IndexKey: testDataKey(3)}, newTestKMS())
+
+	index := service.BlindIndex("1:user@example.com")
+	if index != service.BlindIndex("1:user@example.com") {
+		t.Error("BlindIndex() is not deterministic")
+	}
+	if index == service.BlindIndex("1:other@example.com") {
+		t.Error("BlindIndex() is the same for different values")
+	}
+	if index == otherIndexKey.BlindIndex("1:user@example.com") {
+		t.Error("BlindIndex() does not depend on the index key")
+	}
+	if strings.Contains(index, "user@example.com") {
+		t.Errorf("BlindIndex() = %q, want no plaintext", index)
+	}
+}
+
+func TestFieldEncryptionWithWrappedDataKey(t *testing.T) {
+	kms := newTestKMS()
+	wrapped, err := kms.Wrap(context.Background(), bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	service := NewFieldEncryptionService(config.PIIConfig{DataKey: wrappedDataKeyPrefix + base64.StdEncoding.EncodeToString(wrapped)}, kms)
+	plain := NewFieldEncryptionService(config.PIIConfig{DataKey: testDataKey(1)}, kms)
+
+	encrypted, err := service.EncryptField(context.Background(), "Ada")
+	if err != nil {
+		t.Fatalf("EncryptField() error = %v", err)
+	}
+	// The wrapped key is the same key, so either service reads the value.
+	if plaintext, err := plain.DecryptField(context.Background(), encrypted); err != nil || plaintext != "Ada" {
+		t.Errorf("DecryptField() = %q, %v, want Ada", plaintext, err)
+	}
+}
+
+func TestNewFieldEncryptionServiceRejectsBadKeys(t *testing.T) {
+	tests := map[string]config.PIIConfig{
+		"no data key":       {},
+		"not base64":        {DataKey: "not base64!"},
+		"short data key":    {DataKey: base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		"bad index key":     {DataKey: testDataKey(1), IndexKey: "short"},
+		"not a wrapped key": {DataKey: wrappedDataKeyPrefix + testDataKey(1)},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("NewFieldEncryptionService() accepted the configuration")
+				}
+			}()
+			NewFieldEncryptionService(cfg, newTestKMS())
+		})
+	}
+}
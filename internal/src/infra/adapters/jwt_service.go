@@ -4,102 +4,615 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const keyRingReloadInterval = 5 * time.Second
+
 type jwtService struct {
-    secretKey        []byte
-    refreshSecretKey []byte
+	signingKeyRepo repositories.ISigningKeyRepository
+	kms            services.IKeyManagementService
+	accessRing     *signingKeyRing
+	refreshRing    *signingKeyRing
+	overlap        time.Duration
+	retention      time.Duration
+	issuer         string
+	claimMapping   map[string]string
+	clockSkew      time.Duration
+	seeds          config.JWTConfig
+
+	reloadMu   sync.Mutex
+	lastReload time.Time
 }
 
-func NewJWTService() services.IJWTService {
-    return &jwtService{
-        secretKey:        []byte(os.Getenv("JWT_SECRET_KEY")),
-        refreshSecretKey: []byte(os.Getenv("JWT_REFRESH_SECRET_KEY")),
-    }
+// NewJWTService builds the service on top of the signing key ring persisted
+// in the database. The configuration only seeds an empty ring: the signing
+// algorithm is HS256 (the default, keyed by the secret key) or one of RS256,
+// ES256 and EdDSA keyed by the signing private key, and the refresh secret
+// key seeds the refresh ring. When an asymmetric algorithm is configured and
+// the secret key is still set, the HMAC secret is imported as a verify-only
+// key so tokens issued before the switch remain valid for the overlap
+// window. Missing seeds are generated.
+//
+// The metadata claims list the user metadata keys copied into access tokens
+// and the clock skew is the tolerance applied to exp, nbf and iat.
+func NewJWTService(cfg config.JWTConfig, signingKeyRepo repositories.ISigningKeyRepository, kms services.IKeyManagementService) services.IJWTService {
+	claimMapping, err := parseClaimMapping(cfg.MetadataClaims)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid JWT_METADATA_CLAIMS: %v", err))
+	}
+
+	service := &jwtService{
+		signingKeyRepo: signingKeyRepo,
+		kms:            kms,
+		accessRing:     newSigningKeyRing(models.SigningKeyPurposeAccess, kms),
+		refreshRing:    newSigningKeyRing(models.SigningKeyPurposeRefresh, kms),
+		overlap:        cfg.KeyOverlap,
+		retention:      cfg.RetiredKeyRetention,
+		issuer:         cfg.Issuer,
+		claimMapping:   claimMapping,
+		clockSkew:      cfg.ClockSkew,
+		seeds:          cfg,
+	}
+
+	ctx := context.Background()
+	if err := service.seedKeyRing(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to seed JWT signing keys: %v", err))
+	}
+	if err := service.reload(ctx); err != nil {
+		panic(fmt.Sprintf("Failed to load JWT signing keys: %v", err))
+	}
+
+	return service
 }
 
-func (s *jwtService) GenerateToken(ctx context.Context, userID string, roles []string, exp int) (*string, error) {
-    claims := jwt.MapClaims{
-        "sub":   userID,
-        "roles": strings.Join(roles, ","),
-        "type":  "access",
-        "iat":   time.Now().Unix(),
-        "exp":   time.Now().Add(time.Second * time.Duration(exp)).Unix(),
-    }
+func (s *jwtService) GetIssuer() string {
+	return s.issuer
+}
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    tokenString, err := token.SignedString(s.secretKey)
-    if err != nil {
-        return nil, fmt.Errorf("error creating access token: %w", err)
-    }
+func (s *jwtService) GetSigningAlgorithm() string {
+	key, err := s.accessRing.signer(time.Now())
+	if err != nil {
+		return ""
+	}
+	return key.method.Alg()
+}
 
-    return &tokenString, nil
+// GetPublicKeys returns the JWKS of every asymmetric access key tokens may
+// currently be verified with, including keys published ahead of their
+// activation. HMAC secrets are never published.
+func (s *jwtService) GetPublicKeys(ctx context.Context) ([]services.JSONWebKey, error) {
+	keys := []services.JSONWebKey{}
+	for _, key := range s.accessRing.verifiableKeys(time.Now()) {
+		if key.isSymmetric() {
+			continue
+		}
+
+		jwk, err := key.publicJWK()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *jwk)
+	}
+
+	return keys, nil
 }
 
-func (s *jwtService) GenerateRefreshToken(ctx context.Context, userID string, exp int) (*string, error) {
-    claims := jwt.MapClaims{
-        "sub":  userID,
-        "type": "refresh",
-        "iat":  time.Now().Unix(),
-        "exp":  time.Now().Add(time.Second * time.Duration(exp)).Unix(),
-    }
+// RotateSigningKey creates a new active key for purpose that takes over
+// signing at activateAt. Keys that were active until then keep verifying for
+// the configured overlap window after the new key activates.
+func (s *jwtService) RotateSigningKey(ctx context.Context, purpose models.SigningKeyPurpose, algorithm string, activateAt time.Time) (models.SigningKey, error) {
+	if purpose == models.SigningKeyPurposeRefresh && algorithm != "" && algorithm != "HS256" {
+		return nil, fmt.Errorf("refresh tokens are only read by authgate and are always signed with HS256")
+	}
+	if algorithm == "" {
+		algorithm = "HS256"
+		if purpose == models.SigningKeyPurposeAccess {
+			algorithm = s.GetSigningAlgorithm()
+		}
+	}
+
+	existing, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    tokenString, err := token.SignedString(s.refreshSecretKey)
-    if err != nil {
-        return nil, fmt.Errorf("error creating refresh token: %w", err)
-    }
+	newKey, err := s.createSigningKey(ctx, purpose, algorithm, models.SigningKeyStateActive, activateAt)
+	if err != nil {
+		return nil, err
+	}
 
-    return &tokenString, nil
+	for _, key := range existing {
+		if key.GetPurpose() != purpose || key.GetState() != models.SigningKeyStateActive {
+			continue
+		}
+		key.ExpireAt(activateAt.Add(s.overlap))
+		if err := s.signingKeyRepo.Save(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return newKey, nil
 }
 
-func (s *jwtService) ExtractClaims(ctx context.Context, token string) (map[string]interface{}, error) {
-    parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-        }
-        return s.secretKey, nil
-    })
+func (s *jwtService) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	return s.signingKeyRepo.List(ctx)
+}
 
-    if err != nil {
-        if errors.Is(err, jwt.ErrTokenExpired) {
-            return nil, fmt.Errorf("token expired")
-        }
+// MaintainSigningKeys moves keys through their lifecycle: superseded active
+// keys become verify-only, expired keys are retired and scheduled for
+// removal, and retired keys past their removal time are deleted. It is safe
+// to run concurrently on several instances.
+func (s *jwtService) MaintainSigningKeys(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
 
-        return nil, fmt.Errorf("error parsing token: %w", err)
-    }
+	now := time.Now()
+	for _, key := range keys {
+		if key.GetPurpose() == models.SigningKeyPurposeAudit {
+			continue
+		}
 
-    if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
-        return claims, nil
-    }
+		changed := false
 
-    return nil, fmt.Errorf("invalid token")
+		if key.GetState() == models.SigningKeyStateActive && isSuperseded(key, keys, now) {
+			key.Demote()
+			key.ExpireAt(now.Add(s.overlap))
+			changed = true
+		}
+
+		if key.GetState() != models.SigningKeyStateRetired && !key.CanVerify(now) {
+			key.Retire(now, s.retention)
+			changed = true
+		}
+
+		if key.GetState() == models.SigningKeyStateRetired && key.GetRemoveAt() != nil && !now.Before(*key.GetRemoveAt()) {
+			if err := s.signingKeyRepo.Delete(ctx, key.GetKeyID()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if changed {
+			if err := s.signingKeyRepo.Save(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.reload(ctx)
+}
+
+// CheckKeys fails when access or refresh tokens cannot be signed right now.
+func (s *jwtService) CheckKeys(ctx context.Context) error {
+	now := time.Now()
+	if _, err := s.accessRing.signer(now); err != nil {
+		return fmt.Errorf("access token signing key: %w", err)
+	}
+	if _, err := s.refreshRing.signer(now); err != nil {
+		return fmt.Errorf("refresh token signing key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *jwtService) GenerateToken(ctx context.Context, accessClaims services.AccessTokenClaims) (*string, error) {
+	ctx, span := tracer.Start(ctx, "jwtService.GenerateToken")
+	token, err := s.generateToken(ctx, accessClaims)
+	EndSpan(span, err)
+	return token, err
+}
+
+func (s *jwtService) GenerateRefreshToken(ctx context.Context, refreshClaims services.RefreshTokenClaims) (*string, error) {
+	ctx, span := tracer.Start(ctx, "jwtService.GenerateRefreshToken")
+	token, err := s.generateRefreshToken(ctx, refreshClaims)
+	EndSpan(span, err)
+	return token, err
+}
+
+func (s *jwtService) GenerateIDToken(ctx context.Context, idClaims map[string]interface{}, exp int) (*string, error) {
+	ctx, span := tracer.Start(ctx, "jwtService.GenerateIDToken")
+	token, err := s.generateIDToken(ctx, idClaims, exp)
+	EndSpan(span, err)
+	return token, err
+}
+
+func (s *jwtService) ExtractClaims(ctx context.Context, token string, expectations services.TokenExpectations) (map[string]interface{}, error) {
+	ctx, span := tracer.Start(ctx, "jwtService.ExtractClaims")
+	claims, err := s.extractClaims(ctx, token, expectations)
+	EndSpan(span, err)
+	return claims, err
 }
 
 func (s *jwtService) ExtractRefreshClaims(ctx context.Context, token string) (map[string]interface{}, error) {
-    parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-        }
-        return s.refreshSecretKey, nil
-    })
-
-    if err != nil {
-        return nil, fmt.Errorf("error parsing refresh token: %w", err)
-    }
-
-    if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
-        if tokenType, exists := claims["type"]; !exists || tokenType != "refresh" {
-            return nil, fmt.Errorf("invalid token type")
-        }
-        return claims, nil
-    }
-
-    return nil, fmt.Errorf("invalid refresh token")
-}
\ No newline at end of file
+	ctx, span := tracer.Start(ctx, "jwtService.ExtractRefreshClaims")
+	claims, err := s.extractRefreshClaims(ctx, token)
+	EndSpan(span, err)
+	return claims, err
+}
+
+func (s *jwtService) generateToken(ctx context.Context, accessClaims services.AccessTokenClaims) (*string, error) {
+	roles := accessClaims.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.issuer,
+		"sub":   accessClaims.UserID,
+		"roles": roles,
+		"type":  "access",
+		"jti":   utils.GenerateUUID(),
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   now.Add(time.Second * time.Duration(accessClaims.ExpiresIn)).Unix(),
+	}
+	if accessClaims.Scope != "" {
+		claims["scope"] = accessClaims.Scope
+	}
+	if accessClaims.ClientID != "" {
+		claims["client_id"] = accessClaims.ClientID
+	}
+	if accessClaims.SessionID != "" {
+		claims["sid"] = accessClaims.SessionID
+	}
+	if len(accessClaims.Audience) > 0 {
+		claims["aud"] = accessClaims.Audience
+	}
+	if len(accessClaims.Actor) > 0 {
+		claims["act"] = accessClaims.Actor
+	}
+	for key, claim := range s.claimMapping {
+		if value, ok := accessClaims.Metadata[key]; ok {
+			claims[claim] = value
+		}
+	}
+
+	tokenString, err := s.sign(s.accessRing, claims)
+	if err != nil {
+		return nil, fmt.Errorf("error creating access token: %w", err)
+	}
+
+	return &tokenString, nil
+}
+
+func (s *jwtService) generateRefreshToken(ctx context.Context, refreshClaims services.RefreshTokenClaims) (*string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":  s.issuer,
+		"sub":  refreshClaims.UserID,
+		"type": "refresh",
+		"jti":  utils.GenerateUUID(),
+		"iat":  now.Unix(),
+		"nbf":  now.Unix(),
+		"exp":  now.Add(time.Second * time.Duration(refreshClaims.ExpiresIn)).Unix(),
+	}
+	if refreshClaims.Scope != "" {
+		claims["scope"] = refreshClaims.Scope
+	}
+	if refreshClaims.ClientID != "" {
+		claims["client_id"] = refreshClaims.ClientID
+	}
+	if refreshClaims.SessionID != "" {
+		claims["sid"] = refreshClaims.SessionID
+	}
+	// Refresh tokens are only read by authgate; their aud records the
+	// audiences the access tokens minted from them may be issued for.
+	if len(refreshClaims.Audience) > 0 {
+		claims["aud"] = refreshClaims.Audience
+	}
+
+	tokenString, err := s.sign(s.refreshRing, claims)
+	if err != nil {
+		return nil, fmt.Errorf("error creating refresh token: %w", err)
+	}
+
+	return &tokenString, nil
+}
+
+// GenerateIDToken signs an OpenID Connect ID token. The caller supplies the
+// user and client specific claims; iss, iat and exp are set here.
+func (s *jwtService) generateIDToken(ctx context.Context, idClaims map[string]interface{}, exp int) (*string, error) {
+	claims := jwt.MapClaims{}
+	for key, value := range idClaims {
+		claims[key] = value
+	}
+	claims["type"] = "id"
+	claims["iss"] = s.issuer
+	claims["jti"] = utils.GenerateUUID()
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Second * time.Duration(exp)).Unix()
+
+	tokenString, err := s.sign(s.accessRing, claims)
+	if err != nil {
+		return nil, fmt.Errorf("error creating id token: %w", err)
+	}
+
+	return &tokenString, nil
+}
+
+// ExtractClaims verifies an access token and returns its claims. exp, nbf
+// and iat are checked with the configured clock skew tolerance; audience
+// and issuer only when the caller expects them.
+func (s *jwtService) extractClaims(ctx context.Context, token string, expectations services.TokenExpectations) (map[string]interface{}, error) {
+	options := []jwt.ParserOption{jwt.WithLeeway(s.clockSkew), jwt.WithIssuedAt()}
+	if expectations.Audience != "" {
+		options = append(options, jwt.WithAudience(expectations.Audience))
+	}
+	if expectations.Issuer != "" {
+		options = append(options, jwt.WithIssuer(expectations.Issuer))
+	}
+
+	parsedToken, err := jwt.Parse(token, s.keyFunc(s.accessRing), options...)
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("token expired")
+		}
+
+		return nil, fmt.Errorf("error parsing token: %w", err)
+	}
+
+	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
+		// ID tokens share the signing key, so the type claim is what keeps
+		// them from being replayed as access tokens.
+		if tokenType, exists := claims["type"]; !exists || tokenType != "access" {
+			return nil, fmt.Errorf("invalid token type")
+		}
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+func (s *jwtService) extractRefreshClaims(ctx context.Context, token string) (map[string]interface{}, error) {
+	parsedToken, err := jwt.Parse(token, s.keyFunc(s.refreshRing), jwt.WithLeeway(s.clockSkew))
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing refresh token: %w", err)
+	}
+
+	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
+		if tokenType, exists := claims["type"]; !exists || tokenType != "refresh" {
+			return nil, fmt.Errorf("invalid token type")
+		}
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid refresh token")
+}
+
+func (s *jwtService) sign(ring *signingKeyRing, claims jwt.MapClaims) (string, error) {
+	key, err := ring.signer(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.signKey)
+}
+
+// keyFunc resolves the key for a token from its kid header and refuses any
+// token whose alg does not match that key, which rules out algorithm
+// confusion between HMAC and public keys. An unknown kid triggers a reload so
+// keys rotated by another instance are picked up. Tokens issued before key
+// IDs were introduced carry no kid and are checked against the HMAC keys.
+func (s *jwtService) keyFunc(ring *signingKeyRing) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		now := time.Now()
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			keySet := jwt.VerificationKeySet{}
+			for _, key := range ring.symmetricKeys(now) {
+				if token.Method.Alg() == key.method.Alg() {
+					keySet.Keys = append(keySet.Keys, key.verifyKey)
+				}
+			}
+			if len(keySet.Keys) == 0 {
+				return nil, fmt.Errorf("token has no key ID")
+			}
+			return keySet, nil
+		}
+
+		key := ring.lookup(kid, now)
+		if key == nil && s.reloadIfStale(context.Background()) {
+			key = ring.lookup(kid, now)
+		}
+		if key == nil {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return key.verifyKey, nil
+	}
+}
+
+func (s *jwtService) reload(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accessRing.load(ctx, keys); err != nil {
+		return err
+	}
+	if err := s.refreshRing.load(ctx, keys); err != nil {
+		return err
+	}
+
+	s.reloadMu.Lock()
+	s.lastReload = time.Now()
+	s.reloadMu.Unlock()
+
+	return nil
+}
+
+// reloadIfStale reloads the rings unless that happened recently, so a flood
+// of tokens with a bogus kid cannot turn into a flood of queries.
+func (s *jwtService) reloadIfStale(ctx context.Context) bool {
+	s.reloadMu.Lock()
+	stale := time.Since(s.lastReload) >= keyRingReloadInterval
+	s.reloadMu.Unlock()
+
+	if !stale {
+		return false
+	}
+
+	return s.reload(ctx) == nil
+}
+
+func (s *jwtService) seedKeyRing(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	hasAccess, hasRefresh := false, false
+	for _, key := range keys {
+		switch key.GetPurpose() {
+		case models.SigningKeyPurposeAccess:
+			hasAccess = true
+		case models.SigningKeyPurposeRefresh:
+			hasRefresh = true
+		}
+	}
+
+	now := time.Now()
+
+	if !hasAccess {
+		algorithm := s.seeds.SigningAlgorithm
+		if algorithm == "" {
+			algorithm = "HS256"
+		}
+
+		secret := s.seeds.SecretKey
+		if algorithm == "HS256" {
+			if err := s.importOrCreateKey(ctx, models.SigningKeyPurposeAccess, algorithm, secret, models.SigningKeyStateActive, now); err != nil {
+				return err
+			}
+		} else {
+			privateKeyPEM := s.seeds.SigningPrivateKey
+			if err := s.importOrCreateKey(ctx, models.SigningKeyPurposeAccess, algorithm, privateKeyPEM, models.SigningKeyStateActive, now); err != nil {
+				return err
+			}
+			if secret != "" {
+				if err := s.importOrCreateKey(ctx, models.SigningKeyPurposeAccess, "HS256", secret, models.SigningKeyStateVerifyOnly, now); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if !hasRefresh {
+		secret := s.seeds.RefreshSecretKey
+		if err := s.importOrCreateKey(ctx, models.SigningKeyPurposeRefresh, "HS256", secret, models.SigningKeyStateActive, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importOrCreateKey stores seed as a key of the given purpose, generating new
+// material when seed is empty. seed is an HMAC secret for HS256 and a PEM
+// private key otherwise.
+func (s *jwtService) importOrCreateKey(ctx context.Context, purpose models.SigningKeyPurpose, algorithm, seed string, state models.SigningKeyState, now time.Time) error {
+	if seed == "" {
+		_, err := s.createSigningKey(ctx, purpose, algorithm, state, now)
+		return err
+	}
+
+	var key *signingKey
+	var err error
+	if algorithm == "HS256" {
+		key, err = newHMACSigningKey([]byte(seed))
+	} else {
+		key, err = newAsymmetricSigningKey(algorithm, seed)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storeSigningKey(ctx, purpose, key, state, now)
+	return err
+}
+
+func (s *jwtService) createSigningKey(ctx context.Context, purpose models.SigningKeyPurpose, algorithm string, state models.SigningKeyState, activateAt time.Time) (models.SigningKey, error) {
+	key, err := generateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storeSigningKey(ctx, purpose, key, state, activateAt)
+}
+
+func (s *jwtService) storeSigningKey(ctx context.Context, purpose models.SigningKeyPurpose, key *signingKey, state models.SigningKeyState, activateAt time.Time) (models.SigningKey, error) {
+	material, err := key.material()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := s.kms.Wrap(ctx, material)
+	if err != nil {
+		return nil, err
+	}
+
+	props := models.SigningKeyProps{
+		KeyID:           key.kid,
+		Purpose:         purpose,
+		Algorithm:       key.method.Alg(),
+		WrappedMaterial: wrapped,
+		State:           state,
+		ActivatesAt:     activateAt,
+	}
+	if state == models.SigningKeyStateVerifyOnly {
+		expiresAt := activateAt.Add(s.overlap)
+		props.ExpiresAt = &expiresAt
+	}
+
+	model, er := models.NewSigningKey(props)
+	if er != nil {
+		return nil, er
+	}
+
+	if err := s.signingKeyRepo.Save(ctx, model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// isSuperseded reports whether another active key of the same purpose
+// activated after key and is already signing.
+func isSuperseded(key models.SigningKey, keys []models.SigningKey, now time.Time) bool {
+	for _, other := range keys {
+		if other.GetKeyID() == key.GetKeyID() || other.GetPurpose() != key.GetPurpose() {
+			continue
+		}
+		if other.CanSign(now) && other.GetActivatesAt().After(key.GetActivatesAt()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package adapters
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fakeSigningKeyRepo struct {
+	mu   sync.Mutex
+	keys []models.SigningKey
+}
+
+func (r *fakeSigningKeyRepo) Save(ctx context.Context, key models.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.keys {
+		if stored.GetKeyID() == key.GetKeyID() {
+			r.keys[i] = key
+			return nil
+		}
+	}
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeSigningKeyRepo) List(ctx context.Context) ([]models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.SigningKey(nil), r.keys...), nil
+}
+
+func (r *fakeSigningKeyRepo) Delete(ctx context.Context, keyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.keys {
+		if stored.GetKeyID() == keyID {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func newTestKMS() services.IKeyManagementService {
+	return NewKeyManagementService(config.KMSConfig{MasterKey: base64.StdEncoding.EncodeToString(make([]byte, 32))})
+}
+
+func newTestJWTService(t *testing.T, cfg config.JWTConfig) services.IJWTService {
+	t.Helper()
+	return newTestJWTServiceWithRepo(t, cfg, &fakeSigningKeyRepo{})
+}
+
+func newTestJWTServiceWithRepo(t *testing.T, cfg config.JWTConfig, repo *fakeSigningKeyRepo) services.IJWTService {
+	t.Helper()
+	cfg.Issuer = "https://auth.example"
+	cfg.ClockSkew = 30 * time.Second
+	if cfg.KeyOverlap == 0 {
+		cfg.KeyOverlap = time.Hour
+	}
+	return NewJWTService(cfg, repo, newTestKMS())
+}
+
+func issueTestToken(t *testing.T, service services.IJWTService) string {
+	t.Helper()
+	token, err := service.GenerateToken(context.Background(), services.AccessTokenClaims{UserID: "user-1", ExpiresIn: 300})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	return *token
+}
+
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	return parsed.Header
+}
+
+func TestAsymmetricSigningPublishesJWKS(t *testing.T) {
+	for _, algorithm := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: algorithm})
+			token := issueTestToken(t, service)
+
+			header := tokenHeader(t, token)
+			if header["alg"] != algorithm {
+				t.Errorf("alg = %v, want %s", header["alg"], algorithm)
+			}
+
+			keys, err := service.GetPublicKeys(context.Background())
+			if err != nil {
+				t.Fatalf("GetPublicKeys() error = %v", err)
+			}
+			if len(keys) != 1 || keys[0].Kid != header["kid"] || keys[0].Alg != algorithm {
+				t.Fatalf("JWKS = %+v, want the key %v", keys, header["kid"])
+			}
+
+			claims, err := service.ExtractClaims(context.Background(), token, services.TokenExpectations{})
+			if err != nil {
+				t.Fatalf("ExtractClaims() error = %v", err)
+			}
+			if claims["sub"] != "user-1" {
+				t.Errorf("sub = %v, want user-1", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestHMACKeysAreNotPublished(t *testing.T) {
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "HS256"})
+
+	keys, err := service.GetPublicKeys(context.Background())
+	if err != nil {
+		t.Fatalf("GetPublicKeys() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("JWKS = %+v, want no keys", keys)
+	}
+}
+
+func TestExtractClaimsRefusesForeignKeysAndAlgorithms(t *testing.T) {
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "EdDSA"})
+	kid := tokenHeader(t, issueTestToken(t, service))["kid"]
+
+	claims := jwt.MapClaims{"sub": "user-1", "type": "access", "exp": time.Now().Add(time.Minute).Unix()}
+
+	// An HS256 token under the EdDSA key's kid: verifying it with the
+	// public key as an HMAC secret must not even be tried.
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	confused.Header["kid"] = kid
+	confusedToken, err := confused.SignedString([]byte("public key bytes"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	unknown.Header["kid"] = "unknown"
+	unknownToken, err := unknown.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"algorithm confusion": confusedToken, "unknown kid": unknownToken} {
+		if _, err := service.ExtractClaims(context.Background(), token, services.TokenExpectations{}); err == nil {
+			t.Errorf("%s: ExtractClaims() accepted the token", name)
+		}
+	}
+}
+
+func TestSwitchToAsymmetricKeepsHMACTokensVerifying(t *testing.T) {
+	const secret = "0123456789abcdef0123456789abcdef"
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "ES256", SecretKey: secret})
+
+	// A token from before the switch: HS256 under the shared secret, from
+	// before tokens carried a kid.
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "user-1",
+		"type": "access",
+		"exp":  time.Now().Add(time.Minute).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	if _, err := service.ExtractClaims(context.Background(), legacy, services.TokenExpectations{}); err != nil {
+		t.Errorf("ExtractClaims(legacy HS256 token) error = %v", err)
+	}
+	if alg := tokenHeader(t, issueTestToken(t, service))["alg"]; alg != "ES256" {
+		t.Errorf("new tokens are signed with %v, want ES256", alg)
+	}
+}
+
+func publishedKeyIDs(t *testing.T, service services.IJWTService) map[string]bool {
+	t.Helper()
+	keys, err := service.GetPublicKeys(context.Background())
+	if err != nil {
+		t.Fatalf("GetPublicKeys() error = %v", err)
+	}
+	kids := map[string]bool{}
+	for _, key := range keys {
+		kids[key.Kid] = true
+	}
+	return kids
+}
+
+func TestRotationKeepsOldTokensVerifyingDuringOverlap(t *testing.T) {
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "ES256"})
+	oldToken := issueTestToken(t, service)
+	oldKid := tokenHeader(t, oldToken)["kid"].(string)
+
+	newKey, err := service.RotateSigningKey(context.Background(), models.SigningKeyPurposeAccess, "", time.Now())
+	if err != nil {
+		t.Fatalf("RotateSigningKey() error = %v", err)
+	}
+
+	if kid := tokenHeader(t, issueTestToken(t, service))["kid"]; kid != newKey.GetKeyID() {
+		t.Errorf("new tokens are signed with %v, want the rotated key %s", kid, newKey.GetKeyID())
+	}
+	if _, err := service.ExtractClaims(context.Background(), oldToken, services.TokenExpectations{}); err != nil {
+		t.Errorf("ExtractClaims(token of the old key) error = %v", err)
+	}
+	if kids := publishedKeyIDs(t, service); !kids[oldKid] || !kids[newKey.GetKeyID()] {
+		t.Errorf("JWKS = %v, want both the old and the rotated key", kids)
+	}
+}
+
+func TestKeyScheduledAheadIsPublishedBeforeSigning(t *testing.T) {
+	service := newTestJWTService(t, config.JWTConfig{SigningAlgorithm: "RS256"})
+	currentKid := tokenHeader(t, issueTestToken(t, service))["kid"]
+
+	newKey, err := service.RotateSigningKey(context.Background(), models.SigningKeyPurposeAccess, "", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("RotateSigningKey() error = %v", err)
+	}
+
+	if kids := publishedKeyIDs(t, service); !kids[newKey.GetKeyID()] {
+		t.Errorf("JWKS = %v, want the scheduled key %s published", kids, newKey.GetKeyID())
+	}
+	if kid := tokenHeader(t, issueTestToken(t, service))["kid"]; kid != currentKid {
+		t.Errorf("tokens are signed with %v before the rotation, want %v", kid, currentKid)
+	}
+}
+
+func TestKeyPastOverlapStopsVerifyingAndIsRetired(t *testing.T) {
+	repo := &fakeSigningKeyRepo{}
+	service := newTestJWTServiceWithRepo(t, config.JWTConfig{
+		SigningAlgorithm:    "EdDSA",
+		KeyOverlap:          time.Hour,
+		RetiredKeyRetention: time.Hour,
+	}, repo)
+	oldToken := issueTestToken(t, service)
+	oldKid := tokenHeader(t, oldToken)["kid"].(string)
+
+	// The new key took over two hours ago, so the old key's hour of
+	// overlap is over.
+	if _, err := service.RotateSigningKey(context.Background(), models.SigningKeyPurposeAccess, "", time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("RotateSigningKey() error = %v", err)
+	}
+
+	if _, err := service.ExtractClaims(context.Background(), oldToken, services.TokenExpectations{}); err == nil {
+		t.Error("ExtractClaims() accepted a token of a key past its overlap")
+	}
+	if kids := publishedKeyIDs(t, service); kids[oldKid] {
+		t.Errorf("JWKS = %v, want the expired key %s gone", kids, oldKid)
+	}
+
+	if err := service.MaintainSigningKeys(context.Background()); err != nil {
+		t.Fatalf("MaintainSigningKeys() error = %v", err)
+	}
+	keys, _ := repo.List(context.Background())
+	for _, key := range keys {
+		if key.GetKeyID() == oldKid && key.GetState() != models.SigningKeyStateRetired {
+			t.Errorf("old key state = %s, want %s", key.GetState(), models.SigningKeyStateRetired)
+		}
+	}
+}
+
+func TestMaintainSigningKeysDeletesRetiredKeys(t *testing.T) {
+	repo := &fakeSigningKeyRepo{}
+	service := newTestJWTServiceWithRepo(t, config.JWTConfig{SigningAlgorithm: "HS256"}, repo)
+	oldKid := tokenHeader(t, issueTestToken(t, service))["kid"].(string)
+
+	if _, err := service.RotateSigningKey(context.Background(), models.SigningKeyPurposeAccess, "", time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("RotateSigningKey() error = %v", err)
+	}
+	// No retention: the key is deleted as soon as it is retired.
+	if err := service.MaintainSigningKeys(context.Background()); err != nil {
+		t.Fatalf("MaintainSigningKeys() error = %v", err)
+	}
+
+	keys, _ := repo.List(context.Background())
+	for _, key := range keys {
+		if key.GetKeyID() == oldKid {
+			t.Errorf("old key left in state %s, want it deleted", key.GetState())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package adapters
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const wrappedKeyVersion byte = 1
+
+// localKeyManagementService wraps key material with AES-256-GCM under a
+// master key taken from the configuration (32 bytes, base64 encoded).
+type localKeyManagementService struct {
+	aead cipher.AEAD
+}
+
+func NewKeyManagementService(cfg config.KMSConfig) services.IKeyManagementService {
+	masterKey, err := base64.StdEncoding.DecodeString(cfg.MasterKey)
+	if err != nil || len(masterKey) != 32 {
+		panic("KMS_MASTER_KEY must be 32 bytes encoded as base64")
+	}
+
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create master key cipher: %v", err))
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create master key GCM: %v", err))
+	}
+
+	return &localKeyManagementService{
+		aead: aead,
+	}
+}
+
+func (s *localKeyManagementService) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	wrapped := append([]byte{wrappedKeyVersion}, nonce...)
+	return s.aead.Seal(wrapped, nonce, plaintext, []byte{wrappedKeyVersion}), nil
+}
+
+func (s *localKeyManagementService) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(wrapped) < 1+nonceSize || wrapped[0] != wrappedKeyVersion {
+		return nil, fmt.Errorf("unsupported wrapped key format")
+	}
+
+	plaintext, err := s.aead.Open(nil, wrapped[1:1+nonceSize], wrapped[1+nonceSize:], []byte{wrappedKeyVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const metricsNamespace = "authgate"
+
+type prometheusMetrics struct {
+	rpcRequests     *prometheus.CounterVec
+	rpcDuration     *prometheus.HistogramVec
+	loginAttempts   *prometheus.CounterVec
+	tokensIssued    *prometheus.CounterVec
+	tokensVerified  *prometheus.CounterVec
+	tokensRefreshed *prometheus.CounterVec
+	passwordHashing *prometheus.HistogramVec
+	encryption      *prometheus.HistogramVec
+}
+
+// NewMetricsRegistry returns the registry served on /metrics, with the Go
+// runtime and process collectors already registered.
+func NewMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+func NewMetrics(registry *prometheus.Registry) services.IMetrics {
+	metrics := &prometheusMetrics{
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by method and status code.",
+		}, []string{"method", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Time spent handling gRPC requests, by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		loginAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "login_attempts_total",
+			Help:      "Password logins, by outcome.",
+		}, []string{"outcome"}),
+		tokensIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tokens_issued_total",
+			Help:      "Tokens issued, by type.",
+		}, []string{"type"}),
+		tokensVerified: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tokens_verified_total",
+			Help:      "Tokens verified, by type and whether they were valid.",
+		}, []string{"type", "valid"}),
+		tokensRefreshed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tokens_refreshed_total",
+			Help:      "Refresh token grants, by whether they succeeded.",
+		}, []string{"success"}),
+		// bcrypt takes tens to hundreds of milliseconds depending on the cost.
+		passwordHashing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "password_hash_duration_seconds",
+			Help:      "Time spent hashing and comparing passwords with bcrypt, by operation.",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		}, []string{"operation"}),
+		encryption: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "token_encryption_duration_seconds",
+			Help:      "Time spent encrypting and decrypting tokens, by operation.",
+			Buckets:   []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05},
+		}, []string{"operation"}),
+	}
+
+	registry.MustRegister(
+		metrics.rpcRequests,
+		metrics.rpcDuration,
+		metrics.loginAttempts,
+		metrics.tokensIssued,
+		metrics.tokensVerified,
+		metrics.tokensRefreshed,
+		metrics.passwordHashing,
+		metrics.encryption,
+	)
+
+	return metrics
+}
+
+func (m *prometheusMetrics) ObserveRPC(method, code string, took time.Duration) {
+	m.rpcRequests.WithLabelValues(method, code).Inc()
+	m.rpcDuration.WithLabelValues(method, code).Observe(took.Seconds())
+}
+
+func (m *prometheusMetrics) LoginAttempt(outcome string) {
+	m.loginAttempts.WithLabelValues(outcome).Inc()
+}
+
+func (m *prometheusMetrics) TokenIssued(tokenType string) {
+	m.tokensIssued.WithLabelValues(tokenType).Inc()
+}
+
+func (m *prometheusMetrics) TokenVerified(tokenType string, valid bool) {
+	m.tokensVerified.WithLabelValues(tokenType, strconv.FormatBool(valid)).Inc()
+}
+
+func (m *prometheusMetrics) TokenRefreshed(success bool) {
+	m.tokensRefreshed.WithLabelValues(strconv.FormatBool(success)).Inc()
+}
+
+func (m *prometheusMetrics) ObservePasswordHash(operation string, took time.Duration) {
+	m.passwordHashing.WithLabelValues(operation).Observe(took.Seconds())
+}
+
+func (m *prometheusMetrics) ObserveEncryption(operation string, took time.Duration) {
+	m.encryption.WithLabelValues(operation).Observe(took.Seconds())
+}
+
+// observeSince reports the time elapsed since start to observe. It is meant
+// to be deferred.
+func observeSince(observe func(string, time.Duration), operation string, start time.Time) {
+	observe(operation, time.Since(start))
+}
@@ -0,0 +1,251 @@
+package adapters
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one key the jwtService can sign or verify with. For HMAC
+// keys signKey and verifyKey are the same secret; for asymmetric keys only
+// the public half is ever published.
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func newHMACSigningKey(secret []byte) (*signingKey, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HMAC secret cannot be empty")
+	}
+
+	kid, err := jwkThumbprint(map[string]string{
+		"kty": "oct",
+		"k":   base64.RawURLEncoding.EncodeToString(secret),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{
+		kid:       kid,
+		method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}, nil
+}
+
+// newAsymmetricSigningKey parses a PEM private key and checks that it fits
+// algorithm. The key ID is the RFC 7638 thumbprint of the public key.
+func newAsymmetricSigningKey(algorithm, privateKeyPEM string) (*signingKey, error) {
+	privateKey, err := parseSigningPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSignerSigningKey(algorithm, privateKey)
+}
+
+// newSigningKeyFromMaterial rebuilds a key from the raw material kept in the
+// key ring: the secret itself for HS256, PKCS#8 DER for everything else.
+func newSigningKeyFromMaterial(algorithm string, material []byte) (*signingKey, error) {
+	if algorithm == "HS256" {
+		return newHMACSigningKey(material)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(material)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("PKCS#8 key of type %T cannot sign", privateKey)
+	}
+
+	return newSignerSigningKey(algorithm, signer)
+}
+
+// generateSigningKey creates a key with fresh material for algorithm.
+func generateSigningKey(algorithm string) (*signingKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case "HS256":
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate HMAC secret: %w", err)
+		}
+		return newHMACSigningKey(secret)
+	case "RS256":
+		privateKey, err = rsa.GenerateKey(rand.Reader, 3072)
+	case "ES256":
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "EdDSA":
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", algorithm, err)
+	}
+
+	return newSignerSigningKey(algorithm, privateKey)
+}
+
+func newSignerSigningKey(algorithm string, privateKey crypto.Signer) (*signingKey, error) {
+	var method jwt.SigningMethod
+	switch algorithm {
+	case "RS256":
+		if _, ok := privateKey.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("RS256 requires an RSA private key")
+		}
+		method = jwt.SigningMethodRS256
+	case "ES256":
+		ecKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 EC private key")
+		}
+		method = jwt.SigningMethodES256
+	case "EdDSA":
+		if _, ok := privateKey.(ed25519.PrivateKey); !ok {
+			return nil, fmt.Errorf("EdDSA requires an Ed25519 private key")
+		}
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	key := &signingKey{
+		method:    method,
+		signKey:   privateKey,
+		verifyKey: privateKey.Public(),
+	}
+
+	jwk, err := key.publicJWK()
+	if err != nil {
+		return nil, err
+	}
+	key.kid = jwk.Kid
+
+	return key, nil
+}
+
+// material returns the raw form of the key that newSigningKeyFromMaterial
+// understands.
+func (k *signingKey) material() ([]byte, error) {
+	if secret, ok := k.signKey.([]byte); ok {
+		return secret, nil
+	}
+
+	material, err := x509.MarshalPKCS8PrivateKey(k.signKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	return material, nil
+}
+
+func (k *signingKey) isSymmetric() bool {
+	_, ok := k.method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// publicJWK renders the public half of the key as a JWK. The kid is
+// computed from the required members only, so it is stable no matter which
+// optional members are added.
+func (k *signingKey) publicJWK() (*services.JSONWebKey, error) {
+	jwk := &services.JSONWebKey{
+		Use: "sig",
+		Alg: k.method.Alg(),
+	}
+
+	var members map[string]string
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		members = map[string]string{"kty": jwk.Kty, "n": jwk.N, "e": jwk.E}
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode EC public key: %w", err)
+		}
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[:size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[size:])
+		members = map[string]string{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X, "y": jwk.Y}
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		members = map[string]string{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X}
+	default:
+		return nil, fmt.Errorf("key type %T has no public JWK representation", k.verifyKey)
+	}
+
+	kid, err := jwkThumbprint(members)
+	if err != nil {
+		return nil, err
+	}
+	jwk.Kid = kid
+
+	return jwk, nil
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of a JWK given its required
+// members. encoding/json sorts map keys, which yields the canonical form.
+func jwkThumbprint(members map[string]string) (string, error) {
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWK: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func parseSigningPrivateKey(privateKeyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("PKCS#8 key of type %T cannot sign", key)
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("failed to parse private key: unsupported format")
+}
@@ -0,0 +1,149 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+type ringEntry struct {
+	model models.SigningKey
+	key   *signingKey
+}
+
+// signingKeyRing holds the usable keys of one purpose, decoded from the
+// signing_keys table. It is rebuilt wholesale on every reload so readers
+// never see a partially updated ring.
+type signingKeyRing struct {
+	purpose models.SigningKeyPurpose
+	kms     services.IKeyManagementService
+
+	mu      sync.RWMutex
+	entries map[string]ringEntry
+}
+
+func newSigningKeyRing(purpose models.SigningKeyPurpose, kms services.IKeyManagementService) *signingKeyRing {
+	return &signingKeyRing{
+		purpose: purpose,
+		kms:     kms,
+		entries: map[string]ringEntry{},
+	}
+}
+
+// load replaces the ring with the keys of its purpose found in models.
+// Material already decoded for a key ID is reused instead of unwrapped again.
+func (r *signingKeyRing) load(ctx context.Context, keys []models.SigningKey) error {
+	r.mu.RLock()
+	previous := r.entries
+	r.mu.RUnlock()
+
+	entries := make(map[string]ringEntry, len(keys))
+	for _, model := range keys {
+		if model.GetPurpose() != r.purpose || model.GetState() == models.SigningKeyStateRetired {
+			continue
+		}
+
+		if existing, ok := previous[model.GetKeyID()]; ok {
+			entries[model.GetKeyID()] = ringEntry{model: model, key: existing.key}
+			continue
+		}
+
+		material, err := r.kms.Unwrap(ctx, model.GetWrappedMaterial())
+		if err != nil {
+			return fmt.Errorf("failed to unwrap signing key %s: %w", model.GetKeyID(), err)
+		}
+
+		key, err := newSigningKeyFromMaterial(model.GetAlgorithm(), material)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key %s: %w", model.GetKeyID(), err)
+		}
+
+		entries[model.GetKeyID()] = ringEntry{model: model, key: key}
+	}
+
+	r.mu.Lock()
+	r.entries = entries
+	r.mu.Unlock()
+
+	return nil
+}
+
+// signer returns the most recently activated key that may sign at now.
+func (r *signingKeyRing) signer(now time.Time) (*signingKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var current *ringEntry
+	for _, entry := range r.entries {
+		if !entry.model.CanSign(now) {
+			continue
+		}
+		if current == nil || entry.model.GetActivatesAt().After(current.model.GetActivatesAt()) {
+			e := entry
+			current = &e
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("no active %s signing key", r.purpose)
+	}
+
+	return current.key, nil
+}
+
+func (r *signingKeyRing) lookup(kid string, now time.Time) *signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[kid]
+	if !ok || !entry.model.CanVerify(now) {
+		return nil
+	}
+
+	return entry.key
+}
+
+// symmetricKeys returns the HMAC keys that still verify at now. Tokens issued
+// before key IDs existed carry no kid and are checked against these.
+func (r *signingKeyRing) symmetricKeys(now time.Time) []*signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []*signingKey
+	for _, entry := range r.entries {
+		if entry.key.isSymmetric() && entry.model.CanVerify(now) {
+			keys = append(keys, entry.key)
+		}
+	}
+
+	return keys
+}
+
+// verifiableKeys returns every key that still verifies at now, ordered by
+// activation time.
+func (r *signingKeyRing) verifiableKeys(now time.Time) []*signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]ringEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if entry.model.CanVerify(now) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].model.GetActivatesAt().Before(entries[j].model.GetActivatesAt())
+	})
+
+	keys := make([]*signingKey, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.key)
+	}
+
+	return keys
+}
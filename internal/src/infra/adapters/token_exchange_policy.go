@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+type tokenExchangePolicy struct {
+	rules []models.TokenExchangeRule
+}
+
+// NewTokenExchangePolicy loads the rules from the configured JSON policy
+// file. Without a file no exchange is allowed.
+func NewTokenExchangePolicy(cfg config.TokenExchangeConfig) services.ITokenExchangePolicy {
+	policy := &tokenExchangePolicy{}
+
+	path := cfg.PolicyFile
+	if path == "" {
+		return policy
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read token exchange policy: %v", err))
+	}
+
+	var document struct {
+		Rules []models.TokenExchangeRule `json:"rules"`
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		panic(fmt.Sprintf("Failed to parse token exchange policy: %v", err))
+	}
+
+	for i, rule := range document.Rules {
+		if err := rule.Validate(); err != nil {
+			panic(fmt.Sprintf("Invalid token exchange rule %d: %v", i, err))
+		}
+	}
+	policy.rules = document.Rules
+
+	return policy
+}
+
+func (p *tokenExchangePolicy) Evaluate(ctx context.Context, request models.TokenExchangeRequest) *models.TokenExchangeRule {
+	for i := range p.rules {
+		if p.rules[i].Allows(request) {
+			return &p.rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const (
+	traceExporterNone   = "none"
+	traceExporterOTLP   = "otlp"
+	traceExporterStdout = "stdout"
+)
+
+var tracer = otel.Tracer("github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters")
+
+// NewSpanExporter returns the configured exporter: otlp
+// sends spans over gRPC to OTEL_EXPORTER_OTLP_ENDPOINT, stdout prints them,
+// and none, the default, drops them. Tests can replace it with an in-memory
+// exporter through fx.Decorate.
+func NewSpanExporter(cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch exporter := cfg.Exporter; exporter {
+	case "", traceExporterNone:
+		return nil, nil
+	case traceExporterOTLP:
+		return otlptracegrpc.New(context.Background())
+	case traceExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER must be %q, %q or %q, got %q",
+			traceExporterOTLP, traceExporterStdout, traceExporterNone, exporter)
+	}
+}
+
+// NewTracerProvider installs the global tracer provider and the W3C trace
+// context and baggage propagators. Spans are batched to exporter, when
+// there is one, and flushed when the app stops. The service name defaults to
+// authgate and, like the sampler, can be overridden with the standard
+// OTEL_* variables.
+func NewTracerProvider(lc fx.Lifecycle, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(attribute.String("service.name", "authgate")),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(options...)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	lc.Append(fx.StopHook(provider.Shutdown))
+
+	return provider, nil
+}
+
+// EndSpan marks span as failed when err is set and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+)
+
+const (
+	webhookSenderTimeout = 10 * time.Second
+
+	webhookSignatureHeader = "X-Authgate-Signature"
+	webhookTimestampHeader = "X-Authgate-Timestamp"
+	webhookDeliveryHeader  = "X-Authgate-Delivery"
+)
+
+type webhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender signs each request with HMAC-SHA256 over
+// "<timestamp>.<body>" using the subscription secret. The hex signature goes
+// in X-Authgate-Signature as "v1=<hex>" and the Unix timestamp in
+// X-Authgate-Timestamp; receivers recompute the signature and reject
+// requests whose timestamp is too old. Redirects are not followed.
+func NewWebhookSender() services.IWebhookSender {
+	return &webhookSender{
+		client: &http.Client{
+			Timeout: webhookSenderTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *webhookSender) Send(ctx context.Context, url, secret, deliveryID string, payload []byte, sentAt time.Time) services.WebhookResult {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return services.WebhookResult{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "authgate-webhooks")
+	req.Header.Set(webhookDeliveryHeader, deliveryID)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "v1="+signWebhook(secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return services.WebhookResult{Err: err}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return services.WebhookResult{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("receiver answered %s", resp.Status),
+		}
+	}
+
+	return services.WebhookResult{StatusCode: resp.StatusCode}
+}
+
+func signWebhook(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
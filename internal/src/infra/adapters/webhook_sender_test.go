@@ -0,0 +1,96 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookSenderSignsRequest(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"type":"user.registered"}`)
+	sentAt := time.Unix(1735689600, 0)
+
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	result := NewWebhookSender().Send(context.Background(), receiver.URL, secret, "delivery-1", payload, sentAt)
+	if result.Err != nil {
+		t.Fatalf("Send() error = %v", result.Err)
+	}
+	if result.StatusCode != http.StatusNoContent {
+		t.Errorf("StatusCode = %d, want %d", result.StatusCode, http.StatusNoContent)
+	}
+
+	if string(body) != string(payload) {
+		t.Errorf("body = %s, want %s", body, payload)
+	}
+	if got := received.Header.Get("X-Authgate-Delivery"); got != "delivery-1" {
+		t.Errorf("X-Authgate-Delivery = %q, want %q", got, "delivery-1")
+	}
+
+	timestamp := received.Header.Get("X-Authgate-Timestamp")
+	if timestamp != strconv.FormatInt(sentAt.Unix(), 10) {
+		t.Errorf("X-Authgate-Timestamp = %q, want %d", timestamp, sentAt.Unix())
+	}
+
+	// The receiver's side of the contract: HMAC-SHA256 over
+	// "<timestamp>.<body>" with the subscription secret.
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	want := "v1=" + hex.EncodeToString(mac.Sum(nil))
+	if got := received.Header.Get("X-Authgate-Signature"); !hmac.Equal([]byte(got), []byte(want)) {
+		t.Errorf("X-Authgate-Signature = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookSenderFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			},
+			wantStatus: http.StatusFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := httptest.NewServer(tt.handler)
+			defer receiver.Close()
+
+			result := NewWebhookSender().Send(context.Background(), receiver.URL, "0123456789abcdef", "delivery-1", []byte(`{}`), time.Now())
+			if result.Err == nil {
+				t.Fatal("Send() error = nil, want an error")
+			}
+			if result.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", result.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+)
+
+type audienceKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAudienceKeyRepository(db *gorm.DB) repositories.IAudienceKeyRepository {
+	return &audienceKeyRepository{
+		db: db,
+	}
+}
+
+func (r *audienceKeyRepository) Save(ctx context.Context, key models.AudienceKey) error {
+	keyEntity := mappers.AudienceKeyDomainToModel(key)
+
+	if err := r.db.WithContext(ctx).Save(&keyEntity).Error; err != nil {
+		return fmt.Errorf("failed to save audience key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *audienceKeyRepository) GetByAudience(ctx context.Context, audience string) (models.AudienceKey, error) {
+	var keyEntity entities.AudienceKey
+
+	if err := r.db.WithContext(ctx).
+		Where("audience = ?", audience).
+		First(&keyEntity).Error; err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("audience key not found for audience: %s", audience))
+		}
+		return nil, fmt.Errorf("database error in GetByAudience: %w", err)
+	}
+
+	key, err := mappers.AudienceKeyModelToDomain(keyEntity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *audienceKeyRepository) Delete(ctx context.Context, audience string) error {
+	if err := r.db.WithContext(ctx).
+		Where("audience = ?", audience).
+		Delete(&entities.AudienceKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete audience key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type auditCheckpointRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditCheckpointRepository(db *gorm.DB) repositories.IAuditCheckpointRepository {
+	return &auditCheckpointRepository{
+		db: db,
+	}
+}
+
+func (r *auditCheckpointRepository) Save(ctx context.Context, checkpoint models.AuditCheckpoint) error {
+	checkpointEntity := mappers.AuditCheckpointDomainToModel(checkpoint)
+
+	onConflict := clause.OnConflict{DoNothing: true}
+	if checkpoint.IsAnchor() {
+		onConflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: "sequence"}},
+			DoUpdates: clause.AssignmentColumns([]string{"hash", "key_id", "signature", "created_at", "anchor"}),
+		}
+	}
+
+	if err := r.db.WithContext(ctx).
+		Clauses(onConflict).
+		Create(&checkpointEntity).Error; err != nil {
+		return fmt.Errorf("failed to save audit checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (r *auditCheckpointRepository) GetLatest(ctx context.Context) (models.AuditCheckpoint, error) {
+	var checkpointEntities []entities.AuditCheckpoint
+	if err := r.db.WithContext(ctx).
+		Order("sequence DESC").
+		Limit(1).
+		Find(&checkpointEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in GetLatest: %w", err)
+	}
+
+	if len(checkpointEntities) == 0 {
+		return nil, exceptions.NewRepositoryNoDataFoundException("no audit checkpoint found")
+	}
+
+	checkpoint, err := mappers.AuditCheckpointModelToDomain(checkpointEntities[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+func (r *auditCheckpointRepository) GetLatestAnchor(ctx context.Context) (models.AuditCheckpoint, error) {
+	var checkpointEntities []entities.AuditCheckpoint
+	if err := r.db.WithContext(ctx).
+		Where("anchor").
+		Order("sequence DESC").
+		Limit(1).
+		Find(&checkpointEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in GetLatestAnchor: %w", err)
+	}
+
+	if len(checkpointEntities) == 0 {
+		return nil, exceptions.NewRepositoryNoDataFoundException("no audit anchor found")
+	}
+
+	checkpoint, err := mappers.AuditCheckpointModelToDomain(checkpointEntities[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+func (r *auditCheckpointRepository) ListFrom(ctx context.Context, sequence int64) ([]models.AuditCheckpoint, error) {
+	var checkpointEntities []entities.AuditCheckpoint
+	if err := r.db.WithContext(ctx).
+		Where("sequence >= ?", sequence).
+		Order("sequence ASC").
+		Find(&checkpointEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in ListFrom: %w", err)
+	}
+
+	checkpoints := make([]models.AuditCheckpoint, 0, len(checkpointEntities))
+	for _, checkpointEntity := range checkpointEntities {
+		checkpoint, err := mappers.AuditCheckpointModelToDomain(checkpointEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+func (r *auditCheckpointRepository) DeleteBefore(ctx context.Context, sequence int64) error {
+	if err := r.db.WithContext(ctx).
+		Where("sequence < ?", sequence).
+		Delete(&entities.AuditCheckpoint{}).Error; err != nil {
+		return fmt.Errorf("failed to delete audit checkpoints: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"gorm.io/gorm"
+)
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) repositories.IAuditEventRepository {
+	return &auditEventRepository{
+		db: db,
+	}
+}
+
+// auditChainLock is the Postgres advisory lock key that keeps one instance
+// at a time sealing the audit chain.
+const auditChainLock = 0x61756469
+
+// Save stores the event as pending. It takes no lock, so requests that are
+// audited at the same time do not wait on each other; Seal chains the event
+// afterwards.
+func (r *auditEventRepository) Save(ctx context.Context, event models.AuditEvent) error {
+	eventEntity := mappers.AuditEventDomainToModel(event)
+	eventEntity.Pending = true
+
+	if err := r.db.WithContext(ctx).Create(&eventEntity).Error; err != nil {
+		return fmt.Errorf("failed to save audit event: %w", err)
+	}
+
+	return nil
+}
+
+// Seal appends up to limit pending events to the chain, oldest first, and
+// returns how many it chained. An instance that finds another one sealing
+// skips the run instead of waiting for it.
+func (r *auditEventRepository) Seal(ctx context.Context, limit int) (int, error) {
+	var sealed int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", auditChainLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var pending []entities.AuditEvent
+		if err := tx.Where("pending").
+			Order("occurred_at ASC, id ASC").
+			Limit(limit).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		var head entities.AuditEvent
+		result := tx.Where("sequence IS NOT NULL").
+			Order("sequence DESC").
+			Limit(1).
+			Find(&head)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		sequence, prevHash := int64(1), ""
+		if result.RowsAffected > 0 {
+			sequence, prevHash = *head.Sequence+1, head.Hash
+		}
+
+		// The hash is taken over the event as read back, which is also what
+		// verification reads.
+		for _, eventEntity := range pending {
+			event, err := mappers.AuditEventModelToDomain(eventEntity)
+			if err != nil {
+				return fmt.Errorf("failed to convert entity to domain: %w", err)
+			}
+			event.Chain(sequence, prevHash)
+
+			if err := tx.Model(&entities.AuditEvent{}).
+				Where("id = ?", event.GetID()).
+				Updates(map[string]interface{}{
+					"sequence":  event.GetSequence(),
+					"prev_hash": event.GetPrevHash(),
+					"hash":      event.GetHash(),
+					"pending":   false,
+				}).Error; err != nil {
+				return err
+			}
+
+			sequence, prevHash = sequence+1, event.GetHash()
+		}
+
+		sealed = len(pending)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to seal audit events: %w", err)
+	}
+
+	return sealed, nil
+}
+
+func (r *auditEventRepository) Query(ctx context.Context, query repositories.AuditEventQuery) ([]models.AuditEvent, error) {
+	tx := r.db.WithContext(ctx).Model(&entities.AuditEvent{})
+
+	if query.UserID != "" {
+		tx = tx.Where("subject_id = ? OR actor_id = ?", query.UserID, query.UserID)
+	}
+	if len(query.Types) > 0 {
+		tx = tx.Where("type IN ?", query.Types)
+	}
+	if query.From != nil {
+		tx = tx.Where("occurred_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		tx = tx.Where("occurred_at < ?", *query.To)
+	}
+	if query.After != nil {
+		tx = tx.Where("(occurred_at, id) < (?, ?)", query.After.OccurredAt, query.After.ID)
+	}
+
+	var eventEntities []entities.AuditEvent
+	if err := tx.
+		Order("occurred_at DESC, id DESC").
+		Limit(query.Limit).
+		Find(&eventEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in Query: %w", err)
+	}
+
+	events := make([]models.AuditEvent, 0, len(eventEntities))
+	for _, eventEntity := range eventEntities {
+		event, err := mappers.AuditEventModelToDomain(eventEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *auditEventRepository) SequenceRange(ctx context.Context, from, to *time.Time) (int64, int64, error) {
+	tx := r.db.WithContext(ctx).Model(&entities.AuditEvent{}).Where("sequence IS NOT NULL")
+	if from != nil {
+		tx = tx.Where("occurred_at >= ?", *from)
+	}
+	if to != nil {
+		tx = tx.Where("occurred_at < ?", *to)
+	}
+
+	var bounds struct {
+		First *int64
+		Last  *int64
+	}
+	if err := tx.Select("MIN(sequence) AS first, MAX(sequence) AS last").Scan(&bounds).Error; err != nil {
+		return 0, 0, fmt.Errorf("database error in SequenceRange: %w", err)
+	}
+
+	if bounds.First == nil || bounds.Last == nil {
+		return 0, 0, nil
+	}
+
+	return *bounds.First, *bounds.Last, nil
+}
+
+func (r *auditEventRepository) ListBySequence(ctx context.Context, first, last int64, limit int) ([]models.AuditEvent, error) {
+	var eventEntities []entities.AuditEvent
+	if err := r.db.WithContext(ctx).
+		Where("sequence BETWEEN ? AND ?", first, last).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&eventEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in ListBySequence: %w", err)
+	}
+
+	events := make([]models.AuditEvent, 0, len(eventEntities))
+	for _, eventEntity := range eventEntities {
+		event, err := mappers.AuditEventModelToDomain(eventEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// DeleteBefore cuts the chain right before sequence. Unchained events are
+// deleted by time, except for pending ones, which are about to be chained.
+func (r *auditEventRepository) DeleteBefore(ctx context.Context, before time.Time, sequence int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("(sequence IS NULL AND NOT pending AND occurred_at < ?) OR sequence < ?", before, sequence).
+		Delete(&entities.AuditEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete audit events: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -3,9 +3,11 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
 	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
@@ -13,17 +15,26 @@ import (
 )
 
 type authRepository struct {
-	db *gorm.DB
+	db              *gorm.DB
+	fieldEncryption services.IFieldEncryptionService
 }
 
-func NewAuthRepository(db *gorm.DB) repositories.IAuthRepository {
+// NewAuthRepository stores identifier values and user names encrypted.
+// Identifiers are looked up through their blind index; rows written before
+// field encryption still match on the plaintext column until they are
+// migrated with cmd/migrate-pii.
+func NewAuthRepository(db *gorm.DB, fieldEncryption services.IFieldEncryptionService) repositories.IAuthRepository {
 	return &authRepository{
-		db: db,
+		db:              db,
+		fieldEncryption: fieldEncryption,
 	}
 }
 
 func (r *authRepository) Save(ctx context.Context, auth models.Auth) (models.Auth, error) {
 	authEntity := mappers.DomainToModel(auth)
+	if err := sealAuth(ctx, r.fieldEncryption, &authEntity); err != nil {
+		return nil, err
+	}
 
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Omit("UserInfo").Create(&authEntity).Error; err != nil {
@@ -37,7 +48,10 @@ func (r *authRepository) Save(ctx context.Context, auth models.Auth) (models.Aut
 			}
 		}
 
-		return nil
+		return appendUserEvent(tx, models.EventUserRegistered, auth.GetUserInfo().GetUserID(), map[string]interface{}{
+			"identifier_type": auth.GetIdentifierType().String(),
+			"roles":           auth.GetUserInfo().GetRoles(),
+		})
 	})
 
 	if err != nil {
@@ -51,6 +65,10 @@ func (r *authRepository) Save(ctx context.Context, auth models.Auth) (models.Aut
         return nil, fmt.Errorf("failed to reload saved auth: %w", err)
     }
 
+	if err := openAuth(ctx, r.fieldEncryption, &authEntity); err != nil {
+		return nil, err
+	}
+
 	savedAuth, err := mappers.ModelToDomain(authEntity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert saved entity to domain: %w", err)
@@ -75,6 +93,10 @@ func (r *authRepository) GetByUserID(ctx context.Context, userID string) (models
 		return nil, fmt.Errorf("database error in GetByUserID: %w", err)
 	}
 
+	if err := openAuth(ctx, r.fieldEncryption, &authEntity); err != nil {
+		return nil, err
+	}
+
 	auth, err := mappers.ModelToDomain(authEntity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
@@ -88,16 +110,21 @@ func (r *authRepository) GetByIdentifier(ctx context.Context, identifierType int
 
 	if err := r.db.WithContext(ctx).
 		Preload("UserInfo").
-		Where("identifier_type = ? AND identifier_value = ?", identifierType, identifierValue).
+		Where("identifier_type = ? AND (identifier_index = ? OR (identifier_index IS NULL AND identifier_value = ?))",
+			identifierType, identifierIndex(r.fieldEncryption, identifierType, identifierValue), identifierValue).
 		First(&authEntity).Error; err != nil {
 
 		if err == gorm.ErrRecordNotFound {
 			return nil, exceptions.NewRepositoryNoDataFoundException(
-				fmt.Sprintf("Auth not found for identifier type: %d and value: %s", identifierType, identifierValue))
+				fmt.Sprintf("Auth not found for identifier type: %d", identifierType))
 		}
 		return nil, fmt.Errorf("database error in GetByIdentifier: %w", err)
 	}
 
+	if err := openAuth(ctx, r.fieldEncryption, &authEntity); err != nil {
+		return nil, err
+	}
+
 	auth, err := mappers.ModelToDomain(authEntity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
@@ -106,6 +133,31 @@ func (r *authRepository) GetByIdentifier(ctx context.Context, identifierType int
 	return auth, nil
 }
 
+func (r *authRepository) UpdateUserInfo(ctx context.Context, userInfo models.UserInfo) error {
+	userInfoEntity := mappers.UserInfoDomainToModel(userInfo)
+
+	name, err := r.fieldEncryption.EncryptField(ctx, userInfoEntity.Name)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt user name: %w", err)
+	}
+	userInfoEntity.Name = name
+
+	result := r.db.WithContext(ctx).
+		Model(&entities.UserInfo{}).
+		Where("user_id = ?", userInfoEntity.UserID).
+		Select("name", "metadata").
+		Updates(&userInfoEntity)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user info: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return exceptions.NewRepositoryNoDataFoundException(
+			fmt.Sprintf("User info not found for user ID: %s", userInfoEntity.UserID))
+	}
+
+	return nil
+}
+
 func (r *authRepository) Delete(ctx context.Context, userID string) error {
 	var authEntity entities.Auth
 
@@ -134,7 +186,7 @@ func (r *authRepository) Delete(ctx context.Context, userID string) error {
 			return fmt.Errorf("failed to delete auth: %w", err)
 		}
 
-		return nil
+		return appendUserEvent(tx, models.EventUserDeleted, userID, nil)
 	})
 
 	if err != nil {
@@ -142,4 +194,70 @@ func (r *authRepository) Delete(ctx context.Context, userID string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *authRepository) UpdatePassword(ctx context.Context, userID string, password string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Auth{}).
+			Where("id = (SELECT auth_id FROM user_infos WHERE user_id = ?)", userID).
+			Updates(map[string]interface{}{
+				"password":       password,
+				"wrong_attempts": 0,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update password: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("Auth not found for user ID: %s", userID))
+		}
+
+		return appendUserEvent(tx, models.EventPasswordChanged, userID, nil)
+	})
+}
+
+func (r *authRepository) RecordLoginAttempt(ctx context.Context, userID string, success bool) (int, error) {
+	if success {
+		result := r.db.WithContext(ctx).
+			Model(&entities.Auth{}).
+			Where("id = (SELECT auth_id FROM user_infos WHERE user_id = ?)", userID).
+			Updates(map[string]interface{}{
+				"wrong_attempts": 0,
+				"last_login_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return 0, fmt.Errorf("failed to record login: %w", result.Error)
+		}
+		return 0, nil
+	}
+
+	// Incrementing in the database keeps concurrent failures from being
+	// counted once.
+	var attempts []int
+	if err := r.db.WithContext(ctx).
+		Raw(`UPDATE auths SET wrong_attempts = wrong_attempts + 1, updated_at = ?
+			WHERE id = (SELECT auth_id FROM user_infos WHERE user_id = ?)
+			RETURNING wrong_attempts`, time.Now(), userID).
+		Scan(&attempts).Error; err != nil {
+		return 0, fmt.Errorf("failed to record failed login: %w", err)
+	}
+	if len(attempts) == 0 {
+		return 0, exceptions.NewRepositoryNoDataFoundException(
+			fmt.Sprintf("Auth not found for user ID: %s", userID))
+	}
+
+	return attempts[0], nil
+}
+
+func appendUserEvent(tx *gorm.DB, eventType, userID string, data map[string]interface{}) error {
+	event, err := models.NewDomainEvent(models.DomainEventProps{
+		Type:   eventType,
+		UserID: userID,
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+
+	return appendToOutbox(tx, event)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type authorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorizationCodeRepository(db *gorm.DB) repositories.IAuthorizationCodeRepository {
+	return &authorizationCodeRepository{
+		db: db,
+	}
+}
+
+func (r *authorizationCodeRepository) Save(ctx context.Context, code models.AuthorizationCode) error {
+	codeEntity := mappers.AuthorizationCodeDomainToModel(code)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Codes live for seconds, so expired leftovers are swept on write
+		// instead of by a separate job.
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&entities.AuthorizationCode{}).Error; err != nil {
+			return fmt.Errorf("failed to purge expired authorization codes: %w", err)
+		}
+
+		if err := tx.Create(&codeEntity).Error; err != nil {
+			return fmt.Errorf("failed to save authorization code: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *authorizationCodeRepository) Consume(ctx context.Context, codeHash string) (models.AuthorizationCode, error) {
+	var codeEntities []entities.AuthorizationCode
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("code_hash = ?", codeHash).
+		Delete(&codeEntities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("database error in Consume: %w", result.Error)
+	}
+	if result.RowsAffected == 0 || len(codeEntities) == 0 {
+		return nil, exceptions.NewRepositoryNoDataFoundException("authorization code not found")
+	}
+
+	code, err := mappers.AuthorizationCodeModelToDomain(codeEntities[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return code, nil
+}
@@ -1,30 +1,85 @@
 package connection
 
 import (
-	"log"
-	"os"
-	"strconv"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/fx"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
-func SetupConfig() *gorm.DB {
-	dbPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
+// NewDB opens the database for the app and closes it when the app stops.
+// The stop hook is registered as the connection is built, so it runs after
+// the hooks of the servers and workers that use it: in-flight requests
+// still reach the database while they drain.
+func NewDB(lc fx.Lifecycle, dbConfig config.DbConfig, tracerProvider *sdktrace.TracerProvider, logger *slog.Logger) (*gorm.DB, error) {
+	db, err := SetupConfig(dbConfig, tracerProvider, logger)
 	if err != nil {
-		log.Fatalf("Error converting DB_PORT to int")
+		return nil, err
 	}
 
-	dbConfig := config.NewDbConfig(os.Getenv("DB_HOST"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"), dbPort)
+	lc.Append(fx.StopHook(func() error {
+		sqlDb, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
 
-	db, err := gorm.Open(postgres.Open(dbConfig.ToString()), &gorm.Config{})
+		return sqlDb.Close()
+	}))
+
+	return db, nil
+}
+
+// SetupConfig opens the database and migrates its schema.
+func SetupConfig(dbConfig config.DbConfig, tracerProvider *sdktrace.TracerProvider, logger *slog.Logger) (*gorm.DB, error) {
+	db, err := Open(postgres.Open(dbConfig.ToString()), tracerProvider, logger)
 	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
+		return nil, err
 	}
 
-	db.AutoMigrate(entities.Auth{}, entities.UserInfo{})
+	// Nothing may run against a schema that is missing the blind index or
+	// the audit and outbox tables.
+	if err := db.AutoMigrate(entities.Auth{}, entities.UserInfo{}, entities.OAuthClient{}, entities.AuthorizationCode{}, entities.SigningKey{}, entities.RevokedToken{}, entities.EncryptionKey{}, entities.AudienceKey{}, entities.AuditEvent{}, entities.AuditCheckpoint{}, entities.OutboxMessage{}, entities.WebhookSubscription{}, entities.WebhookDelivery{}); err != nil {
+		if sqlDb, err := db.DB(); err == nil {
+			sqlDb.Close()
+		}
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Open opens the database behind dialector and traces every query with the
+// provider. Query variables are left out of the spans since they carry
+// identifiers, password hashes and tokens, and for the same reason slow and
+// failed queries are logged without them.
+func Open(dialector gorm.Dialector, tracerProvider *sdktrace.TracerProvider, logger *slog.Logger) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormlogger.New(slog.NewLogLogger(logger.Handler(), slog.LevelWarn), gormlogger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			LogLevel:                  gormlogger.Warn,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true,
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := db.Use(tracing.NewPlugin(
+		tracing.WithTracerProvider(tracerProvider),
+		tracing.WithoutQueryVariables(),
+		tracing.WithoutMetrics(),
+	)); err != nil {
+		return nil, fmt.Errorf("failed to instrument database: %w", err)
+	}
 
-	return db
+	return db, nil
 }
\ No newline at end of file
@@ -0,0 +1,33 @@
+package connection
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// RegisterPoolMetrics exports the connection pool statistics of db (open,
+// in use and idle connections, waits and closes) for as long as the app
+// runs.
+func RegisterPoolMetrics(lc fx.Lifecycle, registry *prometheus.Registry, db *gorm.DB) {
+	var collector prometheus.Collector
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			sqlDb, err := db.DB()
+			if err != nil {
+				return err
+			}
+
+			collector = collectors.NewDBStatsCollector(sqlDb, "authgate")
+			return registry.Register(collector)
+		},
+		OnStop: func(ctx context.Context) error {
+			registry.Unregister(collector)
+			return nil
+		},
+	})
+}
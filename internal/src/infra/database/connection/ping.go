@@ -0,0 +1,17 @@
+package connection
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Ping checks that the database answers.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDb, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDb.PingContext(ctx)
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"gorm.io/gorm"
+)
+
+type encryptionKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewEncryptionKeyRepository(db *gorm.DB) repositories.IEncryptionKeyRepository {
+	return &encryptionKeyRepository{
+		db: db,
+	}
+}
+
+func (r *encryptionKeyRepository) Save(ctx context.Context, key models.EncryptionKey) error {
+	keyEntity := mappers.EncryptionKeyDomainToModel(key)
+
+	if err := r.db.WithContext(ctx).Save(&keyEntity).Error; err != nil {
+		return fmt.Errorf("failed to save encryption key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *encryptionKeyRepository) List(ctx context.Context) ([]models.EncryptionKey, error) {
+	var keyEntities []entities.EncryptionKey
+
+	if err := r.db.WithContext(ctx).
+		Order("activates_at ASC").
+		Find(&keyEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in List: %w", err)
+	}
+
+	keys := make([]models.EncryptionKey, 0, len(keyEntities))
+	for _, keyEntity := range keyEntities {
+		key, err := mappers.EncryptionKeyModelToDomain(keyEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *encryptionKeyRepository) Delete(ctx context.Context, keyID string) error {
+	if err := r.db.WithContext(ctx).
+		Where("key_id = ?", keyID).
+		Delete(&entities.EncryptionKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete encryption key: %w", err)
+	}
+
+	return nil
+}
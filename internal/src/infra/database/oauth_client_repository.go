@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+)
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) repositories.IOAuthClientRepository {
+	return &oauthClientRepository{
+		db: db,
+	}
+}
+
+func (r *oauthClientRepository) Save(ctx context.Context, client models.OAuthClient) (models.OAuthClient, error) {
+	clientEntity := mappers.OAuthClientDomainToModel(client)
+
+	if err := r.db.WithContext(ctx).Save(&clientEntity).Error; err != nil {
+		return nil, fmt.Errorf("failed to save oauth client: %w", err)
+	}
+
+	savedClient, err := mappers.OAuthClientModelToDomain(clientEntity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert saved entity to domain: %w", err)
+	}
+
+	return savedClient, nil
+}
+
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (models.OAuthClient, error) {
+	var clientEntity entities.OAuthClient
+
+	if err := r.db.WithContext(ctx).
+		Where("client_id = ?", clientID).
+		First(&clientEntity).Error; err != nil {
+
+		if err == gorm.ErrRecordNotFound {
+			return nil, exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("OAuth client not found for client ID: %s", clientID))
+		}
+		return nil, fmt.Errorf("database error in GetByClientID: %w", err)
+	}
+
+	client, err := mappers.OAuthClientModelToDomain(clientEntity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return client, nil
+}
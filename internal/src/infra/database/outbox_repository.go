@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repositories.IOutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// appendToOutbox writes event in the transaction of the change it
+// describes, so the event exists if and only if the change was committed.
+func appendToOutbox(tx *gorm.DB, event models.DomainEvent) error {
+	messageEntity := mappers.DomainEventToOutboxModel(event)
+
+	if err := tx.Create(&messageEntity).Error; err != nil {
+		return fmt.Errorf("failed to write %s to the outbox: %w", event.GetType(), err)
+	}
+
+	return nil
+}
+
+// Claim takes the head of each user's queue that is due and not leased by
+// another relay. The lease is re-checked by the UPDATE itself, so two
+// relays racing for the same message cannot both win it.
+func (r *outboxRepository) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]models.OutboxMessage, error) {
+	now := time.Now()
+
+	var messageEntities []entities.OutboxMessage
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE outbox_messages SET locked_until = ?
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT DISTINCT ON (user_id) id, sequence, next_attempt_at, locked_until
+				FROM outbox_messages
+				WHERE published_at IS NULL
+				ORDER BY user_id, sequence
+			) heads
+			WHERE next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY sequence
+			LIMIT ?
+		)
+		AND published_at IS NULL AND (locked_until IS NULL OR locked_until < ?)
+		RETURNING *`,
+		leaseUntil, now, now, limit, now,
+	).Scan(&messageEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in Claim: %w", err)
+	}
+
+	messages := make([]models.OutboxMessage, 0, len(messageEntities))
+	for _, messageEntity := range messageEntities {
+		message, err := mappers.OutboxMessageModelToDomain(messageEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, eventID string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&entities.OutboxMessage{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"locked_until": nil,
+			"last_error":   nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message as published: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, eventID string, reason string, retryAt time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Model(&entities.OutboxMessage{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": retryAt,
+			"locked_until":    nil,
+			"last_error":      reason,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message as failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at < ?", before).
+		Delete(&entities.OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete published outbox messages: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"gorm.io/gorm"
+)
+
+// identifierIndex is the blind index of an identifier. The type is part of
+// the input so equal values of different types never share an index.
+func identifierIndex(fieldEncryption services.IFieldEncryptionService, identifierType int, identifierValue string) string {
+	return fieldEncryption.BlindIndex(fmt.Sprintf("%d:%s", identifierType, identifierValue))
+}
+
+// sealAuth replaces the personal data of an auth row with its encrypted
+// form and fills in the blind index.
+func sealAuth(ctx context.Context, fieldEncryption services.IFieldEncryptionService, auth *entities.Auth) error {
+	plaintext, err := fieldEncryption.DecryptField(ctx, auth.IdentifierValue)
+	if err != nil {
+		return fmt.Errorf("failed to read identifier value: %w", err)
+	}
+
+	index := identifierIndex(fieldEncryption, int(auth.IdentifierType), plaintext)
+	auth.IdentifierIndex = &index
+
+	if !fieldEncryption.IsEncrypted(auth.IdentifierValue) {
+		if auth.IdentifierValue, err = fieldEncryption.EncryptField(ctx, plaintext); err != nil {
+			return fmt.Errorf("failed to encrypt identifier value: %w", err)
+		}
+	}
+
+	if !fieldEncryption.IsEncrypted(auth.UserInfo.Name) {
+		if auth.UserInfo.Name, err = fieldEncryption.EncryptField(ctx, auth.UserInfo.Name); err != nil {
+			return fmt.Errorf("failed to encrypt user name: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func openAuth(ctx context.Context, fieldEncryption services.IFieldEncryptionService, auth *entities.Auth) error {
+	var err error
+
+	if auth.IdentifierValue, err = fieldEncryption.DecryptField(ctx, auth.IdentifierValue); err != nil {
+		return fmt.Errorf("failed to decrypt identifier value: %w", err)
+	}
+	if auth.UserInfo.Name, err = fieldEncryption.DecryptField(ctx, auth.UserInfo.Name); err != nil {
+		return fmt.Errorf("failed to decrypt user name: %w", err)
+	}
+
+	return nil
+}
+
+// MigratePII encrypts the personal data of rows written before field
+// encryption was introduced, batchSize rows per transaction. It is
+// idempotent and can run while the service is serving traffic, since rows
+// are readable both before and after they are migrated. It returns the
+// number of auth and user info rows it changed.
+func MigratePII(ctx context.Context, db *gorm.DB, fieldEncryption services.IFieldEncryptionService, batchSize int) (int, int, error) {
+	auths, err := migrateAuthIdentifiers(ctx, db, fieldEncryption, batchSize)
+	if err != nil {
+		return auths, 0, err
+	}
+
+	userInfos, err := migrateUserNames(ctx, db, fieldEncryption, batchSize)
+	return auths, userInfos, err
+}
+
+func migrateAuthIdentifiers(ctx context.Context, db *gorm.DB, fieldEncryption services.IFieldEncryptionService, batchSize int) (int, error) {
+	migrated := 0
+
+	for {
+		var batch []entities.Auth
+		if err := db.WithContext(ctx).
+			Where("identifier_index IS NULL").
+			Limit(batchSize).
+			Find(&batch).Error; err != nil {
+			return migrated, fmt.Errorf("failed to load auths to migrate: %w", err)
+		}
+		if len(batch) == 0 {
+			return migrated, nil
+		}
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for i := range batch {
+				auth := &batch[i]
+				if err := sealAuth(ctx, fieldEncryption, auth); err != nil {
+					return err
+				}
+
+				if err := tx.Model(&entities.Auth{}).
+					Where("id = ?", auth.ID).
+					Updates(map[string]interface{}{
+						"identifier_value": auth.IdentifierValue,
+						"identifier_index": auth.IdentifierIndex,
+					}).Error; err != nil {
+					return fmt.Errorf("failed to update auth %s: %w", auth.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return migrated, err
+		}
+
+		migrated += len(batch)
+	}
+}
+
+// migrateUserNames walks user infos in user_id order, since whether a name
+// is encrypted can only be told by the field encryption service.
+func migrateUserNames(ctx context.Context, db *gorm.DB, fieldEncryption services.IFieldEncryptionService, batchSize int) (int, error) {
+	migrated := 0
+	lastUserID := ""
+
+	for {
+		var batch []entities.UserInfo
+		if err := db.WithContext(ctx).
+			Where("user_id > ?", lastUserID).
+			Order("user_id ASC").
+			Limit(batchSize).
+			Find(&batch).Error; err != nil {
+			return migrated, fmt.Errorf("failed to load user infos to migrate: %w", err)
+		}
+		if len(batch) == 0 {
+			return migrated, nil
+		}
+		lastUserID = batch[len(batch)-1].UserID
+
+		changed := 0
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, userInfo := range batch {
+				if userInfo.Name == "" || fieldEncryption.IsEncrypted(userInfo.Name) {
+					continue
+				}
+
+				name, err := fieldEncryption.EncryptField(ctx, userInfo.Name)
+				if err != nil {
+					return fmt.Errorf("failed to encrypt name of user %s: %w", userInfo.UserID, err)
+				}
+
+				if err := tx.Model(&entities.UserInfo{}).
+					Where("user_id = ?", userInfo.UserID).
+					Update("name", name).Error; err != nil {
+					return fmt.Errorf("failed to update user info %s: %w", userInfo.UserID, err)
+				}
+				changed++
+			}
+			return nil
+		})
+		if err != nil {
+			return migrated, err
+		}
+
+		migrated += changed
+	}
+}
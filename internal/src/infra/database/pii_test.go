@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens a private in-memory SQLite database with the auth tables.
+// The queries used here are plain SQL that SQLite runs as Postgres does.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.AutoMigrate(&entities.Auth{}, &entities.UserInfo{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+func newTestFieldEncryption() services.IFieldEncryptionService {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	return adapters.NewFieldEncryptionService(
+		config.PIIConfig{DataKey: key},
+		adapters.NewKeyManagementService(config.KMSConfig{MasterKey: key}),
+	)
+}
+
+// insertPlaintextUser writes a user the way versions before field
+// encryption did: identifier and name in the clear and no blind index.
+func insertPlaintextUser(t *testing.T, db *gorm.DB, id, identifier, name string) {
+	t.Helper()
+
+	// IdentifierType values are int32, which only the Postgres driver
+	// takes, so the row is written in SQL.
+	if err := db.Exec(
+		"INSERT INTO auths (id, identifier_type, identifier_value, password, wrong_attempts, max_wrong_attempts, max_token_age_seconds) VALUES (?, 1, ?, 'hash', 0, 5, 3600)",
+		id, identifier,
+	).Error; err != nil {
+		t.Fatalf("insert auth error = %v", err)
+	}
+	if err := db.Create(&entities.UserInfo{UserID: "user-" + id, AuthID: id, Name: name}).Error; err != nil {
+		t.Fatalf("Create(user info) error = %v", err)
+	}
+}
+
+func TestMigratePII(t *testing.T) {
+	db := newTestDB(t)
+	fieldEncryption := newTestFieldEncryption()
+	ctx := context.Background()
+
+	insertPlaintextUser(t, db, "a1", "ada@example.com", "Ada")
+	insertPlaintextUser(t, db, "a2", "alan@example.com", "Alan")
+	insertPlaintextUser(t, db, "a3", "grace@example.com", "")
+
+	auths, userInfos, err := MigratePII(ctx, db, fieldEncryption, 2)
+	if err != nil {
+		t.Fatalf("MigratePII() error = %v", err)
+	}
+	if auths != 3 || userInfos != 2 {
+		t.Errorf("MigratePII() migrated %d auths and %d user infos, want 3 and 2", auths, userInfos)
+	}
+
+	var stored []entities.Auth
+	if err := db.Preload("UserInfo").Order("id").Find(&stored).Error; err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	want := map[string][2]string{"a1": {"ada@example.com", "Ada"}, "a2": {"alan@example.com", "Alan"}, "a3": {"grace@example.com", ""}}
+	for _, auth := range stored {
+		if !fieldEncryption.IsEncrypted(auth.IdentifierValue) {
+			t.Errorf("auth %s identifier = %q, want it encrypted", auth.ID, auth.IdentifierValue)
+		}
+		if auth.UserInfo.Name != "" && !fieldEncryption.IsEncrypted(auth.UserInfo.Name) {
+			t.Errorf("auth %s name = %q, want it encrypted", auth.ID, auth.UserInfo.Name)
+		}
+		wantIndex := identifierIndex(fieldEncryption, 1, want[auth.ID][0])
+		if auth.IdentifierIndex == nil || *auth.IdentifierIndex != wantIndex {
+			t.Errorf("auth %s index = %v, want %s", auth.ID, auth.IdentifierIndex, wantIndex)
+		}
+
+		if err := openAuth(ctx, fieldEncryption, &auth); err != nil {
+			t.Fatalf("openAuth() error = %v", err)
+		}
+		if got := [2]string{auth.IdentifierValue, auth.UserInfo.Name}; got != want[auth.ID] {
+			t.Errorf("auth %s opens to %v, want %v", auth.ID, got, want[auth.ID])
+		}
+	}
+
+	// Migrated rows are left alone on a second run.
+	if auths, userInfos, err := MigratePII(ctx, db, fieldEncryption, 2); err != nil || auths != 0 || userInfos != 0 {
+		t.Errorf("second MigratePII() = %d, %d, %v, want nothing to migrate", auths, userInfos, err)
+	}
+}
+
+func TestGetByIdentifierBeforeAndAfterMigration(t *testing.T) {
+	db := newTestDB(t)
+	fieldEncryption := newTestFieldEncryption()
+	repo := NewAuthRepository(db, fieldEncryption)
+	ctx := context.Background()
+
+	insertPlaintextUser(t, db, "a1", "ada@example.com", "Ada")
+
+	find := func() {
+		t.Helper()
+		auth, err := repo.GetByIdentifier(ctx, 1, "ada@example.com")
+		if err != nil {
+			t.Fatalf("GetByIdentifier() error = %v", err)
+		}
+		if auth.GetIdentifierValue() != "ada@example.com" || auth.GetUserInfo().GetName() != "Ada" {
+			t.Errorf("GetByIdentifier() = %s %q, want ada@example.com \"Ada\"", auth.GetIdentifierValue(), auth.GetUserInfo().GetName())
+		}
+	}
+
+	find()
+	if _, _, err := MigratePII(ctx, db, fieldEncryption, 10); err != nil {
+		t.Fatalf("MigratePII() error = %v", err)
+	}
+	find()
+
+	if _, err := repo.GetByIdentifier(ctx, 2, "ada@example.com"); err == nil {
+		t.Error("GetByIdentifier() found the identifier under another type")
+	}
+}
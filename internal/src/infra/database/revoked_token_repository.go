@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) repositories.IRevokedTokenRepository {
+	return &revokedTokenRepository{
+		db: db,
+	}
+}
+
+func (r *revokedTokenRepository) Save(ctx context.Context, token models.RevokedToken) error {
+	tokenEntity := mappers.RevokedTokenDomainToModel(token)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A revocation only matters until the token expires by itself, so
+		// stale entries are swept on write like authorization codes.
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&entities.RevokedToken{}).Error; err != nil {
+			return fmt.Errorf("failed to purge expired revocations: %w", err)
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tokenEntity).Error; err != nil {
+			return fmt.Errorf("failed to save revoked token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, tokenIDs ...string) (bool, error) {
+	if len(tokenIDs) == 0 {
+		return false, nil
+	}
+
+	var count int64
+
+	if err := r.db.WithContext(ctx).
+		Model(&entities.RevokedToken{}).
+		Where("token_id IN ?", tokenIDs).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("database error in IsRevoked: %w", err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"gorm.io/gorm"
+)
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) repositories.ISigningKeyRepository {
+	return &signingKeyRepository{
+		db: db,
+	}
+}
+
+func (r *signingKeyRepository) Save(ctx context.Context, key models.SigningKey) error {
+	keyEntity := mappers.SigningKeyDomainToModel(key)
+
+	if err := r.db.WithContext(ctx).Save(&keyEntity).Error; err != nil {
+		return fmt.Errorf("failed to save signing key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *signingKeyRepository) List(ctx context.Context) ([]models.SigningKey, error) {
+	var keyEntities []entities.SigningKey
+
+	if err := r.db.WithContext(ctx).
+		Order("activates_at ASC").
+		Find(&keyEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in List: %w", err)
+	}
+
+	keys := make([]models.SigningKey, 0, len(keyEntities))
+	for _, keyEntity := range keyEntities {
+		key, err := mappers.SigningKeyModelToDomain(keyEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *signingKeyRepository) Delete(ctx context.Context, keyID string) error {
+	if err := r.db.WithContext(ctx).
+		Where("key_id = ?", keyID).
+		Delete(&entities.SigningKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+
+	return nil
+}
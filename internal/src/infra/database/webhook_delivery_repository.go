@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) repositories.IWebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery models.WebhookDelivery) error {
+	deliveryEntity := mappers.WebhookDeliveryDomainToModel(delivery)
+
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(&deliveryEntity).Error; err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Save stores the outcome of an attempt and releases the lease.
+func (r *webhookDeliveryRepository) Save(ctx context.Context, delivery models.WebhookDelivery) error {
+	deliveryEntity := mappers.WebhookDeliveryDomainToModel(delivery)
+
+	if err := r.db.WithContext(ctx).Save(&deliveryEntity).Error; err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, id string) (models.WebhookDelivery, error) {
+	var deliveryEntity entities.WebhookDelivery
+
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&deliveryEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("webhook delivery not found: %s", id))
+		}
+		return nil, fmt.Errorf("database error in GetByID: %w", err)
+	}
+
+	delivery, err := mappers.WebhookDeliveryModelToDomain(deliveryEntity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	now := time.Now()
+
+	var deliveryEntities []entities.WebhookDelivery
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET locked_until = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		leaseUntil, string(models.WebhookDeliveryPending), now, now, limit,
+	).Scan(&deliveryEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in Claim: %w", err)
+	}
+
+	return toWebhookDeliveries(deliveryEntities)
+}
+
+func (r *webhookDeliveryRepository) Query(ctx context.Context, query repositories.WebhookDeliveryQuery) ([]models.WebhookDelivery, error) {
+	tx := r.db.WithContext(ctx).
+		Model(&entities.WebhookDelivery{}).
+		Where("subscription_id = ?", query.SubscriptionID)
+
+	if query.Status != "" {
+		tx = tx.Where("status = ?", string(query.Status))
+	}
+	if query.After != nil {
+		tx = tx.Where("(created_at, id) < (?, ?)", query.After.CreatedAt, query.After.ID)
+	}
+
+	var deliveryEntities []entities.WebhookDelivery
+	if err := tx.
+		Order("created_at DESC, id DESC").
+		Limit(query.Limit).
+		Find(&deliveryEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in Query: %w", err)
+	}
+
+	return toWebhookDeliveries(deliveryEntities)
+}
+
+func toWebhookDeliveries(deliveryEntities []entities.WebhookDelivery) ([]models.WebhookDelivery, error) {
+	deliveries := make([]models.WebhookDelivery, 0, len(deliveryEntities))
+	for _, deliveryEntity := range deliveryEntities {
+		delivery, err := mappers.WebhookDeliveryModelToDomain(deliveryEntity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
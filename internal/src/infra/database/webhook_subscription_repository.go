@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/mappers"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+	"gorm.io/gorm"
+)
+
+type webhookSubscriptionRepository struct {
+	db              *gorm.DB
+	fieldEncryption services.IFieldEncryptionService
+}
+
+// NewWebhookSubscriptionRepository stores subscription secrets encrypted;
+// they have to be readable again to sign deliveries.
+func NewWebhookSubscriptionRepository(db *gorm.DB, fieldEncryption services.IFieldEncryptionService) repositories.IWebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		db:              db,
+		fieldEncryption: fieldEncryption,
+	}
+}
+
+func (r *webhookSubscriptionRepository) Save(ctx context.Context, subscription models.WebhookSubscription) error {
+	subscriptionEntity := mappers.WebhookSubscriptionDomainToModel(subscription)
+
+	secret, err := r.fieldEncryption.EncryptField(ctx, subscriptionEntity.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	subscriptionEntity.Secret = secret
+
+	if err := r.db.WithContext(ctx).Save(&subscriptionEntity).Error; err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, id string) (models.WebhookSubscription, error) {
+	var subscriptionEntity entities.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&subscriptionEntity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("webhook subscription not found: %s", id))
+		}
+		return nil, fmt.Errorf("database error in GetByID: %w", err)
+	}
+
+	return r.toDomain(ctx, subscriptionEntity)
+}
+
+func (r *webhookSubscriptionRepository) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subscriptionEntities []entities.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).
+		Order("created_at ASC").
+		Find(&subscriptionEntities).Error; err != nil {
+		return nil, fmt.Errorf("database error in List: %w", err)
+	}
+
+	subscriptions := make([]models.WebhookSubscription, 0, len(subscriptionEntities))
+	for _, subscriptionEntity := range subscriptionEntities {
+		subscription, err := r.toDomain(ctx, subscriptionEntity)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&entities.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+
+		result := tx.Where("id = ?", id).Delete(&entities.WebhookSubscription{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return exceptions.NewRepositoryNoDataFoundException(
+				fmt.Sprintf("webhook subscription not found: %s", id))
+		}
+
+		return nil
+	})
+}
+
+func (r *webhookSubscriptionRepository) toDomain(ctx context.Context, subscriptionEntity entities.WebhookSubscription) (models.WebhookSubscription, error) {
+	secret, err := r.fieldEncryption.DecryptField(ctx, subscriptionEntity.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	subscriptionEntity.Secret = secret
+
+	subscription, err := mappers.WebhookSubscriptionModelToDomain(subscriptionEntity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert entity to domain: %w", err)
+	}
+
+	return subscription, nil
+}
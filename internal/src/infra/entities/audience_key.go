@@ -0,0 +1,13 @@
+package entities
+
+import (
+	"time"
+)
+
+type AudienceKey struct {
+	Audience  string    `gorm:"primaryKey;column:audience"`
+	KeyID     string    `gorm:"not null;column:key_id"`
+	Algorithm string    `gorm:"not null"`
+	PublicJWK string    `gorm:"type:text;not null;column:public_jwk"`
+	CreatedAt time.Time `gorm:"not null"`
+}
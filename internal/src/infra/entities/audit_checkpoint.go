@@ -0,0 +1,14 @@
+package entities
+
+import (
+	"time"
+)
+
+type AuditCheckpoint struct {
+	Sequence  int64     `gorm:"primaryKey;autoIncrement:false"`
+	Hash      string    `gorm:"not null"`
+	KeyID     string    `gorm:"column:key_id;not null"`
+	Signature string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	Anchor    bool      `gorm:"not null;default:false"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+)
+
+type AuditEvent struct {
+	ID         string    `gorm:"primaryKey;type:uuid"`
+	Type       string    `gorm:"not null;index"`
+	ActorID    string    `gorm:"index"`
+	SubjectID  string    `gorm:"index"`
+	ClientID   string    `gorm:"default:null"`
+	Outcome    string    `gorm:"not null"`
+	Reason     string    `gorm:"default:null"`
+	PeerIP     string    `gorm:"column:peer_ip;default:null"`
+	UserAgent  string    `gorm:"default:null"`
+	Details    JSONMap   `gorm:"type:jsonb;default:'{}'"`
+	OccurredAt time.Time `gorm:"not null;index"`
+
+	// Events recorded before the log was chained have no sequence. Pending
+	// events are stored but not chained yet.
+	Sequence *int64 `gorm:"uniqueIndex"`
+	PrevHash string `gorm:"default:null"`
+	Hash     string `gorm:"default:null"`
+	Pending  bool   `gorm:"not null;default:false;index:idx_audit_events_pending,where:pending"`
+}
@@ -8,6 +8,7 @@ type Auth struct {
 	ID                 string                `gorm:"primaryKey;type:uuid"`
 	IdentifierType     IdentifierType `gorm:"type:int;not null"`
 	IdentifierValue    string                `gorm:"not null"`
+	IdentifierIndex    *string               `gorm:"index;default:null"`
 	Password           string                `gorm:"not null"`
 	UserInfo           UserInfo       `gorm:"foreignKey:AuthID;references:ID"`
 	EncryptToken       bool                  `gorm:"default:false"`
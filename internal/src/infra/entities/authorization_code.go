@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+)
+
+type AuthorizationCode struct {
+	CodeHash            string     `gorm:"primaryKey;column:code_hash"`
+	ClientID            string     `gorm:"not null;index"`
+	UserID              string     `gorm:"not null"`
+	RedirectURI         string     `gorm:"not null"`
+	CodeChallenge       string     `gorm:"not null"`
+	CodeChallengeMethod string     `gorm:"not null"`
+	Scope               string     `gorm:"default:''"`
+	Nonce               string     `gorm:"default:''"`
+	AuthTime            time.Time  `gorm:"not null"`
+	ExpiresAt           time.Time  `gorm:"not null;index"`
+	CreatedAt           *time.Time `gorm:"autoCreateTime"`
+}
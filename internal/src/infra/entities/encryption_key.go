@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+)
+
+type EncryptionKey struct {
+	KeyID           string     `gorm:"primaryKey;column:key_id"`
+	Algorithm       string     `gorm:"not null"`
+	WrappedMaterial []byte     `gorm:"type:bytea;not null"`
+	State           string     `gorm:"not null"`
+	ActivatesAt     time.Time  `gorm:"not null"`
+	ExpiresAt       *time.Time `gorm:"default:null"`
+	RemoveAt        *time.Time `gorm:"default:null"`
+	LastUsedAt      *time.Time `gorm:"default:null"`
+	CreatedAt       time.Time  `gorm:"not null"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONMap stores a free-form JSON object in a jsonb column.
+type JSONMap map[string]interface{}
+
+func (d JSONMap) Value() (driver.Value, error) {
+	if d == nil {
+		return "{}", nil
+	}
+
+	encoded, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (d *JSONMap) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case nil:
+		*d = JSONMap{}
+		return nil
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONMap", src)
+	}
+
+	decoded := JSONMap{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return err
+	}
+	*d = decoded
+	return nil
+}
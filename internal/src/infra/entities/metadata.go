@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata stores a string map in a jsonb column.
+type Metadata map[string]string
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (m *Metadata) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into Metadata", src)
+	}
+
+	decoded := Metadata{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type OAuthClient struct {
+	ClientID      string         `gorm:"primaryKey;column:client_id"`
+	Name          string         `gorm:"not null"`
+	SecretHash    *string        `gorm:"default:null"`
+	RedirectURIs  pq.StringArray `gorm:"type:text[];column:redirect_uris"`
+	Public        bool           `gorm:"default:false"`
+	EncryptTokens bool           `gorm:"default:false"`
+	CreatedAt     *time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt     *time.Time     `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+)
+
+type OutboxMessage struct {
+	ID string `gorm:"primaryKey;type:uuid"`
+	// Sequence is assigned by the database and orders a user's messages.
+	Sequence      int64      `gorm:"type:bigserial;not null;uniqueIndex;<-:false"`
+	EventType     string     `gorm:"not null"`
+	UserID        string     `gorm:"not null;index"`
+	Data          JSONMap    `gorm:"type:jsonb;default:'{}'"`
+	OccurredAt    time.Time  `gorm:"not null"`
+	Attempts      int        `gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time `gorm:"default:null"`
+	LastError     string     `gorm:"default:null"`
+	PublishedAt   *time.Time `gorm:"index"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+import (
+	"time"
+)
+
+type RevokedToken struct {
+	TokenID   string    `gorm:"primaryKey;column:token_id"`
+	UserID    string    `gorm:"default:'';index"`
+	RevokedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
@@ -5,8 +5,9 @@ import (
 )
 
 type UserInfo struct {
-	UserID  string         `gorm:"primaryKey;column:user_id" json:"user_id"`
-	Name    string         `gorm:"column:name" json:"name"`
-	AuthID  string         `gorm:"column:auth_id" json:"auth_id"`
-	Roles   pq.StringArray `gorm:"type:text[];column:roles" json:"roles"`
-}
\ No newline at end of file
+	UserID   string         `gorm:"primaryKey;column:user_id" json:"user_id"`
+	Name     string         `gorm:"column:name" json:"name"`
+	AuthID   string         `gorm:"column:auth_id" json:"auth_id"`
+	Roles    pq.StringArray `gorm:"type:text[];column:roles" json:"roles"`
+	Metadata Metadata       `gorm:"type:jsonb;column:metadata;default:'{}'" json:"metadata"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+)
+
+type WebhookDelivery struct {
+	ID             string     `gorm:"primaryKey;type:uuid"`
+	SubscriptionID string     `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string     `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string     `gorm:"not null"`
+	Payload        []byte     `gorm:"type:bytea;not null"`
+	Status         string     `gorm:"not null;index"`
+	Attempts       int        `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"not null;index"`
+	LockedUntil    *time.Time `gorm:"default:null"`
+	LastStatusCode int        `gorm:"default:0"`
+	LastError      string     `gorm:"default:null"`
+	CreatedAt      time.Time  `gorm:"not null;index"`
+	DeliveredAt    *time.Time `gorm:"default:null"`
+}
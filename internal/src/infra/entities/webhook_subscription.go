@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookSubscription struct {
+	ID          string         `gorm:"primaryKey;type:uuid"`
+	URL         string         `gorm:"column:url;not null"`
+	EventTypes  pq.StringArray `gorm:"type:text[]"`
+	Secret      string         `gorm:"not null"`
+	MaxAttempts int            `gorm:"not null"`
+	CreatedAt   time.Time      `gorm:"not null"`
+}
@@ -0,0 +1,31 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func AudienceKeyModelToDomain(entity entities.AudienceKey) (models.AudienceKey, error) {
+	domain, err := models.LoadAudienceKey(models.AudienceKeyProps{
+		Audience:  entity.Audience,
+		KeyID:     entity.KeyID,
+		Algorithm: entity.Algorithm,
+		PublicJWK: entity.PublicJWK,
+		CreatedAt: entity.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func AudienceKeyDomainToModel(domain models.AudienceKey) entities.AudienceKey {
+	return entities.AudienceKey{
+		Audience:  domain.GetAudience(),
+		KeyID:     domain.GetKeyID(),
+		Algorithm: domain.GetAlgorithm(),
+		PublicJWK: domain.GetPublicJWK(),
+		CreatedAt: domain.GetCreatedAt(),
+	}
+}
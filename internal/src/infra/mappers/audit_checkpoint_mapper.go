@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func AuditCheckpointModelToDomain(entity entities.AuditCheckpoint) (models.AuditCheckpoint, error) {
+	domain, err := models.LoadAuditCheckpoint(models.AuditCheckpointProps{
+		Sequence:  entity.Sequence,
+		Hash:      entity.Hash,
+		KeyID:     entity.KeyID,
+		Signature: entity.Signature,
+		CreatedAt: entity.CreatedAt,
+		Anchor:    entity.Anchor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func AuditCheckpointDomainToModel(domain models.AuditCheckpoint) entities.AuditCheckpoint {
+	return entities.AuditCheckpoint{
+		Sequence:  domain.GetSequence(),
+		Hash:      domain.GetHash(),
+		KeyID:     domain.GetKeyID(),
+		Signature: domain.GetSignature(),
+		CreatedAt: domain.GetCreatedAt(),
+		Anchor:    domain.IsAnchor(),
+	}
+}
@@ -0,0 +1,60 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func AuditEventModelToDomain(entity entities.AuditEvent) (models.AuditEvent, error) {
+	var sequence int64
+	if entity.Sequence != nil {
+		sequence = *entity.Sequence
+	}
+
+	domain, err := models.LoadAuditEvent(models.AuditEventProps{
+		ID:         entity.ID,
+		Type:       entity.Type,
+		ActorID:    entity.ActorID,
+		SubjectID:  entity.SubjectID,
+		ClientID:   entity.ClientID,
+		Outcome:    entity.Outcome,
+		Reason:     entity.Reason,
+		PeerIP:     entity.PeerIP,
+		UserAgent:  entity.UserAgent,
+		Details:    entity.Details,
+		OccurredAt: entity.OccurredAt,
+		Sequence:   sequence,
+		PrevHash:   entity.PrevHash,
+		Hash:       entity.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func AuditEventDomainToModel(domain models.AuditEvent) entities.AuditEvent {
+	var sequence *int64
+	if domain.GetSequence() > 0 {
+		value := domain.GetSequence()
+		sequence = &value
+	}
+
+	return entities.AuditEvent{
+		ID:         domain.GetID(),
+		Type:       domain.GetType(),
+		ActorID:    domain.GetActorID(),
+		SubjectID:  domain.GetSubjectID(),
+		ClientID:   domain.GetClientID(),
+		Outcome:    domain.GetOutcome(),
+		Reason:     domain.GetReason(),
+		PeerIP:     domain.GetPeerIP(),
+		UserAgent:  domain.GetUserAgent(),
+		Details:    domain.GetDetails(),
+		OccurredAt: domain.GetOccurredAt(),
+		Sequence:   sequence,
+		PrevHash:   domain.GetPrevHash(),
+		Hash:       domain.GetHash(),
+	}
+}
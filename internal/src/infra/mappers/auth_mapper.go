@@ -37,7 +37,7 @@ func DomainToModel(domain models.Auth) entities.Auth {
 		IdentifierType:     IdentifierTypeFromDomain(domain.GetIdentifierType()),
 		IdentifierValue:    domain.GetIdentifierValue(),
 		Password:           domain.GetPassword(),
-		UserInfo: userInfoDomainToModel(domain.GetUserInfo()),
+		UserInfo: UserInfoDomainToModel(domain.GetUserInfo()),
 		EncryptToken:       domain.GetEncryptToken(),
 		LastLoginAt:        domain.GetLastLoginAt(),
 		WrongAttempts:      domain.GetWrongAttempts(),
@@ -50,9 +50,10 @@ func DomainToModel(domain models.Auth) entities.Auth {
 func userInfoModelToDomain(entity entities.UserInfo) (models.UserInfo, error) {
 	model, err := models.NewUserInfo(
 		models.UserInfoProps{
-			UserID: entity.UserID,
-			Name:   entity.Name,
-			Roles:  entity.Roles,
+			UserID:   entity.UserID,
+			Name:     entity.Name,
+			Roles:    entity.Roles,
+			Metadata: entity.Metadata,
 		},
 	)
 	if err != nil {
@@ -62,10 +63,11 @@ func userInfoModelToDomain(entity entities.UserInfo) (models.UserInfo, error) {
 	return model, nil
 }
 
-func userInfoDomainToModel(domain models.UserInfo) entities.UserInfo {
+func UserInfoDomainToModel(domain models.UserInfo) entities.UserInfo {
 	return entities.UserInfo{
-		UserID: domain.GetUserID(),
-		Name:   domain.GetName(),
-		Roles:  domain.GetRoles(),
+		UserID:   domain.GetUserID(),
+		Name:     domain.GetName(),
+		Roles:    domain.GetRoles(),
+		Metadata: domain.GetMetadata(),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func AuthorizationCodeModelToDomain(entity entities.AuthorizationCode) (models.AuthorizationCode, error) {
+	domain, err := models.LoadAuthorizationCode(models.AuthorizationCodeProps{
+		CodeHash:            entity.CodeHash,
+		ClientID:            entity.ClientID,
+		UserID:              entity.UserID,
+		RedirectURI:         entity.RedirectURI,
+		CodeChallenge:       entity.CodeChallenge,
+		CodeChallengeMethod: entity.CodeChallengeMethod,
+		Scope:               entity.Scope,
+		Nonce:               entity.Nonce,
+		AuthTime:            entity.AuthTime,
+		ExpiresAt:           entity.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func AuthorizationCodeDomainToModel(domain models.AuthorizationCode) entities.AuthorizationCode {
+	return entities.AuthorizationCode{
+		CodeHash:            domain.GetCodeHash(),
+		ClientID:            domain.GetClientID(),
+		UserID:              domain.GetUserID(),
+		RedirectURI:         domain.GetRedirectURI(),
+		CodeChallenge:       domain.GetCodeChallenge(),
+		CodeChallengeMethod: domain.GetCodeChallengeMethod(),
+		Scope:               domain.GetScope(),
+		Nonce:               domain.GetNonce(),
+		AuthTime:            domain.GetAuthTime(),
+		ExpiresAt:           domain.GetExpiresAt(),
+	}
+}
@@ -0,0 +1,39 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func EncryptionKeyModelToDomain(entity entities.EncryptionKey) (models.EncryptionKey, error) {
+	domain, err := models.LoadEncryptionKey(models.EncryptionKeyProps{
+		KeyID:           entity.KeyID,
+		Algorithm:       entity.Algorithm,
+		WrappedMaterial: entity.WrappedMaterial,
+		State:           models.EncryptionKeyState(entity.State),
+		ActivatesAt:     entity.ActivatesAt,
+		ExpiresAt:       entity.ExpiresAt,
+		RemoveAt:        entity.RemoveAt,
+		LastUsedAt:      entity.LastUsedAt,
+		CreatedAt:       entity.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func EncryptionKeyDomainToModel(domain models.EncryptionKey) entities.EncryptionKey {
+	return entities.EncryptionKey{
+		KeyID:           domain.GetKeyID(),
+		Algorithm:       domain.GetAlgorithm(),
+		WrappedMaterial: domain.GetWrappedMaterial(),
+		State:           string(domain.GetState()),
+		ActivatesAt:     domain.GetActivatesAt(),
+		ExpiresAt:       domain.GetExpiresAt(),
+		RemoveAt:        domain.GetRemoveAt(),
+		LastUsedAt:      domain.GetLastUsedAt(),
+		CreatedAt:       domain.GetCreatedAt(),
+	}
+}
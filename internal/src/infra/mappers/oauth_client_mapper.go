@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func OAuthClientModelToDomain(entity entities.OAuthClient) (models.OAuthClient, error) {
+	domain, err := models.LoadOAuthClient(models.OAuthClientProps{
+		ClientID:      entity.ClientID,
+		Name:          entity.Name,
+		SecretHash:    entity.SecretHash,
+		RedirectURIs:  entity.RedirectURIs,
+		Public:        entity.Public,
+		EncryptTokens: entity.EncryptTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func OAuthClientDomainToModel(domain models.OAuthClient) entities.OAuthClient {
+	return entities.OAuthClient{
+		ClientID:      domain.GetClientID(),
+		Name:          domain.GetName(),
+		SecretHash:    domain.GetSecretHash(),
+		RedirectURIs:  domain.GetRedirectURIs(),
+		Public:        domain.IsPublic(),
+		EncryptTokens: domain.GetEncryptTokens(),
+	}
+}
@@ -0,0 +1,44 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func OutboxMessageModelToDomain(entity entities.OutboxMessage) (models.OutboxMessage, error) {
+	event, err := models.LoadDomainEvent(models.DomainEventProps{
+		ID:         entity.ID,
+		Type:       entity.EventType,
+		UserID:     entity.UserID,
+		Data:       entity.Data,
+		OccurredAt: entity.OccurredAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := models.LoadOutboxMessage(models.OutboxMessageProps{
+		Event:       event,
+		Attempts:    entity.Attempts,
+		LastError:   entity.LastError,
+		PublishedAt: entity.PublishedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// DomainEventToOutboxModel builds the outbox row of a new event, due for
+// delivery right away.
+func DomainEventToOutboxModel(event models.DomainEvent) entities.OutboxMessage {
+	return entities.OutboxMessage{
+		ID:            event.GetID(),
+		EventType:     event.GetType(),
+		UserID:        event.GetUserID(),
+		Data:          event.GetData(),
+		OccurredAt:    event.GetOccurredAt(),
+		NextAttemptAt: event.GetOccurredAt(),
+	}
+}
@@ -0,0 +1,29 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func RevokedTokenModelToDomain(entity entities.RevokedToken) (models.RevokedToken, error) {
+	domain, err := models.LoadRevokedToken(models.RevokedTokenProps{
+		TokenID:   entity.TokenID,
+		UserID:    entity.UserID,
+		RevokedAt: entity.RevokedAt,
+		ExpiresAt: entity.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func RevokedTokenDomainToModel(domain models.RevokedToken) entities.RevokedToken {
+	return entities.RevokedToken{
+		TokenID:   domain.GetTokenID(),
+		UserID:    domain.GetUserID(),
+		RevokedAt: domain.GetRevokedAt(),
+		ExpiresAt: domain.GetExpiresAt(),
+	}
+}
@@ -0,0 +1,39 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func SigningKeyModelToDomain(entity entities.SigningKey) (models.SigningKey, error) {
+	domain, err := models.LoadSigningKey(models.SigningKeyProps{
+		KeyID:           entity.KeyID,
+		Purpose:         models.SigningKeyPurpose(entity.Purpose),
+		Algorithm:       entity.Algorithm,
+		WrappedMaterial: entity.WrappedMaterial,
+		State:           models.SigningKeyState(entity.State),
+		ActivatesAt:     entity.ActivatesAt,
+		ExpiresAt:       entity.ExpiresAt,
+		RemoveAt:        entity.RemoveAt,
+		CreatedAt:       entity.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func SigningKeyDomainToModel(domain models.SigningKey) entities.SigningKey {
+	return entities.SigningKey{
+		KeyID:           domain.GetKeyID(),
+		Purpose:         string(domain.GetPurpose()),
+		Algorithm:       domain.GetAlgorithm(),
+		WrappedMaterial: domain.GetWrappedMaterial(),
+		State:           string(domain.GetState()),
+		ActivatesAt:     domain.GetActivatesAt(),
+		ExpiresAt:       domain.GetExpiresAt(),
+		RemoveAt:        domain.GetRemoveAt(),
+		CreatedAt:       domain.GetCreatedAt(),
+	}
+}
@@ -0,0 +1,45 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func WebhookDeliveryModelToDomain(entity entities.WebhookDelivery) (models.WebhookDelivery, error) {
+	domain, err := models.LoadWebhookDelivery(models.WebhookDeliveryProps{
+		ID:             entity.ID,
+		SubscriptionID: entity.SubscriptionID,
+		EventID:        entity.EventID,
+		EventType:      entity.EventType,
+		Payload:        entity.Payload,
+		Status:         models.WebhookDeliveryStatus(entity.Status),
+		Attempts:       entity.Attempts,
+		NextAttemptAt:  entity.NextAttemptAt,
+		LastStatusCode: entity.LastStatusCode,
+		LastError:      entity.LastError,
+		CreatedAt:      entity.CreatedAt,
+		DeliveredAt:    entity.DeliveredAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func WebhookDeliveryDomainToModel(domain models.WebhookDelivery) entities.WebhookDelivery {
+	return entities.WebhookDelivery{
+		ID:             domain.GetID(),
+		SubscriptionID: domain.GetSubscriptionID(),
+		EventID:        domain.GetEventID(),
+		EventType:      domain.GetEventType(),
+		Payload:        domain.GetPayload(),
+		Status:         string(domain.GetStatus()),
+		Attempts:       domain.GetAttempts(),
+		NextAttemptAt:  domain.GetNextAttemptAt(),
+		LastStatusCode: domain.GetLastStatusCode(),
+		LastError:      domain.GetLastError(),
+		CreatedAt:      domain.GetCreatedAt(),
+		DeliveredAt:    domain.GetDeliveredAt(),
+	}
+}
@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/entities"
+)
+
+func WebhookSubscriptionModelToDomain(entity entities.WebhookSubscription) (models.WebhookSubscription, error) {
+	domain, err := models.LoadWebhookSubscription(models.WebhookSubscriptionProps{
+		ID:          entity.ID,
+		URL:         entity.URL,
+		EventTypes:  entity.EventTypes,
+		Secret:      entity.Secret,
+		MaxAttempts: entity.MaxAttempts,
+		CreatedAt:   entity.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func WebhookSubscriptionDomainToModel(domain models.WebhookSubscription) entities.WebhookSubscription {
+	return entities.WebhookSubscription{
+		ID:          domain.GetID(),
+		URL:         domain.GetURL(),
+		EventTypes:  domain.GetEventTypes(),
+		Secret:      domain.GetSecret(),
+		MaxAttempts: domain.GetMaxAttempts(),
+		CreatedAt:   domain.GetCreatedAt(),
+	}
+}
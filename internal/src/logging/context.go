@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestFields are the fields added to every record logged while serving
+// a request. The user is learnt while the request runs, so it can be set
+// after the context was created.
+type requestFields struct {
+	requestID string
+	method    string
+
+	mu       sync.Mutex
+	userID   string
+	tenantID string
+}
+
+type requestFieldsKey struct{}
+
+// WithRequest starts the log fields of a request.
+func WithRequest(ctx context.Context, requestID, method string) context.Context {
+	return context.WithValue(ctx, requestFieldsKey{}, &requestFields{
+		requestID: requestID,
+		method:    method,
+	})
+}
+
+// SetUser names the user and tenant a request acts on. It does nothing
+// outside a request.
+func SetUser(ctx context.Context, userID, tenantID string) {
+	fields, ok := ctx.Value(requestFieldsKey{}).(*requestFields)
+	if !ok {
+		return
+	}
+
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+
+	if userID != "" {
+		fields.userID = userID
+	}
+	if tenantID != "" {
+		fields.tenantID = tenantID
+	}
+}
+
+// contextHandler adds the request fields and the trace of the context to
+// each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fields, ok := ctx.Value(requestFieldsKey{}).(*requestFields); ok {
+		record.AddAttrs(slog.String("request_id", fields.requestID), slog.String("method", fields.method))
+
+		fields.mu.Lock()
+		if fields.userID != "" {
+			record.AddAttrs(slog.String("user_id", fields.userID))
+		}
+		if fields.tenantID != "" {
+			record.AddAttrs(slog.String("tenant_id", fields.tenantID))
+		}
+		fields.mu.Unlock()
+	}
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
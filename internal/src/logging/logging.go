@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	clarchutils "github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+	"github.com/rs/zerolog"
+)
+
+// NewLogger builds the application logger at the configured level and in
+// the configured format, json or text. Every record is redacted and carries the request fields of its context.
+//
+// The logger also becomes the slog default, and the go-clarch use case
+// logger, which writes props and results verbatim, is silenced.
+func NewLogger(cfg config.LogConfig) (*slog.Logger, error) {
+	return newLogger(os.Stdout, cfg.Level, cfg.Format)
+}
+
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var leveler slog.Level
+	if level != "" {
+		if err := leveler.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", level)
+		}
+	}
+
+	options := &slog.HandlerOptions{
+		Level:       leveler,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT must be json or text, got %q", format)
+	}
+
+	logger := slog.New(contextHandler{handler})
+	slog.SetDefault(logger)
+	clarchutils.Logger = zerolog.Nop()
+
+	return logger, nil
+}
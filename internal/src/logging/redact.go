@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are parts of attribute names whose values are never logged.
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"cpf",
+	"cnpj",
+	"email",
+	"phone",
+	"identifier_value",
+}
+
+// sensitiveValues finds secrets and personal data inside free text, such as
+// error messages: JWTs and JWEs, bearer and basic credentials, e-mail
+// addresses and formatted CPFs, CNPJs and phone numbers.
+var sensitiveValues = regexp.MustCompile(strings.Join([]string{
+	`\beyJ[A-Za-z0-9_-]*(?:\.[A-Za-z0-9_-]*){2,4}`,
+	`(?i)\b(?:bearer|basic)\s+[A-Za-z0-9._~+/=-]+`,
+	`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	`\b\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}\b`,
+	`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`,
+	`(?:\+\d{1,3}\s?)?\(\d{2}\)\s?\d{4,5}-?\d{4}\b`,
+}, "|"))
+
+// digitRuns finds the bare CPFs, CNPJs and phone numbers identifiers are
+// stored as. Runs joined to a hyphen are left alone: they are parts of
+// UUIDs and dates.
+var digitRuns = regexp.MustCompile(`\+?\b\d{10,15}\b`)
+
+// Redact masks the secrets and personal data found in s.
+func Redact(s string) string {
+	s = sensitiveValues.ReplaceAllString(s, redacted)
+
+	var b strings.Builder
+	last := 0
+	for _, match := range digitRuns.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[1]
+		if (start > 0 && s[start-1] == '-') || (end < len(s) && s[end] == '-') {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(redacted)
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// redactAttr is the ReplaceAttr of the handlers: attributes with sensitive
+// names are dropped to a placeholder and text values, the message
+// included, are scrubbed.
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey) {
+		return attr
+	}
+
+	key := strings.ToLower(attr.Key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, Redact(value.Error()))
+		default:
+			return slog.String(attr.Key, Redact(fmt.Sprintf("%+v", value)))
+		}
+	}
+
+	return attr
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "user not found", want: "user not found"},
+		{name: "JWT", in: "bad token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", want: "bad token [REDACTED]"},
+		{name: "JWE", in: "eyJhbGciOiJSU0EtT0FFUC0yNTYifQ.a.b.c.d failed", want: "[REDACTED] failed"},
+		{name: "bearer", in: "Authorization: Bearer abc.def-123", want: "Authorization: [REDACTED]"},
+		{name: "basic", in: "basic Y2xpZW50OnNlY3JldA==", want: "[REDACTED]"},
+		{name: "email", in: "duplicate ada@example.com", want: "duplicate [REDACTED]"},
+		{name: "formatted CPF", in: "cpf 123.456.789-09 taken", want: "cpf [REDACTED] taken"},
+		{name: "formatted CNPJ", in: "cnpj 12.345.678/0001-95", want: "cnpj [REDACTED]"},
+		{name: "formatted phone", in: "phone +55 (11) 91234-5678", want: "phone [REDACTED]"},
+		{name: "bare CPF", in: "identifier 12345678909", want: "identifier [REDACTED]"},
+		{name: "bare phone", in: "identifier +5511912345678", want: "identifier [REDACTED]"},
+		{name: "UUID", in: "user 123e4567-e89b-12d3-a456-426614174000", want: "user 123e4567-e89b-12d3-a456-426614174000"},
+		{name: "short numbers", in: "retry 3 of 5 after 500ms", want: "retry 3 of 5 after 500ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerRedactsRecords(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	var out bytes.Buffer
+	logger, err := newLogger(&out, "info", "json")
+	if err != nil {
+		t.Fatalf("newLogger() error = %v", err)
+	}
+
+	logger.Info("login failed for ada@example.com",
+		"client_secret", "s3cr3t",
+		"RefreshToken", "opaque-value",
+		slog.Group("request", "password", "hunter2"),
+		"error", errors.New("token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln expired"),
+		"details", map[string]string{"email": "ada@example.com"},
+		"user_id", "user-1",
+		"attempts", 3,
+	)
+
+	line := out.String()
+	for _, leaked := range []string{"ada@example.com", "s3cr3t", "opaque-value", "hunter2", "eyJ"} {
+		if strings.Contains(line, leaked) {
+			t.Errorf("log line %s contains %q", line, leaked)
+		}
+	}
+	for _, kept := range []string{`"user_id":"user-1"`, `"attempts":3`, `"level":"INFO"`, `"time":`} {
+		if !strings.Contains(line, kept) {
+			t.Errorf("log line %s lacks %s", line, kept)
+		}
+	}
+}
+
+func TestNewLoggerRejectsUnknownSettings(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	if _, err := newLogger(&bytes.Buffer{}, "trace", "json"); err == nil {
+		t.Error("newLogger() accepted level trace")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("newLogger() accepted format xml")
+	}
+}
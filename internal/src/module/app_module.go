@@ -1,14 +1,21 @@
 package module
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/usecases"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/controller"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/repositories"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/database"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/database/connection"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/logging"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/server"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/worker"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
@@ -17,13 +24,90 @@ func Module() fx.Option {
 	return fx.Module(
 		"authgate.module.app",
 		fx.Provide(
-			connection.SetupConfig,
+			config.NewSections,
+			logging.NewLogger,
+			adapters.NewSpanExporter,
+			adapters.NewTracerProvider,
+			connection.NewDB,
 		),
 		fx.Provide(
 			fx.Annotate(
 				database.NewAuthRepository,
 				fx.As(new(repositories.IAuthRepository)),
 			),
+			fx.Annotate(
+				database.NewOAuthClientRepository,
+				fx.As(new(repositories.IOAuthClientRepository)),
+			),
+			fx.Annotate(
+				database.NewAuthorizationCodeRepository,
+				fx.As(new(repositories.IAuthorizationCodeRepository)),
+			),
+			fx.Annotate(
+				database.NewRevokedTokenRepository,
+				fx.As(new(repositories.IRevokedTokenRepository)),
+			),
+			fx.Annotate(
+				database.NewSigningKeyRepository,
+				fx.As(new(repositories.ISigningKeyRepository)),
+			),
+			fx.Annotate(
+				database.NewEncryptionKeyRepository,
+				fx.As(new(repositories.IEncryptionKeyRepository)),
+			),
+			fx.Annotate(
+				database.NewAudienceKeyRepository,
+				fx.As(new(repositories.IAudienceKeyRepository)),
+			),
+			fx.Annotate(
+				database.NewAuditEventRepository,
+				fx.As(new(repositories.IAuditEventRepository)),
+			),
+			fx.Annotate(
+				database.NewAuditCheckpointRepository,
+				fx.As(new(repositories.IAuditCheckpointRepository)),
+			),
+			fx.Annotate(
+				database.NewOutboxRepository,
+				fx.As(new(repositories.IOutboxRepository)),
+			),
+			fx.Annotate(
+				database.NewWebhookSubscriptionRepository,
+				fx.As(new(repositories.IWebhookSubscriptionRepository)),
+			),
+			fx.Annotate(
+				database.NewWebhookDeliveryRepository,
+				fx.As(new(repositories.IWebhookDeliveryRepository)),
+			),
+			fx.Annotate(
+				adapters.NewEventPublisher,
+				fx.As(new(services.IEventPublisher)),
+			),
+			fx.Annotate(
+				adapters.NewWebhookSender,
+				fx.As(new(services.IWebhookSender)),
+			),
+			adapters.NewMetricsRegistry,
+			fx.Annotate(
+				adapters.NewMetrics,
+				fx.As(new(services.IMetrics)),
+			),
+			fx.Annotate(
+				adapters.NewKeyManagementService,
+				fx.As(new(services.IKeyManagementService)),
+			),
+			fx.Annotate(
+				adapters.NewFieldEncryptionService,
+				fx.As(new(services.IFieldEncryptionService)),
+			),
+			fx.Annotate(
+				adapters.NewTokenExchangePolicy,
+				fx.As(new(services.ITokenExchangePolicy)),
+			),
+			fx.Annotate(
+				adapters.NewAuditService,
+				fx.As(new(services.IAuditService)),
+			),
 			fx.Annotate(
 				adapters.NewJWTService,
 				fx.As(new(services.IJWTService)),
@@ -32,25 +116,80 @@ func Module() fx.Option {
 				adapters.NewEncryptService,
 				fx.As(new(services.IEncryptService)),
 			),
+			usecases.NewTokenIssuer,
+			usecases.NewTokenVerifier,
+			usecases.NewWebhookDispatcher,
+			usecases.NewOutboxRelay,
+			usecases.NewAuthEventWatcher,
 			usecases.NewLoginUsecase,
 			usecases.NewRegisterUsecase,
 			usecases.NewVerifyTokenUsecase,
 			usecases.NewRefreshTokenUsecase,
 			usecases.NewDeleteAuthUsecase,
+			usecases.NewRegisterClientUsecase,
+			usecases.NewValidateAuthorizeUsecase,
+			usecases.NewAuthorizeUsecase,
+			usecases.NewTokenUsecase,
+			usecases.NewOIDCUserInfoUsecase,
+			usecases.NewOpenIDConfigurationUsecase,
+			usecases.NewGetJWKSUsecase,
+			usecases.NewRotateSigningKeyUsecase,
+			usecases.NewListSigningKeysUsecase,
+			usecases.NewIntrospectTokenUsecase,
+			usecases.NewRevokeTokenUsecase,
+			usecases.NewUpdateUserInfoUsecase,
+			usecases.NewRotateEncryptionKeyUsecase,
+			usecases.NewListEncryptionKeysUsecase,
+			usecases.NewSetAudienceKeyUsecase,
+			usecases.NewQueryAuditEventsUsecase,
+			usecases.NewVerifyAuditChainUsecase,
+			usecases.NewChangePasswordUsecase,
+			usecases.NewCreateWebhookSubscriptionUsecase,
+			usecases.NewListWebhookSubscriptionsUsecase,
+			usecases.NewDeleteWebhookSubscriptionUsecase,
+			usecases.NewListWebhookDeliveriesUsecase,
+			usecases.NewRetryWebhookDeliveryUsecase,
 			controller.NewController,
 		),
+		fx.Provide(func(logger *slog.Logger, db *gorm.DB, jwtService services.IJWTService, encryptService services.IEncryptService) *server.Health {
+			return server.NewHealth(logger,
+				server.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+					return connection.Ping(ctx, db)
+				}},
+				server.HealthCheck{Name: "signing_keys", Check: jwtService.CheckKeys},
+				server.HealthCheck{Name: "encryption_keys", Check: encryptService.CheckKeys},
+			)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, health *server.Health) {
+			lc.Append(fx.StartHook(health.Check))
+			worker.RunPeriodic(lc, logger, "health-check", 5*time.Second, health.Check)
+		}),
+		fx.Invoke(connection.RegisterPoolMetrics),
 		fx.Invoke(server.NewAuthServiceServer),
-		fx.Invoke(func(lc fx.Lifecycle, db *gorm.DB) {
-			lc.Append(fx.StopHook(func() {
-				sqlDb, err := db.DB()
-				if err != nil {
-					panic("failed to get database connection: " + err.Error())
-				}
-				
-				if err := sqlDb.Close(); err != nil {
-					panic("failed to close database connection: " + err.Error())
-				}
-			}))
+		fx.Invoke(server.NewHTTPServer),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, jwtService services.IJWTService) {
+			worker.RunPeriodic(lc, logger, "signing-key-maintenance", time.Minute, jwtService.MaintainSigningKeys)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, encryptService services.IEncryptService) {
+			worker.RunPeriodic(lc, logger, "encryption-key-maintenance", time.Minute, encryptService.MaintainEncryptionKeys)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, auditService services.IAuditService) {
+			worker.RunPeriodic(lc, logger, "audit-seal", time.Second, auditService.Seal)
+			worker.RunPeriodic(lc, logger, "audit-retention", time.Hour, auditService.PurgeExpired)
+			worker.RunPeriodic(lc, logger, "audit-checkpoint", time.Minute, auditService.Checkpoint)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, relay *usecases.OutboxRelay) {
+			worker.RunPeriodic(lc, logger, "outbox-relay", time.Second, relay.Relay)
+			worker.RunPeriodic(lc, logger, "outbox-cleanup", time.Hour, relay.PurgePublished)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, logger *slog.Logger, dispatcher *usecases.WebhookDispatcher) {
+			worker.RunPeriodic(lc, logger, "webhook-dispatch", time.Second, dispatcher.Dispatch)
+		}),
+		// Stop hooks run in reverse order, so this one, registered last,
+		// takes the service out of rotation before the workers and servers
+		// drain. The database closes after all of them.
+		fx.Invoke(func(lc fx.Lifecycle, health *server.Health) {
+			lc.Append(fx.StopHook(health.Shutdown))
 		}),
 	)
 }
\ No newline at end of file
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"slices"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/authpb"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// adminMethods manage keys, clients, webhooks and the audit log, or change
+// or delete a user without a token of that user.
+var adminMethods = map[string]bool{
+	authpb.AuthService_UpdateUserInfo_FullMethodName:            true,
+	authpb.AuthService_DeleteAuth_FullMethodName:                true,
+	authpb.AuthService_RegisterClient_FullMethodName:            true,
+	authpb.AuthService_RotateSigningKey_FullMethodName:          true,
+	authpb.AuthService_ListSigningKeys_FullMethodName:           true,
+	authpb.AuthService_RotateEncryptionKey_FullMethodName:       true,
+	authpb.AuthService_ListEncryptionKeys_FullMethodName:        true,
+	authpb.AuthService_SetAudienceKey_FullMethodName:            true,
+	authpb.AuthService_QueryAuditEvents_FullMethodName:          true,
+	authpb.AuthService_VerifyAuditChain_FullMethodName:          true,
+	authpb.AuthService_CreateWebhookSubscription_FullMethodName: true,
+	authpb.AuthService_ListWebhookSubscriptions_FullMethodName:  true,
+	authpb.AuthService_DeleteWebhookSubscription_FullMethodName: true,
+	authpb.AuthService_ListWebhookDeliveries_FullMethodName:     true,
+	authpb.AuthService_RetryWebhookDelivery_FullMethodName:      true,
+}
+
+// eventWatcherMethods stream the audit log. Besides the admins, they are
+// served to the event watcher identities, such as a fraud service.
+var eventWatcherMethods = map[string]bool{
+	authpb.AuthService_WatchAuthEvents_FullMethodName: true,
+}
+
+// clientIdentities maps verified client certificates to service
+// identities and decides which identities may call the admin and event
+// watcher RPCs.
+type clientIdentities struct {
+	// subjects maps a certificate subject, in RFC 2253 form, or a URI SAN
+	// such as a SPIFFE ID to an identity.
+	subjects      map[string]string
+	admins        []string
+	eventWatchers []string
+}
+
+// newClientIdentities maps the configured identity=subject pairs. Admins
+// may also watch auth events. The admin and event watcher RPCs are refused
+// to every caller while none of their identities are configured; the
+// configuration only allows identities together with mutual TLS.
+func newClientIdentities(cfg config.GRPCConfig) *clientIdentities {
+	identities := &clientIdentities{
+		subjects:      map[string]string{},
+		admins:        cfg.AdminIdentities,
+		eventWatchers: append(slices.Clone(cfg.AdminIdentities), cfg.EventWatcherIdentities...),
+	}
+
+	for _, pair := range cfg.TLS.ClientIdentities {
+		identity, subject, _ := strings.Cut(pair, "=")
+		identities.subjects[strings.TrimSpace(subject)] = strings.TrimSpace(identity)
+	}
+
+	return identities
+}
+
+// clientCertificate returns the verified client certificate of the caller.
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0]
+}
+
+func (c *clientIdentities) lookup(certificate *x509.Certificate) (string, bool) {
+	if identity, ok := c.subjects[certificate.Subject.String()]; ok {
+		return identity, true
+	}
+	for _, uri := range certificate.URIs {
+		if identity, ok := c.subjects[uri.String()]; ok {
+			return identity, true
+		}
+	}
+
+	return "", false
+}
+
+type clientIdentityKey struct{}
+
+// ClientIdentityFromContext returns the service identity of the caller, set
+// when it presented a mapped client certificate.
+func ClientIdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(clientIdentityKey{}).(string)
+	return identity, ok
+}
+
+// allowed returns the identities that may call method, and false when any
+// caller may.
+func (c *clientIdentities) allowed(method string) ([]string, bool) {
+	switch {
+	case adminMethods[method]:
+		return c.admins, true
+	case eventWatcherMethods[method]:
+		return c.eventWatchers, true
+	default:
+		return nil, false
+	}
+}
+
+// authorize puts the caller's identity in the context and keeps the admin
+// and event watcher RPCs to their identities.
+func (c *clientIdentities) authorize(ctx context.Context, method string) (context.Context, error) {
+	certificate := clientCertificate(ctx)
+
+	var identity string
+	var identified bool
+	if certificate != nil {
+		identity, identified = c.lookup(certificate)
+	}
+	if identified {
+		ctx = context.WithValue(ctx, clientIdentityKey{}, identity)
+	}
+
+	allowed, restricted := c.allowed(method)
+	if !restricted {
+		return ctx, nil
+	}
+	if len(allowed) == 0 {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires a client identity and none is configured", method)
+	}
+	if certificate == nil {
+		return nil, status.Error(codes.Unauthenticated, "a client certificate is required")
+	}
+	if !identified {
+		return nil, status.Error(codes.PermissionDenied, "the client certificate is not mapped to an identity")
+	}
+	if !slices.Contains(allowed, identity) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s may not call %s", identity, method)
+	}
+
+	return ctx, nil
+}
+
+// authorizationInterceptor authorizes unary RPCs.
+func (c *clientIdentities) authorizationInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := c.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// authorizationStreamInterceptor is authorizationInterceptor for streaming
+// RPCs.
+func (c *clientIdentities) authorizationStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := c.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &requestInfoStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/authpb"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var testGRPCConfig = config.GRPCConfig{
+	AdminIdentities:        []string{"ops"},
+	EventWatcherIdentities: []string{"fraud"},
+	TLS: config.TLSConfig{
+		ClientIdentities: []string{
+			"ops=CN=ops,O=Example",
+			"fraud=spiffe://example.org/fraud",
+			"billing=CN=billing,O=Example",
+		},
+	},
+}
+
+func testCertificate(commonName string, uris ...string) *x509.Certificate {
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: commonName, Organization: []string{"Example"}}}
+	for _, raw := range uris {
+		uri, _ := url.Parse(raw)
+		certificate.URIs = append(certificate.URIs, uri)
+	}
+	return certificate
+}
+
+// peerContext is the context of an RPC over mutual TLS whose client
+// presented certificate, verified when verified is set.
+func peerContext(certificate *x509.Certificate, verified bool) context.Context {
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}}
+	if verified {
+		state.VerifiedChains = [][]*x509.Certificate{{certificate}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestAuthorizeClientIdentity(t *testing.T) {
+	ops := peerContext(testCertificate("ops"), true)
+	fraud := peerContext(testCertificate("fraud-service", "spiffe://example.org/fraud"), true)
+	billing := peerContext(testCertificate("billing"), true)
+
+	tests := []struct {
+		name     string
+		cfg      config.GRPCConfig
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "admin calls an admin method", cfg: testGRPCConfig, ctx: ops, method: authpb.AuthService_RotateSigningKey_FullMethodName},
+		{name: "admin watches events", cfg: testGRPCConfig, ctx: ops, method: authpb.AuthService_WatchAuthEvents_FullMethodName},
+		{name: "event watcher by URI SAN", cfg: testGRPCConfig, ctx: fraud, method: authpb.AuthService_WatchAuthEvents_FullMethodName},
+		{
+			name:     "event watcher calls an admin method",
+			cfg:      testGRPCConfig,
+			ctx:      fraud,
+			method:   authpb.AuthService_QueryAuditEvents_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "other identity watches events",
+			cfg:      testGRPCConfig,
+			ctx:      billing,
+			method:   authpb.AuthService_WatchAuthEvents_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unmapped certificate",
+			cfg:      testGRPCConfig,
+			ctx:      peerContext(testCertificate("stranger"), true),
+			method:   authpb.AuthService_DeleteAuth_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unverified certificate",
+			cfg:      testGRPCConfig,
+			ctx:      peerContext(testCertificate("ops"), false),
+			method:   authpb.AuthService_DeleteAuth_FullMethodName,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "no certificate",
+			cfg:      testGRPCConfig,
+			ctx:      context.Background(),
+			method:   authpb.AuthService_VerifyAuditChain_FullMethodName,
+			wantCode: codes.Unauthenticated,
+		},
+		{name: "user method without a certificate", cfg: testGRPCConfig, ctx: context.Background(), method: authpb.AuthService_ChangePassword_FullMethodName},
+		{name: "user method by a service", cfg: testGRPCConfig, ctx: billing, method: authpb.AuthService_Login_FullMethodName},
+		{
+			name:     "admin method with no identities configured",
+			ctx:      ops,
+			method:   authpb.AuthService_RegisterClient_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "event watching with no identities configured",
+			ctx:      ops,
+			method:   authpb.AuthService_WatchAuthEvents_FullMethodName,
+			wantCode: codes.PermissionDenied,
+		},
+		{name: "user method with no identities configured", ctx: context.Background(), method: authpb.AuthService_ChangePassword_FullMethodName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newClientIdentities(tt.cfg).authorize(tt.ctx, tt.method)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("authorize() error = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutIdentitiesNamesTheReason(t *testing.T) {
+	_, err := newClientIdentities(config.GRPCConfig{}).authorize(context.Background(), authpb.AuthService_ListSigningKeys_FullMethodName)
+
+	want := authpb.AuthService_ListSigningKeys_FullMethodName + " requires a client identity and none is configured"
+	if s, _ := status.FromError(err); s.Code() != codes.PermissionDenied || s.Message() != want {
+		t.Errorf("authorize() error = %v, want PermissionDenied %q", err, want)
+	}
+}
+
+func TestAuthorizationInterceptorSetsClientIdentity(t *testing.T) {
+	identities := newClientIdentities(testGRPCConfig)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantIdentity string
+	}{
+		{name: "mapped certificate", ctx: peerContext(testCertificate("billing"), true), wantIdentity: "billing"},
+		{name: "unmapped certificate", ctx: peerContext(testCertificate("stranger"), true)},
+		{name: "no certificate", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &grpc.UnaryServerInfo{FullMethod: authpb.AuthService_Login_FullMethodName}
+			_, err := identities.authorizationInterceptor(tt.ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				identity, ok := ClientIdentityFromContext(ctx)
+				if identity != tt.wantIdentity || ok != (tt.wantIdentity != "") {
+					t.Errorf("ClientIdentityFromContext() = %q, %v, want %q", identity, ok, tt.wantIdentity)
+				}
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("authorizationInterceptor() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizationInterceptorStopsRefusedCalls(t *testing.T) {
+	identities := newClientIdentities(testGRPCConfig)
+	info := &grpc.UnaryServerInfo{FullMethod: authpb.AuthService_DeleteAuth_FullMethodName}
+
+	_, err := identities.authorizationInterceptor(peerContext(testCertificate("billing"), true), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("the handler ran for a refused call")
+		return nil, nil
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("authorizationInterceptor() error = %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/url"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+)
+
+const (
+	loginCSRFCookie = "authgate_login_csrf"
+	loginCSRFField  = "csrf_token"
+)
+
+// issueLoginCSRF protects the login form against login CSRF, where another
+// site posts its own credentials to sign the victim in to the attacker's
+// account. Every render gets a fresh random nonce in an HttpOnly cookie, and
+// the form carries a token binding that nonce to the authorization request,
+// so a form can only be posted by the browser it was rendered for and only
+// for the request it was rendered with.
+func issueLoginCSRF(w http.ResponseWriter, r *http.Request, request dtos.AuthorizeRequestDTO) (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCSRFCookie,
+		Value:    encoded,
+		Path:     "/oauth/authorize",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return loginCSRFToken(encoded, request), nil
+}
+
+// verifyLoginCSRF checks a posted login form: a browser that sends an
+// Origin must send the one of this server, and the form token must match
+// the cookie nonce and the posted authorization request.
+func verifyLoginCSRF(r *http.Request, request dtos.AuthorizeRequestDTO) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Host != r.Host {
+			return models.NewOAuthError(models.OAuthErrorInvalidRequest, "the sign-in form was posted from another site")
+		}
+	}
+
+	cookie, err := r.Cookie(loginCSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return models.NewOAuthError(models.OAuthErrorInvalidRequest, "the sign-in form has expired, start the sign-in again")
+	}
+
+	expected := loginCSRFToken(cookie.Value, request)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(r.PostForm.Get(loginCSRFField))) != 1 {
+		return models.NewOAuthError(models.OAuthErrorInvalidRequest, "the sign-in form has expired, start the sign-in again")
+	}
+
+	return nil
+}
+
+func loginCSRFToken(nonce string, request dtos.AuthorizeRequestDTO) string {
+	hash := sha256.New()
+	for _, value := range []string{
+		nonce,
+		request.ResponseType,
+		request.ClientID,
+		request.RedirectURI,
+		request.Scope,
+		request.State,
+		request.Nonce,
+		request.CodeChallenge,
+		request.CodeChallengeMethod,
+	} {
+		// Length prefixes keep adjacent values from running into each
+		// other.
+		hash.Write(binary.BigEndian.AppendUint32(nil, uint32(len(value))))
+		hash.Write([]byte(value))
+	}
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
+}
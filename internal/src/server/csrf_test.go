@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+)
+
+var testAuthorizeRequest = dtos.AuthorizeRequestDTO{
+	ResponseType:        "code",
+	ClientID:            "client-1",
+	RedirectURI:         "https://app.example/callback",
+	Scope:               "openid",
+	State:               "state-1",
+	CodeChallenge:       "-WDrWIR1t4n9_BmFZP3i3z3ut7rovByRcY4Q7bb8O5U",
+	CodeChallengeMethod: "S256",
+}
+
+// renderLoginForm issues a CSRF token the way the login page does and
+// returns it with the cookie the browser would keep.
+func renderLoginForm(t *testing.T) (string, *http.Cookie) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	token, err := issueLoginCSRF(recorder, httptest.NewRequest(http.MethodGet, "/oauth/authorize", nil), testAuthorizeRequest)
+	if err != nil {
+		t.Fatalf("issueLoginCSRF() error = %v", err)
+	}
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != loginCSRFCookie {
+		t.Fatalf("cookies = %v, want %s", cookies, loginCSRFCookie)
+	}
+	if !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie is not HttpOnly and SameSite=Strict: %+v", cookies[0])
+	}
+
+	return token, cookies[0]
+}
+
+func postLoginForm(token string, cookie *http.Cookie, origin string) *http.Request {
+	form := url.Values{loginCSRFField: {token}}
+	r := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	r.ParseForm()
+	return r
+}
+
+func TestLoginCSRF(t *testing.T) {
+	token, cookie := renderLoginForm(t)
+	_, otherCookie := renderLoginForm(t)
+
+	otherRequest := testAuthorizeRequest
+	otherRequest.RedirectURI = "https://app.example/elsewhere"
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		posted  dtos.AuthorizeRequestDTO
+		wantErr bool
+	}{
+		{name: "same browser and request", request: postLoginForm(token, cookie, ""), posted: testAuthorizeRequest},
+		{name: "same origin", request: postLoginForm(token, cookie, "http://example.com"), posted: testAuthorizeRequest},
+		{name: "other origin", request: postLoginForm(token, cookie, "https://attacker.example"), posted: testAuthorizeRequest, wantErr: true},
+		{name: "no cookie", request: postLoginForm(token, nil, ""), posted: testAuthorizeRequest, wantErr: true},
+		{name: "cookie of another render", request: postLoginForm(token, otherCookie, ""), posted: testAuthorizeRequest, wantErr: true},
+		{name: "no token", request: postLoginForm("", cookie, ""), posted: testAuthorizeRequest, wantErr: true},
+		{name: "other authorization request", request: postLoginForm(token, cookie, ""), posted: otherRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyLoginCSRF(tt.request, tt.posted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyLoginCSRF() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
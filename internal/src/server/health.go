@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/authpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const healthCheckTimeout = 3 * time.Second
+
+// HealthCheck is one dependency the service needs to answer requests.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Health tracks whether the service is ready. It backs the grpc.health.v1
+// service, for the whole server and for AuthService, and the HTTP /readyz
+// probe. /healthz only tells the process is up.
+//
+// The service starts NOT_SERVING and becomes SERVING once every check
+// passes; Check is meant to run periodically.
+type Health struct {
+	checks []HealthCheck
+	logger *slog.Logger
+	server *health.Server
+
+	mu       sync.RWMutex
+	failing  []string
+	stopping bool
+}
+
+func NewHealth(logger *slog.Logger, checks ...HealthCheck) *Health {
+	h := &Health{
+		checks:  checks,
+		logger:  logger,
+		server:  health.NewServer(),
+		failing: []string{"startup"},
+	}
+	h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return h
+}
+
+// Check runs every check and updates the serving status. Changes are
+// logged; the error is always nil so a failing dependency does not fill the
+// worker log on every run.
+func (h *Health) Check(ctx context.Context) error {
+	h.mu.RLock()
+	stopping := h.stopping
+	h.mu.RUnlock()
+	if stopping {
+		return nil
+	}
+
+	var failing []string
+	for _, check := range h.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := check.Check(checkCtx)
+		cancel()
+
+		if err != nil {
+			failing = append(failing, check.Name)
+			h.logger.WarnContext(ctx, "Health check failed", "check", check.Name, "error", err)
+		}
+	}
+
+	h.mu.Lock()
+	if h.stopping {
+		h.mu.Unlock()
+		return nil
+	}
+	changed := !slices.Equal(h.failing, failing)
+	h.failing = failing
+	h.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	if len(failing) == 0 {
+		h.logger.InfoContext(ctx, "Service is ready")
+		h.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		h.logger.WarnContext(ctx, "Service is not ready", "failing", failing)
+		h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return nil
+}
+
+// Shutdown reports NOT_SERVING for good, so load balancers and probes stop
+// sending traffic while in-flight requests drain.
+func (h *Health) Shutdown() {
+	h.mu.Lock()
+	h.stopping = true
+	h.failing = []string{"shutdown"}
+	h.mu.Unlock()
+
+	h.logger.Info("Service is shutting down")
+	h.server.Shutdown()
+}
+
+// Ready returns the checks that failed last time, if any.
+func (h *Health) Ready() (bool, []string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.failing) == 0, slices.Clone(h.failing)
+}
+
+func (h *Health) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(authpb.AuthService_ServiceDesc.ServiceName, status)
+}
+
+func (h *Health) register(grpcServer *grpc.Server) {
+	healthpb.RegisterHealthServer(grpcServer, h.server)
+}
+
+// Register mounts the HTTP probes.
+func (h *Health) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.healthz)
+	mux.HandleFunc("GET /readyz", h.readyz)
+}
+
+func (h *Health) healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (h *Health) readyz(w http.ResponseWriter, r *http.Request) {
+	ready, failing := h.Ready()
+	if !ready {
+		writeHealthJSON(w, http.StatusServiceUnavailable, map[string]any{
+			"status":  "unavailable",
+			"failing": failing,
+		})
+		return
+	}
+
+	writeHealthJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func writeHealthJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/controller"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/fx"
+)
+
+func NewHTTPServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, cfg config.HTTPConfig, logger *slog.Logger, controller *controller.Controller, registry *prometheus.Registry, health *Health) *http.Server {
+	mux := http.NewServeMux()
+	NewOAuthHandler(controller).Register(mux)
+	NewOIDCHandler(controller).Register(mux)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	health.Register(mux)
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           otelhttp.NewHandler(withRequestLogging(logger, withRequestInfo(mux)), "authgate.http"),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("HTTP server listening", "address", lis.Addr().String())
+
+			go func() {
+				if err := server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("HTTP server failed", "error", err)
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Stopping HTTP server")
+			return shutdownHTTP(ctx, logger, server, cfg.ShutdownTimeout)
+		},
+	})
+
+	return server
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/logging"
+	clarchutils "github.com/Gabriel-Schiestl/go-clarch/v2/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// validRequestID accepts the request IDs callers may choose; anything else
+// is replaced by a generated one.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// loggingInterceptor gives every unary RPC a request ID, taken from the
+// x-request-id metadata when the caller sent one and echoed in the response
+// headers, and logs the call once it is answered.
+func loggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, requestID := withGRPCRequestID(ctx, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, logger, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// loggingStreamInterceptor is loggingInterceptor for streaming RPCs; a
+// stream is logged once it ends.
+func loggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestID := withGRPCRequestID(stream.Context(), info.FullMethod)
+		_ = stream.SetHeader(metadata.Pairs(requestIDHeader, requestID))
+
+		start := time.Now()
+		err := handler(srv, &requestInfoStream{ServerStream: stream, ctx: ctx})
+		logRPC(ctx, logger, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func withGRPCRequestID(ctx context.Context, method string) (context.Context, string) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if !validRequestID.MatchString(requestID) {
+		requestID = clarchutils.GenerateUUID()
+	}
+
+	return logging.WithRequest(ctx, requestID, method), requestID
+}
+
+func logRPC(ctx context.Context, logger *slog.Logger, method string, err error, took time.Duration) {
+	code := status.Code(err)
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+		// Probes run every few seconds.
+		if strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+			level = slog.LevelDebug
+		}
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("code", code.String()),
+		slog.Duration("duration", took),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	logger.LogAttrs(ctx, level, "rpc finished", attrs...)
+}
+
+// withRequestLogging is the HTTP counterpart of loggingInterceptor.
+func withRequestLogging(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = clarchutils.GenerateUUID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := logging.WithRequest(r.Context(), requestID, r.Method+" "+r.URL.Path)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		switch {
+		case recorder.status < 400 && (r.URL.Path == "/healthz" || r.URL.Path == "/readyz"):
+			level = slog.LevelDebug
+		case recorder.status >= 500:
+			level = slog.LevelError
+		case recorder.status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(ctx, level, "http request finished",
+			slog.Int("status", recorder.status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
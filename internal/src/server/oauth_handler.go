@@ -0,0 +1,302 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/controller"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in to {{.ClientName}}</title></head>
+<body>
+<h1>Sign in to {{.ClientName}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="/oauth/authorize">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+<input type="hidden" name="scope" value="{{.Request.Scope}}">
+<input type="hidden" name="state" value="{{.Request.State}}">
+<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+<select name="identifier_type">
+<option value="1">Email</option>
+<option value="2">CPF</option>
+<option value="3">CNPJ</option>
+<option value="4">Phone</option>
+</select>
+<input name="identifier_value" required autocomplete="username">
+<input name="password" type="password" required autocomplete="current-password">
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Authorization error</title></head>
+<body>
+<h1>Authorization error</h1>
+<p>{{.Code}}{{if .Description}}: {{.Description}}{{end}}</p>
+</body>
+</html>
+`))
+
+type loginPageData struct {
+	ClientName string
+	Error      string
+	Request    dtos.AuthorizeRequestDTO
+	CSRFToken  string
+}
+
+type OAuthHandler struct {
+	controller *controller.Controller
+}
+
+func NewOAuthHandler(controller *controller.Controller) *OAuthHandler {
+	return &OAuthHandler{
+		controller: controller,
+	}
+}
+
+func (h *OAuthHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /oauth/authorize", h.showAuthorize)
+	mux.HandleFunc("POST /oauth/authorize", h.submitAuthorize)
+	mux.HandleFunc("POST /oauth/token", h.token)
+	mux.HandleFunc("POST /oauth/introspect", h.introspect)
+	mux.HandleFunc("POST /oauth/revoke", h.revoke)
+}
+
+func (h *OAuthHandler) showAuthorize(w http.ResponseWriter, r *http.Request) {
+	request := authorizeRequestFromForm(r)
+
+	response, err := h.controller.ValidateAuthorize(r.Context(), request)
+	if err != nil {
+		writeAuthorizeError(w, err)
+		return
+	}
+	if response.RedirectURI != "" {
+		http.Redirect(w, r, response.RedirectURI, http.StatusFound)
+		return
+	}
+
+	renderLoginPage(w, r, http.StatusOK, loginPageData{ClientName: response.ClientName, Request: request})
+}
+
+func (h *OAuthHandler) submitAuthorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeAuthorizeError(w, models.NewOAuthError(models.OAuthErrorInvalidRequest, "malformed form body"))
+		return
+	}
+
+	request := authorizeRequestFromForm(r)
+	if err := verifyLoginCSRF(r, request); err != nil {
+		writeAuthorizeError(w, err)
+		return
+	}
+	identifierType, _ := strconv.Atoi(r.PostForm.Get("identifier_type"))
+
+	response, err := h.controller.Authorize(r.Context(), dtos.AuthorizeDTO{
+		Request: request,
+		Credentials: dtos.LoginDTO{
+			IdentifierType:  models.IdentifierType(identifierType),
+			IdentifierValue: r.PostForm.Get("identifier_value"),
+			Password:        r.PostForm.Get("password"),
+		},
+	})
+	if err != nil {
+		var businessErr *exceptions.BusinessException
+		if errors.As(err, &businessErr) {
+			validation, validationErr := h.controller.ValidateAuthorize(r.Context(), request)
+			if validationErr != nil {
+				writeAuthorizeError(w, validationErr)
+				return
+			}
+			renderLoginPage(w, r, http.StatusUnauthorized, loginPageData{
+				ClientName: validation.ClientName,
+				Error:      businessErr.Error(),
+				Request:    request,
+			})
+			return
+		}
+
+		writeAuthorizeError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, response.RedirectURI, http.StatusFound)
+}
+
+func (h *OAuthHandler) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, models.NewOAuthError(models.OAuthErrorInvalidRequest, "malformed form body"))
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+
+	response, err := h.controller.Token(r.Context(), dtos.TokenDTO{
+		GrantType:          r.PostForm.Get("grant_type"),
+		Code:               r.PostForm.Get("code"),
+		RedirectURI:        r.PostForm.Get("redirect_uri"),
+		ClientID:           clientID,
+		ClientSecret:       clientSecret,
+		CodeVerifier:       r.PostForm.Get("code_verifier"),
+		Scope:              r.PostForm.Get("scope"),
+		Audience:           r.PostForm["audience"],
+		SubjectToken:       r.PostForm.Get("subject_token"),
+		SubjectTokenType:   r.PostForm.Get("subject_token_type"),
+		ActorToken:         r.PostForm.Get("actor_token"),
+		ActorTokenType:     r.PostForm.Get("actor_token_type"),
+		RequestedTokenType: r.PostForm.Get("requested_token_type"),
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	writeOAuthJSON(w, http.StatusOK, response)
+}
+
+func (h *OAuthHandler) introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, models.NewOAuthError(models.OAuthErrorInvalidRequest, "malformed form body"))
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+
+	response, err := h.controller.IntrospectToken(r.Context(), dtos.IntrospectDTO{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	writeOAuthJSON(w, http.StatusOK, response)
+}
+
+func (h *OAuthHandler) revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, models.NewOAuthError(models.OAuthErrorInvalidRequest, "malformed form body"))
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+
+	err := h.controller.RevokeToken(r.Context(), dtos.RevokeTokenDTO{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+func authorizeRequestFromForm(r *http.Request) dtos.AuthorizeRequestDTO {
+	return dtos.AuthorizeRequestDTO{
+		ResponseType:        r.FormValue("response_type"),
+		ClientID:            r.FormValue("client_id"),
+		RedirectURI:         r.FormValue("redirect_uri"),
+		Scope:               r.FormValue("scope"),
+		State:               r.FormValue("state"),
+		Nonce:               r.FormValue("nonce"),
+		CodeChallenge:       r.FormValue("code_challenge"),
+		CodeChallengeMethod: r.FormValue("code_challenge_method"),
+	}
+}
+
+// clientCredentials reads client authentication from HTTP Basic first and
+// falls back to the client_id/client_secret form parameters, as allowed by
+// RFC 6749 section 2.3.1.
+func clientCredentials(r *http.Request) (string, string) {
+	if clientID, clientSecret, ok := r.BasicAuth(); ok {
+		return clientID, clientSecret
+	}
+
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
+
+func renderLoginPage(w http.ResponseWriter, r *http.Request, status int, data loginPageData) {
+	token, err := issueLoginCSRF(w, r, data.Request)
+	if err != nil {
+		writeAuthorizeError(w, err)
+		return
+	}
+	data.CSRFToken = token
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.WriteHeader(status)
+	_ = loginPage.Execute(w, data)
+}
+
+func writeAuthorizeError(w http.ResponseWriter, err error) {
+	oauthErr, status := toOAuthError(err)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = errorPage.Execute(w, oauthErr)
+}
+
+func writeOAuthError(w http.ResponseWriter, err error) {
+	oauthErr, status := toOAuthError(err)
+
+	body := map[string]string{"error": oauthErr.Code}
+	if oauthErr.Description != "" {
+		body["error_description"] = oauthErr.Description
+	}
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="authgate"`)
+	}
+
+	writeOAuthJSON(w, status, body)
+}
+
+func writeOAuthJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func toOAuthError(err error) (*models.OAuthError, int) {
+	var oauthErr *models.OAuthError
+	if errors.As(err, &oauthErr) {
+		if oauthErr.Code == models.OAuthErrorInvalidClient {
+			return oauthErr, http.StatusUnauthorized
+		}
+		return oauthErr, http.StatusBadRequest
+	}
+
+	var businessErr *exceptions.BusinessException
+	if errors.As(err, &businessErr) {
+		return models.NewOAuthError(models.OAuthErrorInvalidRequest, businessErr.Error()), http.StatusBadRequest
+	}
+
+	return models.NewOAuthError(models.OAuthErrorServerError, ""), http.StatusInternalServerError
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/controller"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/go-clarch/v2/domain/exceptions"
+)
+
+type OIDCHandler struct {
+	controller *controller.Controller
+}
+
+func NewOIDCHandler(controller *controller.Controller) *OIDCHandler {
+	return &OIDCHandler{
+		controller: controller,
+	}
+}
+
+func (h *OIDCHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /.well-known/openid-configuration", h.discovery)
+	mux.HandleFunc("GET /.well-known/jwks.json", h.jwks)
+	mux.HandleFunc("GET /userinfo", h.userInfo)
+	mux.HandleFunc("POST /userinfo", h.userInfo)
+}
+
+func (h *OIDCHandler) discovery(w http.ResponseWriter, r *http.Request) {
+	response, err := h.controller.OpenIDConfiguration(r.Context())
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *OIDCHandler) jwks(w http.ResponseWriter, r *http.Request) {
+	response, err := h.controller.GetJWKS(r.Context())
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *OIDCHandler) userInfo(w http.ResponseWriter, r *http.Request) {
+	accessToken, ok := bearerToken(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="authgate"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	response, err := h.controller.OIDCUserInfo(r.Context(), dtos.OIDCUserInfoRequestDTO{
+		AccessToken: accessToken,
+	})
+	if err != nil {
+		writeBearerError(w, err)
+		return
+	}
+
+	writeOAuthJSON(w, http.StatusOK, response)
+}
+
+// bearerToken extracts an RFC 6750 bearer token from the Authorization
+// header or, for POST requests, from the access_token form field.
+func bearerToken(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", false
+		}
+		return token, true
+	}
+
+	if r.Method == http.MethodPost {
+		if token := r.PostFormValue("access_token"); token != "" {
+			return token, true
+		}
+	}
+
+	return "", false
+}
+
+func writeBearerError(w http.ResponseWriter, err error) {
+	code, status := models.OAuthErrorInvalidToken, http.StatusUnauthorized
+
+	var oauthErr *models.OAuthError
+	var businessErr *exceptions.BusinessException
+	var notFound *exceptions.RepositoryNoDataFoundException
+	switch {
+	case errors.As(err, &oauthErr):
+		code = oauthErr.Code
+		if code == models.OAuthErrorInsufficientScope {
+			status = http.StatusForbidden
+		}
+	case errors.As(err, &businessErr), errors.As(err, &notFound):
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("WWW-Authenticate", `Bearer realm="authgate", error="`+code+`"`)
+	w.WriteHeader(status)
+}
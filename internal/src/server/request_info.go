@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// requestInfoInterceptor puts the caller's address and user agent in the
+// context so audit events can record where a request came from.
+func requestInfoInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withGRPCRequestInfo(ctx), req)
+}
+
+// requestInfoStreamInterceptor is requestInfoInterceptor for streaming RPCs.
+func requestInfoStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &requestInfoStream{
+		ServerStream: stream,
+		ctx:          withGRPCRequestInfo(stream.Context()),
+	})
+}
+
+type requestInfoStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestInfoStream) Context() context.Context {
+	return s.ctx
+}
+
+func withGRPCRequestInfo(ctx context.Context) context.Context {
+	var requestInfo utils.RequestInfo
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		requestInfo.PeerIP = hostOnly(p.Addr.String())
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			requestInfo.UserAgent = values[0]
+		}
+	}
+
+	return utils.WithRequestInfo(ctx, requestInfo)
+}
+
+// withRequestInfo is the HTTP counterpart of requestInfoInterceptor.
+func withRequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := utils.WithRequestInfo(r.Context(), utils.RequestInfo{
+			PeerIP:    hostOnly(r.RemoteAddr),
+			UserAgent: r.UserAgent(),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func hostOnly(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
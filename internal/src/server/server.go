@@ -2,54 +2,106 @@ package server
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net"
+	"time"
 
 	"github.com/Gabriel-Schiestl/authgate/authpb"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/application/dtos"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/controller"
 	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/models"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/domain/services"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type AuthServiceServer struct {
 	authpb.UnimplementedAuthServiceServer
 	controller *controller.Controller
+	stopping   chan struct{}
 }
 
-func NewAuthServiceServer(lc fx.Lifecycle, controller *controller.Controller) *AuthServiceServer {
+// NewAuthServiceServer serves AuthService and the grpc.health.v1 service on
+// the configured port, over TLS when it is configured (see grpcTLSConfig).
+// Client certificates map to service identities and the admin RPCs are
+// limited to the admin ones (see newClientIdentities). Server reflection, for grpcurl and
+// similar tools, is served when enabled.
+//
+// On stop, open auth event streams are ended and in-flight RPCs get until
+// the shutdown timeout to finish before the remaining ones are cut off.
+func NewAuthServiceServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, cfg config.GRPCConfig, logger *slog.Logger, controller *controller.Controller, metrics services.IMetrics, health *Health) (*AuthServiceServer, error) {
     server := &AuthServiceServer{
         controller: controller,
+        stopping:   make(chan struct{}),
+    }
+
+    tlsConfig, err := grpcTLSConfig(cfg.TLS, logger)
+    if err != nil {
+        return nil, err
+    }
+    options := serverOptions(logger, metrics, newClientIdentities(cfg))
+    if tlsConfig != nil {
+        options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+    } else {
+        logger.Warn("gRPC listener is plaintext; set GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE unless TLS ends before the service")
+    }
+    if len(cfg.AdminIdentities) == 0 {
+        logger.Warn("no admin identities are configured; admin RPCs are refused, set GRPC_ADMIN_IDENTITIES with mutual TLS to allow them")
+    }
+
+    grpcServer := grpc.NewServer(options...)
+    authpb.RegisterAuthServiceServer(grpcServer, server)
+    health.register(grpcServer)
+    if cfg.Reflection {
+        reflection.Register(grpcServer)
     }
 
     lc.Append(fx.Hook{
         OnStart: func(ctx context.Context) error {
-            lis, err := net.Listen("tcp", ":50051")
+            lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
             if err != nil {
                 return err
             }
 
-            grpcServer := grpc.NewServer()
-            authpb.RegisterAuthServiceServer(grpcServer, server)
-
-            log.Printf("gRPC server listening at %v", lis.Addr())
+            logger.Info("gRPC server listening", "address", lis.Addr().String(), "tls", tlsConfig != nil)
             
             go func() {
+                // Serve only returns an error when it stopped on its own.
                 if err := grpcServer.Serve(lis); err != nil {
-                    log.Fatalf("Failed to serve: %v", err)
+                    logger.Error("gRPC server failed", "error", err)
+                    _ = shutdowner.Shutdown(fx.ExitCode(1))
                 }
             }()
 
             return nil
         },
         OnStop: func(ctx context.Context) error {
-            log.Println("Stopping gRPC server...")
-            return nil
+            logger.Info("Stopping gRPC server")
+            close(server.stopping)
+            return gracefulStop(ctx, logger, grpcServer, cfg.ShutdownTimeout)
         },
     })
 
-    return server
+    return server, nil
+}
+
+// serverOptions traces every RPC, so the spans of the database queries it
+// runs become its children, and chains the interceptors every RPC goes
+// through.
+func serverOptions(logger *slog.Logger, metrics services.IMetrics, identities *clientIdentities) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(loggingInterceptor(logger), requestInfoInterceptor, metricsInterceptor(metrics), identities.authorizationInterceptor),
+		grpc.ChainStreamInterceptor(loggingStreamInterceptor(logger), requestInfoStreamInterceptor, metricsStreamInterceptor(metrics), identities.authorizationStreamInterceptor),
+	}
 }
 
 func (s *AuthServiceServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
@@ -57,6 +109,7 @@ func (s *AuthServiceServer) Login(ctx context.Context, req *authpb.LoginRequest)
 		IdentifierType: models.IdentifierType(req.GetIdentifierType()),
 		IdentifierValue: req.GetIdentifierValue(),
 		Password: req.GetPassword(),
+		Audience: req.GetAudience(),
 	})
 	if err != nil {
 		return nil, err
@@ -74,8 +127,18 @@ func (s *AuthServiceServer) Login(ctx context.Context, req *authpb.LoginRequest)
 }
 
 func (s *AuthServiceServer) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
-	maxTokenAge := int(req.GetMaxTokenAgeSeconds())
-	maxWrongAttempts := int(req.GetMaxWrongAttempts())
+	// Left unset, the token age and the lockout limit fall back to the
+	// configured defaults instead of zero, which disables the lockout.
+	var maxTokenAge *int
+	if req.MaxTokenAgeSeconds != nil {
+		age := int(req.GetMaxTokenAgeSeconds())
+		maxTokenAge = &age
+	}
+	var maxWrongAttempts *int
+	if req.MaxWrongAttempts != nil {
+		limit := int(req.GetMaxWrongAttempts())
+		maxWrongAttempts = &limit
+	}
 
 	response, err := s.controller.Register(ctx, dtos.RegisterDTO{
 		IdentifierType: models.IdentifierType(req.GetIdentifierType()),
@@ -83,12 +146,12 @@ func (s *AuthServiceServer) Register(ctx context.Context, req *authpb.RegisterRe
 		Password: req.GetPassword(),
 		UserInfo: dtos.UserInfoDTO{
 			Name:  req.GetUserInfo().GetName(),
-			Roles: req.GetUserInfo().GetRoles(),
 			UserID: req.GetUserInfo().GetUserId(),
+			Metadata: req.GetUserInfo().GetMetadata(),
 		},
 		EncryptToken:       req.GetEncryptToken(),
-		MaxTokenAgeSeconds: &maxTokenAge,
-		MaxWrongAttempts:   &maxWrongAttempts,
+		MaxTokenAgeSeconds: maxTokenAge,
+		MaxWrongAttempts:   maxWrongAttempts,
 	})
 	if err != nil {
 		return nil, err
@@ -101,6 +164,7 @@ func (s *AuthServiceServer) Register(ctx context.Context, req *authpb.RegisterRe
 			UserId: response.UserInfo.UserID,
 			Name:  response.UserInfo.Name,
 			Roles: response.UserInfo.Roles,
+			Metadata: response.UserInfo.Metadata,
 		},
 	}, nil
 }
@@ -108,6 +172,7 @@ func (s *AuthServiceServer) Register(ctx context.Context, req *authpb.RegisterRe
 func (s *AuthServiceServer) RefreshToken(ctx context.Context, req *authpb.RefreshTokenRequest) (*authpb.RefreshTokenResponse, error) {
 	response, err := s.controller.RefreshToken(ctx, dtos.RefreshTokenDTO{
 		RefreshToken: req.GetRefreshToken(),
+		Audience: req.GetAudience(),
 	})
 	if err != nil {
 		return nil, err
@@ -126,6 +191,8 @@ func (s *AuthServiceServer) RefreshToken(ctx context.Context, req *authpb.Refres
 func (s *AuthServiceServer) VerifyToken(ctx context.Context, req *authpb.VerifyTokenRequest) (*authpb.VerifyTokenResponse, error) {
 	response, err := s.controller.VerifyToken(ctx, dtos.VerifyTokenDTO{
 		AccessToken: req.GetAccessToken(),
+		ExpectedAudience: req.GetExpectedAudience(),
+		ExpectedIssuer: req.GetExpectedIssuer(),
 	})
 	if err != nil {
 		return nil, err
@@ -136,6 +203,7 @@ func (s *AuthServiceServer) VerifyToken(ctx context.Context, req *authpb.VerifyT
 			UserId: response.UserID,
 			Name:  response.Name,
 			Roles: response.Roles,
+			Metadata: response.Metadata,
 		},
 	}, nil
 }
@@ -150,3 +218,482 @@ func (s *AuthServiceServer) DeleteAuth(ctx context.Context, req *authpb.DeleteAu
 	}, nil
 }
 
+
+
+func (s *AuthServiceServer) RegisterClient(ctx context.Context, req *authpb.RegisterClientRequest) (*authpb.RegisterClientResponse, error) {
+	response, err := s.controller.RegisterClient(ctx, dtos.RegisterClientDTO{
+		Name: req.GetName(),
+		RedirectURIs: req.GetRedirectUris(),
+		Public: req.GetPublic(),
+		EncryptTokens: req.GetEncryptTokens(),
+		EncryptionPublicKey: req.GetEncryptionPublicKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.RegisterClientResponse{
+		Success: true,
+		ClientId: response.ClientID,
+		ClientSecret: response.ClientSecret,
+	}, nil
+}
+
+func (s *AuthServiceServer) GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error) {
+	response, err := s.controller.GetJWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*authpb.JsonWebKey, 0, len(response.Keys))
+	for _, key := range response.Keys {
+		keys = append(keys, &authpb.JsonWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N: key.N,
+			E: key.E,
+			Crv: key.Crv,
+			X: key.X,
+			Y: key.Y,
+		})
+	}
+
+	return &authpb.GetJWKSResponse{
+		Success: true,
+		Keys: keys,
+	}, nil
+}
+
+func (s *AuthServiceServer) RotateSigningKey(ctx context.Context, req *authpb.RotateSigningKeyRequest) (*authpb.RotateSigningKeyResponse, error) {
+	response, err := s.controller.RotateSigningKey(ctx, dtos.RotateSigningKeyDTO{
+		Purpose: signingKeyPurposeFromProto(req.GetPurpose()),
+		Algorithm: req.GetAlgorithm(),
+		ActivateInSeconds: int(req.GetActivateInSeconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.RotateSigningKeyResponse{
+		Success: true,
+		Key: signingKeyToProto(*response),
+	}, nil
+}
+
+func (s *AuthServiceServer) ListSigningKeys(ctx context.Context, req *authpb.ListSigningKeysRequest) (*authpb.ListSigningKeysResponse, error) {
+	response, err := s.controller.ListSigningKeys(ctx, dtos.ListSigningKeysDTO{
+		Purpose: signingKeyPurposeFromProto(req.GetPurpose()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*authpb.SigningKey, 0, len(response.Keys))
+	for _, key := range response.Keys {
+		keys = append(keys, signingKeyToProto(key))
+	}
+
+	return &authpb.ListSigningKeysResponse{
+		Success: true,
+		Keys: keys,
+	}, nil
+}
+
+func (s *AuthServiceServer) UpdateUserInfo(ctx context.Context, req *authpb.UpdateUserInfoRequest) (*authpb.UpdateUserInfoResponse, error) {
+	response, err := s.controller.UpdateUserInfo(ctx, dtos.UpdateUserInfoDTO{
+		UserID: req.GetUserId(),
+		Name: req.Name,
+		Metadata: req.GetMetadata(),
+		RemoveMetadataKeys: req.GetRemoveMetadataKeys(),
+		AddRoles: req.GetAddRoles(),
+		RemoveRoles: req.GetRemoveRoles(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.UpdateUserInfoResponse{
+		Success: true,
+		UserInfo: &authpb.UserInfo{
+			UserId: response.UserID,
+			Name:  response.Name,
+			Roles: response.Roles,
+			Metadata: response.Metadata,
+		},
+	}, nil
+}
+
+func (s *AuthServiceServer) RotateEncryptionKey(ctx context.Context, req *authpb.RotateEncryptionKeyRequest) (*authpb.RotateEncryptionKeyResponse, error) {
+	response, err := s.controller.RotateEncryptionKey(ctx, dtos.RotateEncryptionKeyDTO{
+		Algorithm: req.GetAlgorithm(),
+		ActivateInSeconds: int(req.GetActivateInSeconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.RotateEncryptionKeyResponse{
+		Success: true,
+		Key: encryptionKeyToProto(*response),
+	}, nil
+}
+
+func (s *AuthServiceServer) ListEncryptionKeys(ctx context.Context, req *authpb.ListEncryptionKeysRequest) (*authpb.ListEncryptionKeysResponse, error) {
+	response, err := s.controller.ListEncryptionKeys(ctx, dtos.ListEncryptionKeysDTO{
+		InUseOnly: req.GetInUseOnly(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*authpb.EncryptionKey, 0, len(response.Keys))
+	for _, key := range response.Keys {
+		keys = append(keys, encryptionKeyToProto(key))
+	}
+
+	return &authpb.ListEncryptionKeysResponse{
+		Success: true,
+		Keys: keys,
+	}, nil
+}
+
+func (s *AuthServiceServer) SetAudienceKey(ctx context.Context, req *authpb.SetAudienceKeyRequest) (*authpb.SetAudienceKeyResponse, error) {
+	response, err := s.controller.SetAudienceKey(ctx, dtos.SetAudienceKeyDTO{
+		Audience: req.GetAudience(),
+		PublicKey: req.GetPublicKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &authpb.SetAudienceKeyResponse{
+		Success: true,
+	}
+	if response != nil {
+		result.Key = &authpb.AudienceKey{
+			Audience: response.Audience,
+			KeyId: response.KeyID,
+			Algorithm: response.Algorithm,
+			CreatedAt: response.CreatedAt.Unix(),
+		}
+	}
+
+	return result, nil
+}
+
+func (s *AuthServiceServer) QueryAuditEvents(ctx context.Context, req *authpb.QueryAuditEventsRequest) (*authpb.QueryAuditEventsResponse, error) {
+	dto := dtos.QueryAuditEventsDTO{
+		UserID: req.GetUserId(),
+		Types: req.GetTypes(),
+		PageSize: int(req.GetPageSize()),
+		Cursor: req.GetCursor(),
+	}
+	if req.From != nil {
+		from := time.Unix(req.GetFrom(), 0)
+		dto.From = &from
+	}
+	if req.To != nil {
+		to := time.Unix(req.GetTo(), 0)
+		dto.To = &to
+	}
+
+	response, err := s.controller.QueryAuditEvents(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*authpb.AuditEvent, 0, len(response.Events))
+	for _, event := range response.Events {
+		events = append(events, auditEventToProto(event))
+	}
+
+	return &authpb.QueryAuditEventsResponse{
+		Success: true,
+		Events: events,
+		NextCursor: response.NextCursor,
+	}, nil
+}
+
+func (s *AuthServiceServer) VerifyAuditChain(ctx context.Context, req *authpb.VerifyAuditChainRequest) (*authpb.VerifyAuditChainResponse, error) {
+	dto := dtos.VerifyAuditChainDTO{}
+	if req.From != nil {
+		from := time.Unix(req.GetFrom(), 0)
+		dto.From = &from
+	}
+	if req.To != nil {
+		to := time.Unix(req.GetTo(), 0)
+		dto.To = &to
+	}
+
+	response, err := s.controller.VerifyAuditChain(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]*authpb.AuditChainProblem, 0, len(response.Problems))
+	for _, problem := range response.Problems {
+		problems = append(problems, &authpb.AuditChainProblem{
+			Sequence: problem.Sequence,
+			Kind: problem.Kind,
+			Message: problem.Message,
+		})
+	}
+
+	return &authpb.VerifyAuditChainResponse{
+		Success: true,
+		Valid: response.Valid,
+		FirstSequence: response.FirstSequence,
+		LastSequence: response.LastSequence,
+		EventsChecked: response.EventsChecked,
+		CheckpointsVerified: response.CheckpointsVerified,
+		Problems: problems,
+	}, nil
+}
+
+func (s *AuthServiceServer) ChangePassword(ctx context.Context, req *authpb.ChangePasswordRequest) (*authpb.ChangePasswordResponse, error) {
+	err := s.controller.ChangePassword(ctx, dtos.ChangePasswordDTO{
+		UserID: req.GetUserId(),
+		CurrentPassword: req.GetCurrentPassword(),
+		NewPassword: req.GetNewPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.ChangePasswordResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *AuthServiceServer) CreateWebhookSubscription(ctx context.Context, req *authpb.CreateWebhookSubscriptionRequest) (*authpb.CreateWebhookSubscriptionResponse, error) {
+	response, err := s.controller.CreateWebhookSubscription(ctx, dtos.CreateWebhookSubscriptionDTO{
+		URL: req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Secret: req.GetSecret(),
+		MaxAttempts: int(req.GetMaxAttempts()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authpb.CreateWebhookSubscriptionResponse{
+		Success: true,
+		Subscription: webhookSubscriptionToProto(*response),
+		Secret: response.Secret,
+	}, nil
+}
+
+func (s *AuthServiceServer) ListWebhookSubscriptions(ctx context.Context, req *authpb.ListWebhookSubscriptionsRequest) (*authpb.ListWebhookSubscriptionsResponse, error) {
+	response, err := s.controller.ListWebhookSubscriptions(ctx, dtos.ListWebhookSubscriptionsDTO{})
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*authpb.WebhookSubscription, 0, len(response.Subscriptions))
+	for _, subscription := range response.Subscriptions {
+		subscriptions = append(subscriptions, webhookSubscriptionToProto(subscription))
+	}
+
+	return &authpb.ListWebhookSubscriptionsResponse{
+		Success: true,
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+func (s *AuthServiceServer) DeleteWebhookSubscription(ctx context.Context, req *authpb.DeleteWebhookSubscriptionRequest) (*authpb.DeleteWebhookSubscriptionResponse, error) {
+	err := s.controller.DeleteWebhookSubscription(ctx, dtos.DeleteWebhookSubscriptionDTO{
+		ID: req.GetId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authpb.DeleteWebhookSubscriptionResponse{
+		Success: true,
+	}, nil
+}
+
+func (s *AuthServiceServer) ListWebhookDeliveries(ctx context.Context, req *authpb.ListWebhookDeliveriesRequest) (*authpb.ListWebhookDeliveriesResponse, error) {
+	response, err := s.controller.ListWebhookDeliveries(ctx, dtos.ListWebhookDeliveriesDTO{
+		SubscriptionID: req.GetSubscriptionId(),
+		Status: models.WebhookDeliveryStatus(req.GetStatus()),
+		PageSize: int(req.GetPageSize()),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*authpb.WebhookDelivery, 0, len(response.Deliveries))
+	for _, delivery := range response.Deliveries {
+		deliveries = append(deliveries, webhookDeliveryToProto(delivery))
+	}
+
+	return &authpb.ListWebhookDeliveriesResponse{
+		Success: true,
+		Deliveries: deliveries,
+		NextCursor: response.NextCursor,
+	}, nil
+}
+
+func (s *AuthServiceServer) RetryWebhookDelivery(ctx context.Context, req *authpb.RetryWebhookDeliveryRequest) (*authpb.RetryWebhookDeliveryResponse, error) {
+	response, err := s.controller.RetryWebhookDelivery(ctx, dtos.RetryWebhookDeliveryDTO{
+		DeliveryID: req.GetDeliveryId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authpb.RetryWebhookDeliveryResponse{
+		Success: true,
+		Delivery: webhookDeliveryToProto(*response),
+	}, nil
+}
+
+func webhookSubscriptionToProto(subscription dtos.WebhookSubscriptionDTO) *authpb.WebhookSubscription {
+	return &authpb.WebhookSubscription{
+		Id: subscription.ID,
+		Url: subscription.URL,
+		EventTypes: subscription.EventTypes,
+		MaxAttempts: int32(subscription.MaxAttempts),
+		CreatedAt: subscription.CreatedAt.Unix(),
+	}
+}
+
+func webhookDeliveryToProto(delivery dtos.WebhookDeliveryDTO) *authpb.WebhookDelivery {
+	message := &authpb.WebhookDelivery{
+		Id: delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventId: delivery.EventID,
+		EventType: delivery.EventType,
+		Status: string(delivery.Status),
+		Attempts: int32(delivery.Attempts),
+		NextAttemptAt: delivery.NextAttemptAt.Unix(),
+		LastStatusCode: int32(delivery.LastStatusCode),
+		LastError: delivery.LastError,
+		CreatedAt: delivery.CreatedAt.Unix(),
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := delivery.DeliveredAt.Unix()
+		message.DeliveredAt = &deliveredAt
+	}
+	return message
+}
+
+func (s *AuthServiceServer) WatchAuthEvents(req *authpb.WatchAuthEventsRequest, stream grpc.ServerStreamingServer[authpb.WatchAuthEventsResponse]) error {
+	// Streams never finish on their own, so they are ended when the server
+	// stops; clients resume from their cursor on another instance.
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := s.controller.WatchAuthEvents(ctx, dtos.WatchAuthEventsDTO{
+		Types: req.GetTypes(),
+		TenantID: req.GetTenantId(),
+		Cursor: req.GetCursor(),
+	}, func(event dtos.AuthEventDTO) error {
+		return stream.Send(&authpb.WatchAuthEventsResponse{
+			Event: auditEventToProto(event.Event),
+			TenantId: event.TenantID,
+			Cursor: event.Cursor,
+		})
+	})
+
+	select {
+	case <-s.stopping:
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return err
+	}
+}
+
+func signingKeyPurposeFromProto(purpose authpb.SigningKeyPurpose) models.SigningKeyPurpose {
+	switch purpose {
+	case authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_ACCESS:
+		return models.SigningKeyPurposeAccess
+	case authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_REFRESH:
+		return models.SigningKeyPurposeRefresh
+	case authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_AUDIT:
+		return models.SigningKeyPurposeAudit
+	default:
+		return ""
+	}
+}
+
+func signingKeyToProto(key dtos.SigningKeyDTO) *authpb.SigningKey {
+	purpose := authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_ACCESS
+	switch key.Purpose {
+	case models.SigningKeyPurposeRefresh:
+		purpose = authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_REFRESH
+	case models.SigningKeyPurposeAudit:
+		purpose = authpb.SigningKeyPurpose_SIGNING_KEY_PURPOSE_AUDIT
+	}
+
+	signingKey := &authpb.SigningKey{
+		KeyId: key.KeyID,
+		Purpose: purpose,
+		Algorithm: key.Algorithm,
+		State: string(key.State),
+		ActivatesAt: key.ActivatesAt.Unix(),
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+	if key.ExpiresAt != nil {
+		expiresAt := key.ExpiresAt.Unix()
+		signingKey.ExpiresAt = &expiresAt
+	}
+	if key.RemoveAt != nil {
+		removeAt := key.RemoveAt.Unix()
+		signingKey.RemoveAt = &removeAt
+	}
+
+	return signingKey
+}
+
+func encryptionKeyToProto(key dtos.EncryptionKeyDTO) *authpb.EncryptionKey {
+	encryptionKey := &authpb.EncryptionKey{
+		KeyId: key.KeyID,
+		Algorithm: key.Algorithm,
+		State: string(key.State),
+		InUse: key.InUse,
+		ActivatesAt: key.ActivatesAt.Unix(),
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+	if key.ExpiresAt != nil {
+		expiresAt := key.ExpiresAt.Unix()
+		encryptionKey.ExpiresAt = &expiresAt
+	}
+	if key.RemoveAt != nil {
+		removeAt := key.RemoveAt.Unix()
+		encryptionKey.RemoveAt = &removeAt
+	}
+	if key.LastUsedAt != nil {
+		lastUsedAt := key.LastUsedAt.Unix()
+		encryptionKey.LastUsedAt = &lastUsedAt
+	}
+
+	return encryptionKey
+}
+
+func auditEventToProto(event dtos.AuditEventDTO) *authpb.AuditEvent {
+	result := &authpb.AuditEvent{
+		Id: event.ID,
+		Type: event.Type,
+		ActorId: event.ActorID,
+		SubjectId: event.SubjectID,
+		ClientId: event.ClientID,
+		Outcome: event.Outcome,
+		Reason: event.Reason,
+		PeerIp: event.PeerIP,
+		UserAgent: event.UserAgent,
+		OccurredAt: event.OccurredAt.Unix(),
+	}
+	if len(event.Details) > 0 {
+		if details, err := json.Marshal(event.Details); err == nil {
+			result.DetailsJson = string(details)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// gracefulStop lets in-flight RPCs finish and stops the server outright when
+// they take longer than timeout or ctx allows. The timeout stays below the
+// fx stop timeout so the forced stop still runs inside it.
+func gracefulStop(ctx context.Context, logger *slog.Logger, grpcServer *grpc.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warn("gRPC requests still running at the shutdown deadline, stopping them")
+		grpcServer.Stop()
+		<-stopped
+	}
+
+	return nil
+}
+
+// shutdownHTTP is gracefulStop for the HTTP server.
+func shutdownHTTP(ctx context.Context, logger *slog.Logger, server *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		logger.Warn("HTTP requests still running at the shutdown deadline, closing them")
+		return server.Close()
+	}
+
+	return err
+}
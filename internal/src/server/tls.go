@@ -0,0 +1,174 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+)
+
+// tlsReloadInterval is how often the certificate files are checked for
+// changes, at most; the check happens on a handshake.
+const tlsReloadInterval = 10 * time.Second
+
+// grpcTLSConfig configures TLS for the gRPC listener:
+//   - the cert and key files hold the server certificate chain and key, in
+//     PEM. Without them the listener is plaintext.
+//   - the client CA file holds the CA bundle client certificates are
+//     verified against and turns on mutual TLS.
+//   - client auth is require (the default), which rejects clients without a
+//     valid certificate, or optional, which only verifies the certificates
+//     clients present.
+//
+// The files are reloaded when they change, so certificates can be renewed
+// without a restart; a renewal that fails to load leaves the previous
+// certificates in use.
+func grpcTLSConfig(cfg config.TLSConfig, logger *slog.Logger) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+
+	clientAuth := tls.NoClientCert
+	if cfg.ClientCAFile != "" {
+		switch mode := cfg.ClientAuth; mode {
+		case "", "require":
+			clientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			clientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("GRPC_TLS_CLIENT_AUTH must be require or optional, got %q", mode)
+		}
+	}
+
+	reloader := &certReloader{
+		certFile:     cfg.CertFile,
+		keyFile:      cfg.KeyFile,
+		clientCAFile: cfg.ClientCAFile,
+		clientAuth:   clientAuth,
+		logger:       logger,
+	}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: reloader.configForClient,
+	}, nil
+}
+
+// certReloader hands every handshake the current certificates, loading
+// them again when a file changed.
+type certReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTimes  []time.Time
+	checkedAt time.Time
+}
+
+func (r *certReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) >= tlsReloadInterval {
+		r.checkedAt = time.Now()
+
+		if modTimes, err := r.statFiles(); err != nil || !equalTimes(modTimes, r.modTimes) {
+			if err := r.loadLocked(); err != nil {
+				r.logger.Warn("Failed to reload TLS certificates, keeping the current ones", "error", err)
+			} else {
+				r.logger.Info("Reloaded TLS certificates")
+			}
+		}
+	}
+
+	return r.config, nil
+}
+
+func (r *certReloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkedAt = time.Now()
+	return r.loadLocked()
+}
+
+func (r *certReloader) loadLocked() error {
+	// Stat before reading, so a file replaced in between is read again on
+	// the next check.
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		NextProtos:   []string{"h2"},
+	}
+
+	if r.clientCAFile != "" {
+		bundle, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("client CA bundle %s has no PEM certificates", r.clientCAFile)
+		}
+
+		config.ClientCAs = pool
+		config.ClientAuth = r.clientAuth
+	}
+
+	r.config = config
+	r.modTimes = modTimes
+	return nil
+}
+
+func (r *certReloader) statFiles() ([]time.Time, error) {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/Gabriel-Schiestl/authgate/authpb"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/config"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/adapters"
+	"github.com/Gabriel-Schiestl/authgate/internal/src/infra/database/connection"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx/fxtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// emptyDriver is a database/sql driver whose queries succeed without rows,
+// enough for gorm to run and trace them without a database.
+type emptyDriver struct{}
+
+func (emptyDriver) Open(name string) (driver.Conn, error) { return emptyConn{}, nil }
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(query string) (driver.Stmt, error) { return emptyStmt{}, nil }
+func (emptyConn) Close() error                              { return nil }
+func (emptyConn) Begin() (driver.Tx, error)                 { return emptyTx{}, nil }
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error                                    { return nil }
+func (emptyStmt) NumInput() int                                   { return -1 }
+func (emptyStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (emptyStmt) Query(args []driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+type emptyTx struct{}
+
+func (emptyTx) Commit() error   { return nil }
+func (emptyTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return []string{"id"} }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("authgate-empty", emptyDriver{})
+}
+
+// verifyTokenServer answers VerifyToken with a query, the way the real
+// handlers reach the database through the request context.
+type verifyTokenServer struct {
+	authpb.UnimplementedAuthServiceServer
+	db *gorm.DB
+}
+
+func (s *verifyTokenServer) VerifyToken(ctx context.Context, req *authpb.VerifyTokenRequest) (*authpb.VerifyTokenResponse, error) {
+	var ids []string
+	if err := s.db.WithContext(ctx).Table("revoked_tokens").Where("token_id = ?", req.GetAccessToken()).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return &authpb.VerifyTokenResponse{Success: true}, nil
+}
+
+func TestRPCSpanParentsDatabaseSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	lc := fxtest.NewLifecycle(t)
+	provider, err := adapters.NewTracerProvider(lc, exporter)
+	if err != nil {
+		t.Fatalf("NewTracerProvider() error = %v", err)
+	}
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sqlDB, err := sql.Open("authgate-empty", "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	db, err := connection.Open(postgres.New(postgres.Config{Conn: sqlDB}), provider, logger)
+	if err != nil {
+		t.Fatalf("connection.Open() error = %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(serverOptions(logger, adapters.NewMetrics(adapters.NewMetricsRegistry()), newClientIdentities(config.GRPCConfig{}))...)
+	authpb.RegisterAuthServiceServer(grpcServer, &verifyTokenServer{db: db})
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := authpb.NewAuthServiceClient(conn).VerifyToken(context.Background(), &authpb.VerifyTokenRequest{AccessToken: "token-1"}); err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+
+	grpcServer.GracefulStop()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var serverSpan *tracetest.SpanStub
+	for i, span := range spans {
+		if span.Name == authpb.AuthService_VerifyToken_FullMethodName[1:] && span.SpanKind == trace.SpanKindServer {
+			serverSpan = &spans[i]
+		}
+	}
+	if serverSpan == nil {
+		t.Fatalf("no server span for VerifyToken among %d spans", len(spans))
+	}
+
+	var dbSpans int
+	for _, span := range spans {
+		if span.SpanKind != trace.SpanKindClient || span.SpanContext.TraceID() != serverSpan.SpanContext.TraceID() {
+			continue
+		}
+		dbSpans++
+		if span.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+			t.Errorf("span %q has parent %s, want the server span %s", span.Name, span.Parent.SpanID(), serverSpan.SpanContext.SpanID())
+		}
+	}
+	if dbSpans == 0 {
+		t.Errorf("no database span in the trace of the server span; spans: %v", spanNames(spans))
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	return names
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "context"
+
+// RequestInfo describes where a request came from. The gRPC and HTTP servers
+// attach it to the request context so it can be recorded without threading
+// it through every DTO.
+type RequestInfo struct {
+	PeerIP    string
+	UserAgent string
+}
+
+type requestInfoKey struct{}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// RunPeriodic runs task every interval for the lifetime of the application.
+// The first run happens one interval after start. On stop the context passed
+// to task is cancelled and the hook waits for the current run to finish, so
+// no task is cut off halfway through a write.
+func RunPeriodic(lc fx.Lifecycle, logger *slog.Logger, name string, interval time.Duration, task func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := task(ctx); err != nil && ctx.Err() == nil {
+							logger.ErrorContext(ctx, "Worker failed", "worker", name, "error", err)
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}